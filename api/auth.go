@@ -0,0 +1,97 @@
+package api
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+
+	"pivotaltojira/models"
+	"pivotaltojira/utils"
+)
+
+// AuthFailure は認証確認に失敗した原因の種類です
+type AuthFailure int
+
+const (
+	AuthFailureOther       AuthFailure = iota // 想定外のステータスやURLの誤りなど
+	AuthFailureCredentials                    // 401/403: 認証情報またはアカウントの問題
+	AuthFailureTransient                      // 5xx・429・タイムアウト・接続エラー: 一時的な問題
+)
+
+// 一時的なエラーで認証確認を再試行する回数と、最初の待機時間（再試行ごとに倍にする）
+var (
+	authRetryAttempts  = 3
+	authRetryBaseDelay = time.Second
+)
+
+// ClassifyAuthError は認証確認のエラーを原因の種類に分類します
+func ClassifyAuthError(err error) AuthFailure {
+	if err == nil {
+		return AuthFailureOther
+	}
+
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		switch {
+		case apiErr.StatusCode == http.StatusUnauthorized || apiErr.StatusCode == http.StatusForbidden:
+			return AuthFailureCredentials
+		case apiErr.StatusCode >= 500 || apiErr.StatusCode == http.StatusTooManyRequests:
+			return AuthFailureTransient
+		}
+		return AuthFailureOther
+	}
+
+	// 証明書のエラーは再試行しても解消しない
+	var certErr *tls.CertificateVerificationError
+	var authorityErr x509.UnknownAuthorityError
+	var hostnameErr x509.HostnameError
+	if errors.As(err, &certErr) || errors.As(err, &authorityErr) || errors.As(err, &hostnameErr) {
+		return AuthFailureOther
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return AuthFailureTransient
+	}
+	return AuthFailureOther
+}
+
+// AuthErrorHint は認証確認のエラーの種類に応じた対処方法を返します
+func AuthErrorHint(err error) string {
+	switch ClassifyAuthError(err) {
+	case AuthFailureCredentials:
+		return utils.T("auth.hint_credentials")
+	case AuthFailureTransient:
+		return utils.T("auth.hint_transient")
+	}
+	if status := StatusCode(err); status != 0 {
+		return utils.T("auth.hint_status", status)
+	}
+	return utils.T("auth.hint_url")
+}
+
+// VerifyAuth は認証済みユーザーの情報を取得します
+// サーバーエラーやタイムアウトなど一時的なエラーの場合は、待機時間を倍にしながら再試行します
+// 401/403 など認証情報の問題は再試行しません
+func (j *JiraClient) VerifyAuth() (*models.JiraUser, error) {
+	delay := authRetryBaseDelay
+	for attempt := 0; ; attempt++ {
+		user, err := j.WhoAmI()
+		if err == nil || attempt >= authRetryAttempts || ClassifyAuthError(err) != AuthFailureTransient {
+			return user, err
+		}
+
+		if !j.limits.useRetry() {
+			return nil, fmt.Errorf("%w: %w", ErrRetryBudgetExhausted, err)
+		}
+		utils.LogWarn("JIRAに一時的に接続できません。%s 後に再試行します (%d/%d): %v", delay, attempt+1, authRetryAttempts, err)
+		if err := j.limits.wait(delay); err != nil {
+			return nil, err
+		}
+		delay *= 2
+	}
+}
@@ -0,0 +1,115 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"pivotaltojira/utils"
+)
+
+// shortAuthRetryDelay はテストの間だけ認証確認の再試行の待機時間を短くします
+func shortAuthRetryDelay(t *testing.T) {
+	t.Helper()
+	saved := authRetryBaseDelay
+	authRetryBaseDelay = time.Millisecond
+	t.Cleanup(func() { authRetryBaseDelay = saved })
+}
+
+// errorTransport はリクエストを送らずに err を返す http.RoundTripper です（タイムアウトや接続エラーの再現用）
+type errorTransport struct {
+	err error
+}
+
+func (t errorTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	return nil, t.err
+}
+
+func TestVerifyAuthStatusClasses(t *testing.T) {
+	shortAuthRetryDelay(t)
+
+	tests := []struct {
+		status       int
+		wantClass    AuthFailure
+		wantRequests int
+		wantHint     string
+	}{
+		{http.StatusUnauthorized, AuthFailureCredentials, 1, utils.T("auth.hint_credentials")},
+		{http.StatusForbidden, AuthFailureCredentials, 1, utils.T("auth.hint_credentials")},
+		{http.StatusInternalServerError, AuthFailureTransient, authRetryAttempts + 1, utils.T("auth.hint_transient")},
+		{http.StatusServiceUnavailable, AuthFailureTransient, authRetryAttempts + 1, utils.T("auth.hint_transient")},
+		{http.StatusNotFound, AuthFailureOther, 1, utils.T("auth.hint_status", http.StatusNotFound)},
+		{http.StatusBadRequest, AuthFailureOther, 1, utils.T("auth.hint_status", http.StatusBadRequest)},
+	}
+
+	for _, tt := range tests {
+		t.Run(http.StatusText(tt.status), func(t *testing.T) {
+			rec := &requestRecorder{responses: []func(http.ResponseWriter){
+				reply(tt.status, `{"errorMessages":["エラー"]}`),
+			}}
+			client := newTestClient(t, rec)
+
+			_, err := client.VerifyAuth()
+			if err == nil {
+				t.Fatal("VerifyAuth がエラーを返しませんでした")
+			}
+			var apiErr *APIError
+			if !errors.As(err, &apiErr) || apiErr.StatusCode != tt.status {
+				t.Errorf("err = %v, want HTTP %d の APIError", err, tt.status)
+			}
+			if got := ClassifyAuthError(err); got != tt.wantClass {
+				t.Errorf("ClassifyAuthError = %d, want %d", got, tt.wantClass)
+			}
+			if got := AuthErrorHint(err); got != tt.wantHint {
+				t.Errorf("AuthErrorHint = %q, want %q", got, tt.wantHint)
+			}
+			// 一時的なエラーだけを再試行する
+			if got := len(rec.recorded()); got != tt.wantRequests {
+				t.Errorf("リクエスト数 = %d, want %d", got, tt.wantRequests)
+			}
+		})
+	}
+}
+
+func TestVerifyAuthTimeout(t *testing.T) {
+	shortAuthRetryDelay(t)
+	client := newTestClient(t, http.NotFoundHandler())
+	client.client = &http.Client{Transport: errorTransport{err: context.DeadlineExceeded}}
+
+	_, err := client.VerifyAuth()
+	if err == nil {
+		t.Fatal("VerifyAuth がエラーを返しませんでした")
+	}
+	if got := ClassifyAuthError(err); got != AuthFailureTransient {
+		t.Errorf("ClassifyAuthError = %d, want AuthFailureTransient", got)
+	}
+	if got := AuthErrorHint(err); got != utils.T("auth.hint_transient") {
+		t.Errorf("AuthErrorHint = %q, want 一時的なエラーの対処方法", got)
+	}
+	if strings.Contains(AuthErrorHint(err), "JIRA_API_TOKEN") {
+		t.Error("タイムアウトで認証情報の確認を案内しています")
+	}
+}
+
+func TestVerifyAuthRecoversFromTransientError(t *testing.T) {
+	shortAuthRetryDelay(t)
+	rec := &requestRecorder{responses: []func(http.ResponseWriter){
+		reply(http.StatusServiceUnavailable, ""),
+		reply(http.StatusOK, `{"accountId":"tester","displayName":"Tester"}`),
+	}}
+	client := newTestClient(t, rec)
+
+	user, err := client.VerifyAuth()
+	if err != nil {
+		t.Fatalf("VerifyAuth がエラーを返しました: %v", err)
+	}
+	if user.DisplayName != "Tester" {
+		t.Errorf("DisplayName = %q, want Tester", user.DisplayName)
+	}
+	if got := len(rec.recorded()); got != 2 {
+		t.Errorf("リクエスト数 = %d, want 2", got)
+	}
+}
@@ -0,0 +1,82 @@
+package api
+
+import (
+	"sync"
+
+	"pivotaltojira/utils"
+)
+
+// adaptiveConcurrency はAIMD（加算増加・乗算減少）で同時に送信するリクエスト数を調整します
+// 並列数の上限まで使われている間は成功するごとに少しずつ増やし（並列数分の成功でおよそ1増える）、429が続く場合は半分に減らします
+// 同じ混雑で同時に返ってきた429で何度も半減しないよう、半減した時点で送信中だったリクエストの結果では再度調整しません
+// 並列数は1以上 MAX_CONCURRENT 以下に保ちます
+type adaptiveConcurrency struct {
+	mu       sync.Mutex
+	cond     *sync.Cond
+	limit    float64
+	max      float64
+	inFlight int
+	stale    int // 前回の半減の時点で送信中だったリクエストのうち未完了の数
+}
+
+// newAdaptiveConcurrency は並列数 1 から始めて maxConcurrent まで調整するコントローラーを作成します
+// enabled が false の場合は nil（調整なし）を返します
+func newAdaptiveConcurrency(enabled bool, maxConcurrent int) *adaptiveConcurrency {
+	if !enabled {
+		return nil
+	}
+	c := &adaptiveConcurrency{limit: 1, max: float64(max(maxConcurrent, 1))}
+	c.cond = sync.NewCond(&c.mu)
+	return c
+}
+
+// Acquire は送信中のリクエストが現在の並列数未満になるまで待機します
+func (c *adaptiveConcurrency) Acquire() {
+	if c == nil {
+		return
+	}
+
+	c.mu.Lock()
+	for c.inFlight >= int(c.limit) {
+		c.cond.Wait()
+	}
+	c.inFlight++
+	c.mu.Unlock()
+}
+
+// Release はリクエストの完了を記録し、結果に応じて並列数を調整します
+// throttled はレート制限(429)が返されたかどうかです
+func (c *adaptiveConcurrency) Release(throttled bool) {
+	if c == nil {
+		return
+	}
+
+	c.mu.Lock()
+	// 上限まで使われていないときに増やすと、実際には試していない並列数まで上がってしまう
+	saturated := c.inFlight >= int(c.limit)
+	c.inFlight--
+	if c.stale > 0 {
+		// 半減前の並列数で送信したリクエストの結果は、現在の並列数の判断に使わない
+		c.stale--
+	} else if throttled {
+		c.limit = max(c.limit/2, 1)
+		c.stale = c.inFlight
+		utils.LogWarn("レート制限のため並列数を %d に減らします", int(c.limit))
+	} else if saturated {
+		c.limit = min(c.limit+1/c.limit, c.max)
+	}
+	c.mu.Unlock()
+
+	c.cond.Broadcast()
+}
+
+// Limit は現在の並列数を返します
+func (c *adaptiveConcurrency) Limit() int {
+	if c == nil {
+		return 0
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return int(c.limit)
+}
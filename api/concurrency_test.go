@@ -0,0 +1,151 @@
+package api
+
+import (
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestAdaptiveConcurrencyAIMD(t *testing.T) {
+	c := newAdaptiveConcurrency(true, 8)
+
+	// 上限まで使われている間は成功するごとに増え、MAX_CONCURRENT で止まる
+	for range 100 {
+		n := c.Limit()
+		for range n {
+			c.Acquire()
+		}
+		for range n {
+			c.Release(false)
+		}
+	}
+	if got := c.Limit(); got != 8 {
+		t.Fatalf("成功が続いた後の並列数 = %d, want 8", got)
+	}
+
+	// 同時に返ってきた429では1回だけ半減する
+	for range 8 {
+		c.Acquire()
+	}
+	for range 8 {
+		c.Release(true)
+	}
+	if got := c.Limit(); got != 4 {
+		t.Fatalf("429の後の並列数 = %d, want 4", got)
+	}
+
+	// 429が続いても1未満にはならない
+	for range 20 {
+		c.Acquire()
+		c.Release(true)
+	}
+	if got := c.Limit(); got != 1 {
+		t.Errorf("429が続いた後の並列数 = %d, want 1", got)
+	}
+}
+
+func TestAdaptiveConcurrencyDoesNotGrowWhenIdle(t *testing.T) {
+	c := newAdaptiveConcurrency(true, 8)
+	c.Acquire()
+	c.Release(false)
+	c.Acquire()
+	c.Release(false)
+	for range 2 {
+		c.Acquire()
+	}
+	for range 2 {
+		c.Release(false)
+	}
+	before := c.Limit()
+
+	// 上限より少ない並列数でしか送っていない間は増やさない
+	for range 50 {
+		c.Acquire()
+		c.Release(false)
+	}
+	if got := c.Limit(); got != before {
+		t.Errorf("並列数 = %d, want %d", got, before)
+	}
+}
+
+// throttlingServer は同時に処理中のリクエストが threshold を超えると429を返す、レート制限のあるJIRAの代わりです
+type throttlingServer struct {
+	threshold int64
+	inFlight  atomic.Int64
+	peak      atomic.Int64
+	throttled atomic.Int64
+	created   atomic.Int64
+}
+
+func (s *throttlingServer) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	n := s.inFlight.Add(1)
+	defer s.inFlight.Add(-1)
+	for {
+		peak := s.peak.Load()
+		if n <= peak || s.peak.CompareAndSwap(peak, n) {
+			break
+		}
+	}
+
+	time.Sleep(2 * time.Millisecond)
+	if n > s.threshold {
+		s.throttled.Add(1)
+		reply(http.StatusTooManyRequests, `{"errorMessages":["Rate limit exceeded"]}`)(w)
+		return
+	}
+	s.created.Add(1)
+	reply(http.StatusCreated, `{"key":"TEST-1"}`)(w)
+}
+
+func TestAutoTuneConcurrencySimulation(t *testing.T) {
+	shortRetryDelay(t)
+	t.Setenv("AUTO_TUNE_CONCURRENCY", "true")
+	t.Setenv("MAX_CONCURRENT", "16")
+	server := &throttlingServer{threshold: 4}
+	client := newTestClient(t, server)
+
+	// MAX_CONCURRENT と同じ数の goroutine から送り続ける
+	const workers, perWorker = 16, 40
+	var wg sync.WaitGroup
+	var failed atomic.Int64
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range perWorker {
+				if _, err := client.CreateIssue("タイトル", "", nil, "Story", "", ""); err != nil {
+					failed.Add(1)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	tuned := client.TunedConcurrency()
+	t.Logf("調整後の並列数: %d, 最大同時リクエスト数: %d, 429: %d, 失敗: %d",
+		tuned, server.peak.Load(), server.throttled.Load(), failed.Load())
+
+	// 成功が続くと1から増え、429が続くと閾値付近まで下がる
+	if server.peak.Load() <= 1 {
+		t.Errorf("並列数が1から増えていません")
+	}
+	if tuned < 1 || tuned > int(server.threshold)+1 {
+		t.Errorf("調整後の並列数 = %d, want 1..%d", tuned, server.threshold+1)
+	}
+	// 並列数を調整しない場合は goroutine の数だけ同時に送るため、ほとんどが429になる
+	if throttled := server.throttled.Load(); throttled > workers*perWorker/4 {
+		t.Errorf("429の数 = %d。並列数が抑えられていません", throttled)
+	}
+	if server.created.Load()+failed.Load() != workers*perWorker {
+		t.Errorf("作成 %d 件と失敗 %d 件の合計がリクエスト数 %d と一致しません", server.created.Load(), failed.Load(), workers*perWorker)
+	}
+}
+
+func TestAutoTuneConcurrencyDisabled(t *testing.T) {
+	client := newTestClient(t, newJiraStub())
+	if got := client.TunedConcurrency(); got != 0 {
+		t.Errorf("AUTO_TUNE_CONCURRENCY が無効な場合の TunedConcurrency() = %d, want 0", got)
+	}
+}
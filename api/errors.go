@@ -0,0 +1,127 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// APIError はJIRA APIがエラーステータスを返した場合のエラーです
+// 呼び出し側は errors.As で取り出してステータスコードごとに処理を分けられます
+type APIError struct {
+	StatusCode    int               // HTTPステータスコード
+	Endpoint      string            // リクエストしたメソッドとパス (例: POST /rest/api/2/issue)
+	ErrorMessages []string          // JIRAの errorMessages
+	Errors        map[string]string // JIRAの errors（フィールドID → メッセージ）
+	FieldNames    map[string]string // フィールドID → フィールド名（分かる場合のみ）
+	Body          string            // JSONとして解析できなかった場合のレスポンスボディ
+	Hint          string            // よくある原因が分かる場合の対処方法
+}
+
+// jiraErrorBody はJIRAのエラーレスポンスの形式です
+type jiraErrorBody struct {
+	ErrorMessages []string          `json:"errorMessages"`
+	Errors        map[string]string `json:"errors"`
+}
+
+// Error はステータスコード、エンドポイントとJIRAのエラーメッセージをまとめた文字列を返します
+func (e *APIError) Error() string {
+	details := append([]string(nil), e.ErrorMessages...)
+
+	fields := make([]string, 0, len(e.Errors))
+	for field := range e.Errors {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+	for _, field := range fields {
+		details = append(details, fmt.Sprintf("%s: %s", e.describeField(field), e.Errors[field]))
+	}
+
+	if len(details) == 0 && e.Body != "" {
+		details = append(details, e.Body)
+	}
+
+	msg := fmt.Sprintf("HTTP %d (%s)", e.StatusCode, e.Endpoint)
+	if len(details) > 0 {
+		msg += ": " + strings.Join(details, "; ")
+	}
+	if e.Hint != "" {
+		msg += "（" + e.Hint + "）"
+	}
+	return msg
+}
+
+// describeField はフィールドを "field 'Story Points' (customfield_10016)" の形式で表します
+func (e *APIError) describeField(field string) string {
+	if name, ok := e.FieldNames[field]; ok && name != field {
+		return fmt.Sprintf("field '%s' (%s)", name, field)
+	}
+	return fmt.Sprintf("field '%s'", field)
+}
+
+// newAPIError はレスポンスのボディを読み取ってAPIErrorを作成します
+func newAPIError(resp *http.Response) *APIError {
+	body, _ := io.ReadAll(resp.Body)
+	apiErr := &APIError{StatusCode: resp.StatusCode}
+	if resp.Request != nil && resp.Request.URL != nil {
+		apiErr.Endpoint = resp.Request.Method + " " + resp.Request.URL.Path
+	}
+
+	var parsed jiraErrorBody
+	if err := json.Unmarshal(body, &parsed); err == nil && (len(parsed.ErrorMessages) > 0 || len(parsed.Errors) > 0) {
+		apiErr.ErrorMessages = parsed.ErrorMessages
+		apiErr.Errors = parsed.Errors
+	} else {
+		apiErr.Body = strings.TrimSpace(string(body))
+	}
+	apiErr.Hint = apiErr.interpret()
+	return apiErr
+}
+
+// interpret はエラーの内容からよくある原因を判定し、対処方法を返します（分からない場合は空文字）
+func (e *APIError) interpret() string {
+	if e.isSubtaskWithoutParent() {
+		return "サブタスクのイシュータイプには親イシューが必要です。イシュータイプのマッピングがサブタスクを指していないか確認してください"
+	}
+	return ""
+}
+
+// isSubtaskWithoutParent はサブタスクのイシュータイプを親なしで作成しようとしたエラーかどうかを返します
+// 例: errors.issuetype = "Issue type is a sub-task but parent issue key or id not specified."
+func (e *APIError) isSubtaskWithoutParent() bool {
+	if e.StatusCode != http.StatusBadRequest {
+		return false
+	}
+	messages := append([]string(nil), e.ErrorMessages...)
+	for _, field := range []string{"issuetype", "parent"} {
+		if msg, ok := e.Errors[field]; ok {
+			messages = append(messages, msg)
+		}
+	}
+	for _, msg := range messages {
+		lower := strings.ToLower(msg)
+		if (strings.Contains(lower, "sub-task") || strings.Contains(lower, "subtask")) && strings.Contains(lower, "parent") {
+			return true
+		}
+	}
+	return false
+}
+
+// IsSubtaskWithoutParent はエラーがサブタスクを親なしで作成しようとしたことによるものかを返します
+func IsSubtaskWithoutParent(err error) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && apiErr.isSubtaskWithoutParent()
+}
+
+// StatusCode はエラーにAPIErrorが含まれていればそのステータスコードを返します（含まれていない場合は0）
+func StatusCode(err error) int {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.StatusCode
+	}
+	return 0
+}
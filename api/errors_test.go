@@ -0,0 +1,103 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// errorResponse は method と path へのリクエストに status と body を返したレスポンスです
+func errorResponse(method, path string, status int, body string) *http.Response {
+	rec := httptest.NewRecorder()
+	rec.WriteHeader(status)
+	io.WriteString(rec, body)
+	resp := rec.Result()
+	resp.Request = httptest.NewRequest(method, "https://jira.example.test"+path, nil)
+	return resp
+}
+
+func TestNewAPIErrorParsesJiraBody(t *testing.T) {
+	resp := errorResponse("POST", "/rest/api/2/issue", http.StatusBadRequest,
+		`{"errorMessages":["Issue could not be created.","Check the fields."],"errors":{"summary":"You must specify a summary of the issue.","customfield_10016":"Field 'customfield_10016' cannot be set."}}`)
+
+	apiErr := newAPIError(resp)
+	if apiErr.StatusCode != http.StatusBadRequest || apiErr.Endpoint != "POST /rest/api/2/issue" {
+		t.Errorf("StatusCode, Endpoint = %d, %q", apiErr.StatusCode, apiErr.Endpoint)
+	}
+	if want := []string{"Issue could not be created.", "Check the fields."}; !reflect.DeepEqual(apiErr.ErrorMessages, want) {
+		t.Errorf("ErrorMessages = %q, want %q", apiErr.ErrorMessages, want)
+	}
+	wantErrors := map[string]string{
+		"summary":           "You must specify a summary of the issue.",
+		"customfield_10016": "Field 'customfield_10016' cannot be set.",
+	}
+	if !reflect.DeepEqual(apiErr.Errors, wantErrors) {
+		t.Errorf("Errors = %q, want %q", apiErr.Errors, wantErrors)
+	}
+	if apiErr.Body != "" {
+		t.Errorf("解析できたボディは Body に残さないはずです: %q", apiErr.Body)
+	}
+
+	// メッセージ、フィールド（ID順）の順に並べる
+	want := "HTTP 400 (POST /rest/api/2/issue): Issue could not be created.; Check the fields.; " +
+		"field 'customfield_10016': Field 'customfield_10016' cannot be set.; field 'summary': You must specify a summary of the issue."
+	if got := apiErr.Error(); got != want {
+		t.Errorf("Error() = %q\nwant %q", got, want)
+	}
+}
+
+func TestNewAPIErrorKeepsUnparsedBody(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want string
+	}{
+		{"HTML", "<html><body>Bad Gateway</body></html>\n", "HTTP 502 (GET /rest/api/2/myself): <html><body>Bad Gateway</body></html>"},
+		{"空のJIRAエラー", `{"errorMessages":[],"errors":{}}`, `HTTP 502 (GET /rest/api/2/myself): {"errorMessages":[],"errors":{}}`},
+		{"空のボディ", "", "HTTP 502 (GET /rest/api/2/myself)"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			apiErr := newAPIError(errorResponse("GET", "/rest/api/2/myself", http.StatusBadGateway, tt.body))
+			if got := apiErr.Error(); got != tt.want {
+				t.Errorf("Error() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAPIErrorAs(t *testing.T) {
+	apiErr := newAPIError(errorResponse("GET", "/rest/api/2/issue/TEST-1", http.StatusNotFound,
+		`{"errorMessages":["Issue does not exist or you do not have permission to see it."],"errors":{}}`))
+	err := fmt.Errorf("既存イシュー取得エラー: %w", fmt.Errorf("イシュー取得失敗: %w", apiErr))
+
+	var got *APIError
+	if !errors.As(err, &got) || got != apiErr {
+		t.Fatalf("errors.As で APIError を取り出せません: %v", err)
+	}
+	if StatusCode(err) != http.StatusNotFound {
+		t.Errorf("StatusCode(err) = %d, want 404", StatusCode(err))
+	}
+	if StatusCode(errors.New("通信エラー")) != 0 {
+		t.Error("APIError を含まないエラーの StatusCode は0になるべきです")
+	}
+	if !strings.Contains(err.Error(), "Issue does not exist") {
+		t.Errorf("ラップしたエラーにJIRAのメッセージが含まれません: %v", err)
+	}
+}
+
+func TestAPIErrorSubtaskHint(t *testing.T) {
+	apiErr := newAPIError(errorResponse("POST", "/rest/api/2/issue", http.StatusBadRequest,
+		`{"errorMessages":[],"errors":{"issuetype":"Issue type is a sub-task but parent issue key or id not specified."}}`))
+	if apiErr.Hint == "" || !strings.HasSuffix(apiErr.Error(), "（"+apiErr.Hint+"）") {
+		t.Errorf("サブタスクのエラーに対処方法が付きません: %v", apiErr)
+	}
+	if !IsSubtaskWithoutParent(fmt.Errorf("イシュー作成失敗: %w", apiErr)) {
+		t.Error("IsSubtaskWithoutParent = false, want true")
+	}
+}
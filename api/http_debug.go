@@ -0,0 +1,77 @@
+package api
+
+import (
+	"bytes"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+	"time"
+
+	"pivotaltojira/utils"
+)
+
+// maxLoggedBody はデバッグログに出力するボディの最大バイト数です
+const maxLoggedBody = 4096
+
+// logRequest は送信するリクエストのメソッド・URL・ボディをデバッグログに出力します
+// ボディは GetBody で複製して読むため、送信する内容には影響しません
+func logRequest(req *http.Request) {
+	body := ""
+	if isBinaryContent(req.Header.Get("Content-Type")) {
+		body = "(バイナリのため省略)"
+	} else if req.GetBody != nil {
+		if rc, err := req.GetBody(); err == nil {
+			data, _ := io.ReadAll(io.LimitReader(rc, maxLoggedBody+1))
+			rc.Close()
+			body = truncateBody(data)
+		}
+	}
+	utils.LogDebug("HTTP → %s %s %s", req.Method, req.URL, body)
+}
+
+// logResponse は受信したレスポンスのステータスとボディをデバッグログに出力します
+// ボディは読み取った後に同じ内容で差し替えるため、呼び出し元はそのまま読み取れます
+func logResponse(req *http.Request, resp *http.Response, err error, elapsed time.Duration) {
+	if err != nil {
+		utils.LogDebug("HTTP ← %s %s エラー (%s): %v", req.Method, req.URL, elapsed, err)
+		return
+	}
+
+	body := "(バイナリのため省略)"
+	if !isBinaryContent(resp.Header.Get("Content-Type")) {
+		data, readErr := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		resp.Body = io.NopCloser(bytes.NewReader(data))
+		if readErr != nil {
+			body = "(読み取りエラー: " + readErr.Error() + ")"
+		} else {
+			body = truncateBody(data)
+		}
+	}
+	utils.LogDebug("HTTP ← %s %s %d (%s) %s", req.Method, req.URL, resp.StatusCode, elapsed, body)
+}
+
+// isBinaryContent はボディをログに出力しない Content-Type（添付ファイルなど）かどうかを返します
+func isBinaryContent(contentType string) bool {
+	if contentType == "" {
+		return false
+	}
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	switch mediaType {
+	case "application/json", "application/xml", "application/x-www-form-urlencoded":
+		return false
+	}
+	return !strings.HasPrefix(mediaType, "text/")
+}
+
+// truncateBody はボディを最大 maxLoggedBody バイトまでの文字列にします
+func truncateBody(data []byte) string {
+	if len(data) > maxLoggedBody {
+		return string(data[:maxLoggedBody]) + "...(省略)"
+	}
+	return string(data)
+}
@@ -2,37 +2,354 @@ package api
 
 import (
 	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"mime/multipart"
 	"net/http"
+	"net/url"
 	"os"
 	"path/filepath"
+	"slices"
+	"sort"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
+	"unicode/utf8"
 
 	"pivotaltojira/config"
+	"pivotaltojira/models"
 	"pivotaltojira/utils"
 )
 
+// maxSummaryLength はJIRAが受け付けるサマリーの最大文字数です
+const maxSummaryLength = 255
+
 // JiraClient はJIRA APIとのやり取りを処理します
 type JiraClient struct {
-	config *config.Config
-	client *http.Client
+	config      *config.Config
+	client      *http.Client
+	apiBase     string // REST APIのベースURL (例: https://example.com/jira/rest/api/2)
+	agileBase   string // Agile APIのベースURL (例: https://example.com/jira/rest/agile/1.0)
+	limiter     *rateLimiter
+	concurrency *adaptiveConcurrency // AUTO_TUNE_CONCURRENCY が無効な場合は nil
+	httpNanos   atomic.Int64         // リクエストの送信から応答ヘッダー受信までの累計時間
+	limits      *runLimits           // 移行全体の期限と再試行の予算
+
+	// イシュータイプ・遷移元ステータスごとのトランジションキャッシュ
+	transitionCache      map[string]map[string]string
+	transitionFetches    map[string]chan struct{} // 取得中のキャッシュキー
+	transitionCallsSaved int64
+	cacheMutex           sync.Mutex
+
+	// フィールドID → フィールド名（エラーメッセージの表示用、初回のみ取得）
+	fieldNames     map[string]string
+	fieldNamesOnce sync.Once
 }
 
+// JIRAのREST APIのバージョン
+const (
+	restAPIVersion  = "2"
+	agileAPIVersion = "1.0"
+)
+
 // NewJiraClient は新しいJIRAクライアントを作成します
 func NewJiraClient(cfg *config.Config) *JiraClient {
+	return NewJiraClientWithClient(cfg, newHTTPClient(cfg))
+}
+
+// NewJiraClientWithClient は指定したHTTPクライアントを使うJIRAクライアントを作成します
+// テストで httptest.Server や独自の http.RoundTripper に差し替える場合に使用します
+// プロキシ・TLS・接続プールの設定は渡したクライアント側で行ってください
+func NewJiraClientWithClient(cfg *config.Config, client *http.Client) *JiraClient {
+	if client == nil {
+		client = newHTTPClient(cfg)
+	}
+	baseURL := cfg.JiraBaseURL()
 	return &JiraClient{
-		config: cfg,
-		client: &http.Client{},
+		config:            cfg,
+		client:            client,
+		apiBase:           baseURL + "/rest/api/" + restAPIVersion,
+		agileBase:         baseURL + "/rest/agile/" + agileAPIVersion,
+		limiter:           newRateLimiter(cfg.JiraRateLimit),
+		concurrency:       newAdaptiveConcurrency(cfg.AutoTuneConcurrency, cfg.MaxConcurrent),
+		limits:            newRunLimits(cfg.MigrationDeadline, cfg.RetryBudget),
+		transitionCache:   make(map[string]map[string]string),
+		transitionFetches: make(map[string]chan struct{}),
+	}
+}
+
+// newHTTPClient はプロキシとTLSの設定を反映したHTTPクライアントを作成します
+func newHTTPClient(cfg *config.Config) *http.Client {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+
+	// JIRA_PROXY が指定されていれば HTTP_PROXY/HTTPS_PROXY/NO_PROXY より優先する
+	transport.Proxy = http.ProxyFromEnvironment
+	if cfg.JiraProxy != "" {
+		if proxyURL, err := url.Parse(cfg.JiraProxy); err == nil {
+			transport.Proxy = http.ProxyURL(proxyURL)
+		} else {
+			utils.LogWarn("JIRA_PROXY の解析に失敗しました。環境変数のプロキシ設定を使用します: %v", err)
+		}
+	}
+
+	transport.TLSClientConfig = newTLSConfig(cfg)
+
+	// 並列数に合わせて接続をプールし、リクエストごとのハンドシェイクを避ける
+	maxIdle := cfg.MaxIdleConnsPerHost
+	if maxIdle <= 0 {
+		maxIdle = cfg.MaxConcurrent
+	}
+	maxConns := cfg.MaxConnsPerHost
+	if maxConns <= 0 {
+		maxConns = cfg.MaxConcurrent * 2
 	}
+	transport.MaxIdleConns = max(transport.MaxIdleConns, maxIdle)
+	transport.MaxIdleConnsPerHost = maxIdle
+	transport.MaxConnsPerHost = maxConns
+	transport.IdleConnTimeout = time.Duration(cfg.IdleConnTimeoutSec) * time.Second
+
+	return &http.Client{Transport: transport}
+}
+
+// newTLSConfig はCA証明書と証明書検証の設定を反映したTLS設定を作成します
+func newTLSConfig(cfg *config.Config) *tls.Config {
+	tlsConfig := &tls.Config{}
+
+	// 独自CAを信頼する（証明書検証のスキップより推奨）
+	if cfg.JiraCACert != "" {
+		pool, err := x509.SystemCertPool()
+		if err != nil || pool == nil {
+			pool = x509.NewCertPool()
+		}
+
+		pem, err := os.ReadFile(cfg.JiraCACert)
+		if err != nil {
+			utils.LogError("CA証明書の読み込みに失敗しました: %v", err)
+		} else if !pool.AppendCertsFromPEM(pem) {
+			utils.LogError("CA証明書の解析に失敗しました: %s", cfg.JiraCACert)
+		} else {
+			tlsConfig.RootCAs = pool
+			utils.LogInfo("CA証明書を読み込みました: %s", cfg.JiraCACert)
+		}
+	}
+
+	if cfg.JiraInsecureSkipVerify {
+		utils.LogWarn("==================================================================")
+		utils.LogWarn("TLS証明書の検証が無効になっています (JIRA_INSECURE_SKIP_VERIFY=true)")
+		utils.LogWarn("通信の安全性が保証されません。本番環境では使用しないでください。")
+		utils.LogWarn("可能であれば JIRA_CA_CERT でCA証明書を指定してください。")
+		utils.LogWarn("==================================================================")
+		tlsConfig.InsecureSkipVerify = true
+	}
+
+	return tlsConfig
 }
 
 // CheckAuth はJIRA認証をチェックします
 func (j *JiraClient) CheckAuth() error {
-	url := fmt.Sprintf("%s/rest/api/2/myself", j.config.JiraURL)
+	_, err := j.VerifyAuth()
+	return err
+}
+
+// WhoAmI は認証済みユーザーの情報を取得します
+func (j *JiraClient) WhoAmI() (*models.JiraUser, error) {
+	url := fmt.Sprintf("%s/myself", j.apiBase)
+
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("リクエスト作成エラー: %w", err)
+	}
+
+	req.SetBasicAuth(j.config.JiraEmail, j.config.JiraAPIToken)
+
+	resp, err := j.retryOnRateLimit(req)
+	if err != nil {
+		return nil, fmt.Errorf("リクエスト送信エラー: %w", err)
+	}
+	defer resp.Body.Close()
+
+	// 401/403 以外（サーバーエラーなど）は認証情報の問題とは限らない
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return nil, fmt.Errorf("認証失敗: %w", newAPIError(resp))
+	default:
+		return nil, fmt.Errorf("ユーザー情報取得エラー: %w", newAPIError(resp))
+	}
+
+	var user models.JiraUser
+	if err := json.NewDecoder(resp.Body).Decode(&user); err != nil {
+		return nil, fmt.Errorf("レスポンス解析エラー: %w", err)
+	}
+
+	return &user, nil
+}
+
+// CanCreateIssues は認証済みユーザーが対象プロジェクトでイシューを作成できるかを確認します
+func (j *JiraClient) CanCreateIssues() (bool, error) {
+	url := fmt.Sprintf("%s/mypermissions?permissions=CREATE_ISSUES&projectKey=%s",
+		j.apiBase, j.config.JiraProjectKey)
+
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return false, fmt.Errorf("リクエスト作成エラー: %w", err)
+	}
+
+	req.SetBasicAuth(j.config.JiraEmail, j.config.JiraAPIToken)
+
+	resp, err := j.retryOnRateLimit(req)
+	if err != nil {
+		return false, fmt.Errorf("リクエスト送信エラー: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return false, fmt.Errorf("権限確認失敗: %w", newAPIError(resp))
+	}
+
+	var result struct {
+		Permissions map[string]struct {
+			HavePermission bool `json:"havePermission"`
+		} `json:"permissions"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return false, fmt.Errorf("レスポンス解析エラー: %w", err)
+	}
+
+	return result.Permissions["CREATE_ISSUES"].HavePermission, nil
+}
+
+// GetCreateMeta は対象プロジェクトで作成できるイシュータイプと、その作成画面のフィールドを取得します
+// 戻り値のキーはイシュータイプ名です
+func (j *JiraClient) GetCreateMeta() (map[string]*models.IssueTypeMeta, error) {
+	url := fmt.Sprintf("%s/issue/createmeta?projectKeys=%s&expand=projects.issuetypes.fields",
+		j.apiBase, j.config.JiraProjectKey)
+
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("リクエスト作成エラー: %w", err)
+	}
+
+	req.SetBasicAuth(j.config.JiraEmail, j.config.JiraAPIToken)
+
+	resp, err := j.retryOnRateLimit(req)
+	if err != nil {
+		return nil, fmt.Errorf("リクエスト送信エラー: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("作成メタデータ取得失敗: %w", newAPIError(resp))
+	}
+
+	var result struct {
+		Projects []struct {
+			IssueTypes []struct {
+				ID     string `json:"id"`
+				Name   string `json:"name"`
+				Fields map[string]struct {
+					Name     string `json:"name"`
+					Required bool   `json:"required"`
+					Schema   struct {
+						Type string `json:"type"`
+					} `json:"schema"`
+				} `json:"fields"`
+			} `json:"issuetypes"`
+		} `json:"projects"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("レスポンス解析エラー: %w", err)
+	}
+
+	if len(result.Projects) == 0 {
+		return nil, fmt.Errorf("プロジェクト '%s' の作成メタデータが見つかりません", j.config.JiraProjectKey)
+	}
+
+	issueTypes := make(map[string]*models.IssueTypeMeta)
+	for _, it := range result.Projects[0].IssueTypes {
+		meta := &models.IssueTypeMeta{ID: it.ID, Name: it.Name, Fields: make(map[string]models.FieldMeta, len(it.Fields))}
+		for id, f := range it.Fields {
+			meta.Fields[id] = models.FieldMeta{Name: f.Name, Type: f.Schema.Type, Required: f.Required}
+		}
+		issueTypes[it.Name] = meta
+	}
+
+	return issueTypes, nil
+}
+
+// CheckStoryPointField はストーリーポイントのフィールドが作成画面にあり、数値型かを確認します
+// 見つからない場合は、作成画面にある数値のカスタムフィールドを候補としてエラーに含めます
+func CheckStoryPointField(issueTypes map[string]*models.IssueTypeMeta, fieldID string) error {
+	candidates := make(map[string]string)
+	for _, meta := range issueTypes {
+		if field, ok := meta.Fields[fieldID]; ok {
+			if field.Type != "number" {
+				return fmt.Errorf("ストーリーポイントのフィールド %s (%s) は数値型ではありません (型: %s)",
+					fieldID, field.Name, field.Type)
+			}
+			return nil
+		}
+		for id, field := range meta.Fields {
+			if strings.HasPrefix(id, "customfield_") && field.Type == "number" {
+				candidates[id] = field.Name
+			}
+		}
+	}
+
+	if len(candidates) == 0 {
+		return fmt.Errorf("ストーリーポイントのフィールド %s が作成画面にありません（数値のカスタムフィールドもありません）", fieldID)
+	}
+
+	list := make([]string, 0, len(candidates))
+	for id, name := range candidates {
+		list = append(list, fmt.Sprintf("%s (%s)", id, name))
+	}
+	sort.Strings(list)
+	return fmt.Errorf("ストーリーポイントのフィールド %s が作成画面にありません。利用可能な数値のカスタムフィールド: %s",
+		fieldID, strings.Join(list, ", "))
+}
+
+// CheckPivotalIDField はPivotal IDを保存するフィールドが作成画面にあり、文字列型かを確認します
+func CheckPivotalIDField(issueTypes map[string]*models.IssueTypeMeta, fieldID string) error {
+	for _, meta := range issueTypes {
+		if field, ok := meta.Fields[fieldID]; ok {
+			if field.Type != "string" {
+				return fmt.Errorf("Pivotal IDのフィールド %s (%s) は文字列型ではありません (型: %s)",
+					fieldID, field.Name, field.Type)
+			}
+			return nil
+		}
+	}
+	return fmt.Errorf("Pivotal IDのフィールド %s が作成画面にありません", fieldID)
+}
+
+// CheckDateField は日付を保存するフィールドが作成画面にあり、日付型 (date/datetime) かを確認して型を返します
+func CheckDateField(issueTypes map[string]*models.IssueTypeMeta, fieldID string) (string, error) {
+	for _, meta := range issueTypes {
+		if field, ok := meta.Fields[fieldID]; ok {
+			if field.Type != "date" && field.Type != "datetime" {
+				return "", fmt.Errorf("日付のフィールド %s (%s) は日付型ではありません (型: %s)",
+					fieldID, field.Name, field.Type)
+			}
+			return field.Type, nil
+		}
+	}
+	return "", fmt.Errorf("日付のフィールド %s が作成画面にありません", fieldID)
+}
+
+// VerifyProject は設定されたプロジェクトキーが存在しアクセス可能かを確認します
+func (j *JiraClient) VerifyProject() error {
+	if j.config.JiraProjectKey == "" {
+		return fmt.Errorf("プロジェクトキーが設定されていません (JIRA_PROJECT_KEY)")
+	}
+
+	url := fmt.Sprintf("%s/project/%s", j.apiBase, j.config.JiraProjectKey)
 
 	req, err := http.NewRequest("GET", url, nil)
 	if err != nil {
@@ -41,106 +358,920 @@ func (j *JiraClient) CheckAuth() error {
 
 	req.SetBasicAuth(j.config.JiraEmail, j.config.JiraAPIToken)
 
-	resp, err := j.retryOnRateLimit(req)
-	if err != nil {
-		return fmt.Errorf("リクエスト送信エラー: %w", err)
+	resp, err := j.retryOnRateLimit(req)
+	if err != nil {
+		return fmt.Errorf("リクエスト送信エラー: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusOK {
+		return nil
+	}
+
+	if resp.StatusCode != http.StatusNotFound {
+		return fmt.Errorf("プロジェクト確認失敗: %w", newAPIError(resp))
+	}
+
+	// 閲覧可能なプロジェクトがあれば候補として表示する
+	projects, err := j.ListProjects()
+	if err != nil || len(projects) == 0 {
+		return fmt.Errorf("プロジェクト '%s' が見つかりません", j.config.JiraProjectKey)
+	}
+
+	keys := make([]string, 0, len(projects))
+	for key, name := range projects {
+		keys = append(keys, fmt.Sprintf("%s (%s)", key, name))
+	}
+	sort.Strings(keys)
+
+	return fmt.Errorf("プロジェクト '%s' が見つかりません。利用可能なプロジェクト: %s",
+		j.config.JiraProjectKey, strings.Join(keys, ", "))
+}
+
+// ListProjects は閲覧可能なプロジェクトのキーと名前の一覧を取得します
+func (j *JiraClient) ListProjects() (map[string]string, error) {
+	url := fmt.Sprintf("%s/project", j.apiBase)
+
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("リクエスト作成エラー: %w", err)
+	}
+
+	req.SetBasicAuth(j.config.JiraEmail, j.config.JiraAPIToken)
+
+	resp, err := j.retryOnRateLimit(req)
+	if err != nil {
+		return nil, fmt.Errorf("リクエスト送信エラー: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("プロジェクト一覧取得失敗: %w", newAPIError(resp))
+	}
+
+	var result []struct {
+		Key  string `json:"key"`
+		Name string `json:"name"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("レスポンス解析エラー: %w", err)
+	}
+
+	projects := make(map[string]string, len(result))
+	for _, p := range result {
+		projects[p.Key] = p.Name
+	}
+
+	return projects, nil
+}
+
+// ListFields はJIRAのフィールドIDと名前の一覧を取得します
+func (j *JiraClient) ListFields() (map[string]string, error) {
+	url := fmt.Sprintf("%s/field", j.apiBase)
+
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("リクエスト作成エラー: %w", err)
+	}
+
+	req.SetBasicAuth(j.config.JiraEmail, j.config.JiraAPIToken)
+
+	resp, err := j.retryOnRateLimit(req)
+	if err != nil {
+		return nil, fmt.Errorf("リクエスト送信エラー: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("フィールド一覧取得失敗: %w", newAPIError(resp))
+	}
+
+	var result []struct {
+		ID   string `json:"id"`
+		Name string `json:"name"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("レスポンス解析エラー: %w", err)
+	}
+
+	fields := make(map[string]string, len(result))
+	for _, f := range result {
+		fields[f.ID] = f.Name
+	}
+
+	return fields, nil
+}
+
+// fieldError はフィールド単位のエラーを含むレスポンスから、フィールド名付きのAPIErrorを作成します
+// フィールド名の一覧は最初のエラー時に一度だけ取得し、取得できない場合はIDのみ表示します
+func (j *JiraClient) fieldError(resp *http.Response) *APIError {
+	apiErr := newAPIError(resp)
+	if len(apiErr.Errors) == 0 {
+		return apiErr
+	}
+
+	j.fieldNamesOnce.Do(func() {
+		names, err := j.ListFields()
+		if err != nil {
+			utils.LogDebug("フィールド名の取得に失敗しました: %v", err)
+			return
+		}
+		j.fieldNames = names
+	})
+	apiErr.FieldNames = j.fieldNames
+	return apiErr
+}
+
+// originalTitlePrefix は切り詰めたサマリーの元のタイトルを説明文の先頭に残す際の見出しです
+const originalTitlePrefix = "元のタイトル: "
+
+// NormalizeSummary はサマリーの改行と連続する空白を1つの空白にまとめ、JIRAの上限（255文字）を超える場合は切り詰めます
+// 切り詰めた場合は元のタイトルを説明文の先頭に追加し、truncated を true にします
+// 作成時と、既存イシューの更新・差分確認で同じサマリーと説明文になるよう、すべてこの関数で整形します
+func NormalizeSummary(summary, description string) (normalized, newDescription string, truncated bool) {
+	normalized = strings.Join(strings.Fields(summary), " ")
+	if utf8.RuneCountInString(normalized) <= maxSummaryLength {
+		return normalized, description, false
+	}
+	newDescription = originalTitlePrefix + normalized + "\n\n" + description
+	return string([]rune(normalized)[:maxSummaryLength-1]) + "…", newDescription, true
+}
+
+// CreateIssue はJIRAイシューを作成します
+func (j *JiraClient) CreateIssue(summary, description string, labels []string, issueType string, reporter string, assignee string) (string, error) {
+	return j.CreateIssueWithFields(summary, description, labels, issueType, reporter, assignee, nil)
+}
+
+// CreateIssueWithFields はストーリーポイントなどの追加フィールドを含めてJIRAイシューを作成します
+// 作成後に個別に更新するよりAPI呼び出しが少なく、途中で失敗して値が欠けることもありません
+func (j *JiraClient) CreateIssueWithFields(summary, description string, labels []string, issueType string, reporter string, assignee string, extraFields map[string]interface{}) (string, error) {
+	url := fmt.Sprintf("%s/issue", j.apiBase)
+
+	payload, err := j.BuildCreatePayload(summary, description, labels, issueType, reporter, assignee, extraFields)
+	if err != nil {
+		return "", err
+	}
+
+	payloadBytes, err := json.Marshal(payload)
+	if err != nil {
+		return "", fmt.Errorf("JSONエンコードエラー: %w", err)
+	}
+
+	req, err := http.NewRequest("POST", url, bytes.NewBuffer(payloadBytes))
+	if err != nil {
+		return "", fmt.Errorf("リクエスト作成エラー: %w", err)
+	}
+
+	req.SetBasicAuth(j.config.JiraEmail, j.config.JiraAPIToken)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := j.retryOnRateLimit(req)
+	if err != nil {
+		return "", fmt.Errorf("リクエスト送信エラー: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated {
+		return "", fmt.Errorf("イシュー作成失敗: %w", j.fieldError(resp))
+	}
+
+	var result map[string]interface{}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return "", fmt.Errorf("レスポンス解析エラー: %w", err)
+	}
+
+	issueKey, ok := result["key"].(string)
+	if !ok {
+		return "", fmt.Errorf("イシューキーが見つかりません")
+	}
+
+	return issueKey, nil
+}
+
+// issueFields は既存イシューの比較に使うフィールドです
+const issueFields = "summary,description,status,labels"
+
+// BuildCreatePayload はイシュー作成APIに送信するペイロード（{"fields": ...}）を作成します
+// サマリーの整形、担当者・報告者のマッピングを含み、APIは呼び出さないため送信内容の確認にも使えます
+func (j *JiraClient) BuildCreatePayload(summary, description string, labels []string, issueType string, reporter string, assignee string, extraFields map[string]interface{}) (map[string]interface{}, error) {
+	fields, err := j.buildCreatePayload(summary, description, labels, issueType, reporter, assignee)
+	if err != nil {
+		return nil, err
+	}
+
+	// 追加フィールドは基本のフィールドを上書きしない
+	for id, value := range extraFields {
+		if _, ok := fields[id]; !ok && value != nil {
+			fields[id] = value
+		}
+	}
+
+	return map[string]interface{}{
+		"fields": fields,
+	}, nil
+}
+
+// buildCreatePayload はイシュー作成時の基本のフィールド（サマリー・説明・ラベル・担当者・報告者など）を作成します
+// HTTP通信は行わず、サマリーの整形と担当者・報告者のマッピングまでを担当します
+func (j *JiraClient) buildCreatePayload(summary, description string, labels []string, issueType, reporter, assignee string) (map[string]interface{}, error) {
+	if strings.TrimSpace(issueType) == "" {
+		return nil, fmt.Errorf("イシュータイプが指定されていません")
+	}
+
+	// 改行と連続する空白をまとめ、JIRAのサマリー上限を超える場合は切り詰めて元のタイトルを説明文に残す
+	normalized, description, truncated := NormalizeSummary(summary, description)
+	if truncated {
+		utils.LogWarn("サマリーが %d 文字を超えるため切り詰めます: %s", maxSummaryLength, summary)
+	}
+	summary = normalized
+	if summary == "" {
+		return nil, fmt.Errorf("サマリーが空です")
+	}
+
+	// フィールドの作成
+	fields := map[string]interface{}{
+		"project":   map[string]string{"key": j.config.JiraProjectKey},
+		"summary":   summary,
+		"issuetype": map[string]string{"name": issueType},
+	}
+
+	// 任意フィールドは空の場合は送信しない（空の値を拒否する設定があるため）
+	if strings.TrimSpace(description) != "" {
+		fields["description"] = description
+	} else {
+		description = ""
+	}
+	if len(labels) > 0 {
+		fields["labels"] = labels
+	}
+
+	//　担当者と報告者が指定されている場合のマッピング対応
+	j.prepareUserFields(fields, assignee, reporter, description)
+
+	return fields, nil
+}
+
+// UpdateIssue は既存イシューのフィールドを更新します
+func (j *JiraClient) UpdateIssue(issueKey string, fields map[string]interface{}) error {
+	url := fmt.Sprintf("%s/issue/%s", j.apiBase, issueKey)
+
+	payload := map[string]interface{}{
+		"fields": fields,
+	}
+
+	payloadBytes, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("JSONエンコードエラー: %w", err)
+	}
+
+	req, err := http.NewRequest("PUT", url, bytes.NewBuffer(payloadBytes))
+	if err != nil {
+		return fmt.Errorf("リクエスト作成エラー: %w", err)
+	}
+
+	req.SetBasicAuth(j.config.JiraEmail, j.config.JiraAPIToken)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := j.retryOnRateLimit(req)
+	if err != nil {
+		return fmt.Errorf("リクエスト送信エラー: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusNoContent {
+		return fmt.Errorf("イシュー更新失敗: %w", j.fieldError(resp))
+	}
+
+	return nil
+}
+
+// GetIssue はイシューの現在の内容を取得します
+func (j *JiraClient) GetIssue(issueKey string) (*models.JiraIssue, error) {
+	url := fmt.Sprintf("%s/issue/%s?fields=%s", j.apiBase, issueKey, issueFields)
+
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("リクエスト作成エラー: %w", err)
+	}
+
+	req.SetBasicAuth(j.config.JiraEmail, j.config.JiraAPIToken)
+
+	resp, err := j.retryOnRateLimit(req)
+	if err != nil {
+		return nil, fmt.Errorf("リクエスト送信エラー: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("イシュー取得失敗: %w", newAPIError(resp))
+	}
+
+	var result issueResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("レスポンス解析エラー: %w", err)
+	}
+
+	return result.toModel(), nil
+}
+
+// GetAttachments はイシューに添付済みのファイルの一覧を取得します
+func (j *JiraClient) GetAttachments(issueKey string) ([]models.JiraAttachment, error) {
+	url := fmt.Sprintf("%s/issue/%s?fields=attachment", j.apiBase, issueKey)
+
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("リクエスト作成エラー: %w", err)
+	}
+
+	req.SetBasicAuth(j.config.JiraEmail, j.config.JiraAPIToken)
+
+	resp, err := j.retryOnRateLimit(req)
+	if err != nil {
+		return nil, fmt.Errorf("リクエスト送信エラー: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("添付ファイル取得失敗: %w", newAPIError(resp))
+	}
+
+	var result struct {
+		Fields struct {
+			Attachment []struct {
+				Filename string `json:"filename"`
+				Size     int64  `json:"size"`
+			} `json:"attachment"`
+		} `json:"fields"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("レスポンス解析エラー: %w", err)
+	}
+
+	attachments := make([]models.JiraAttachment, len(result.Fields.Attachment))
+	for i, attachment := range result.Fields.Attachment {
+		attachments[i] = models.JiraAttachment{Filename: attachment.Filename, Size: attachment.Size}
+	}
+	return attachments, nil
+}
+
+// CreateIssueIfAbsent はプロジェクト内に label のラベルが付いたイシューがあればそのキーを返し、なければイシューを作成します
+// created は新しく作成した場合に true、既存のイシューを返した場合に false になります
+// JIRAの検索インデックスへの反映には遅れがあるため、作成直後のイシューは見つからない場合があります
+func (j *JiraClient) CreateIssueIfAbsent(label, summary, description string, labels []string, issueType string, reporter string, assignee string, extraFields map[string]interface{}) (issueKey string, created bool, err error) {
+	jql := fmt.Sprintf(`project = "%s" AND labels = "%s"`, j.config.JiraProjectKey, label)
+	issues, err := j.SearchIssues(jql, 2)
+	if err != nil {
+		return "", false, fmt.Errorf("既存イシュー検索エラー: %w", err)
+	}
+
+	switch len(issues) {
+	case 0:
+	case 1:
+		return issues[0].Key, false, nil
+	default:
+		return "", false, fmt.Errorf("ラベル %s のイシューが複数あります", label)
+	}
+
+	issueKey, err = j.CreateIssueWithFields(summary, description, labels, issueType, reporter, assignee, extraFields)
+	if err != nil {
+		return "", false, err
+	}
+	return issueKey, true, nil
+}
+
+// SearchIssues はJQLでイシューを検索します
+func (j *JiraClient) SearchIssues(jql string, maxResults int) ([]*models.JiraIssue, error) {
+	endpoint := fmt.Sprintf("%s/search?jql=%s&fields=%s&maxResults=%d",
+		j.apiBase, url.QueryEscape(jql), issueFields, maxResults)
+
+	req, err := http.NewRequest("GET", endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("リクエスト作成エラー: %w", err)
+	}
+
+	req.SetBasicAuth(j.config.JiraEmail, j.config.JiraAPIToken)
+
+	resp, err := j.retryOnRateLimit(req)
+	if err != nil {
+		return nil, fmt.Errorf("リクエスト送信エラー: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("イシュー検索失敗: %w", newAPIError(resp))
+	}
+
+	var result struct {
+		Issues []issueResponse `json:"issues"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("レスポンス解析エラー: %w", err)
+	}
+
+	issues := make([]*models.JiraIssue, 0, len(result.Issues))
+	for _, issue := range result.Issues {
+		issues = append(issues, issue.toModel())
+	}
+
+	return issues, nil
+}
+
+// issueResponse はイシュー取得APIのレスポンスです
+type issueResponse struct {
+	Key    string `json:"key"`
+	Fields struct {
+		Summary     string   `json:"summary"`
+		Description string   `json:"description"`
+		Labels      []string `json:"labels"`
+		Status      struct {
+			Name string `json:"name"`
+		} `json:"status"`
+	} `json:"fields"`
+}
+
+// toModel はレスポンスをモデルに変換します
+func (r issueResponse) toModel() *models.JiraIssue {
+	return &models.JiraIssue{
+		Key:         r.Key,
+		Title:       r.Fields.Summary,
+		Description: r.Fields.Description,
+		Labels:      r.Fields.Labels,
+		Status:      r.Fields.Status.Name,
+	}
+}
+
+// UpdateStoryPoints はJIRAイシューのストーリーポイントを更新します（0.5 などの小数も数値として送信します）
+func (j *JiraClient) UpdateStoryPoints(issueKey string, storyPoints float64) error {
+	url := fmt.Sprintf("%s/issue/%s", j.apiBase, issueKey)
+
+	payload := map[string]interface{}{
+		"fields": map[string]interface{}{
+			j.config.StoryPointField: storyPoints,
+		},
+	}
+
+	payloadBytes, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("JSONエンコードエラー: %w", err)
+	}
+
+	req, err := http.NewRequest("PUT", url, bytes.NewBuffer(payloadBytes))
+	if err != nil {
+		return fmt.Errorf("リクエスト作成エラー: %w", err)
+	}
+
+	req.SetBasicAuth(j.config.JiraEmail, j.config.JiraAPIToken)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := j.retryOnRateLimit(req)
+	if err != nil {
+		return fmt.Errorf("リクエスト送信エラー: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusNoContent {
+		return fmt.Errorf("ストーリーポイント更新失敗: %w", j.fieldError(resp))
+	}
+
+	return nil
+}
+
+// ListVersions はプロジェクトのバージョン名とIDの一覧を取得します
+func (j *JiraClient) ListVersions() (map[string]string, error) {
+	url := fmt.Sprintf("%s/project/%s/versions", j.apiBase, j.config.JiraProjectKey)
+
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("リクエスト作成エラー: %w", err)
+	}
+
+	req.SetBasicAuth(j.config.JiraEmail, j.config.JiraAPIToken)
+
+	resp, err := j.retryOnRateLimit(req)
+	if err != nil {
+		return nil, fmt.Errorf("リクエスト送信エラー: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("バージョン一覧取得失敗: %w", newAPIError(resp))
+	}
+
+	var result []struct {
+		ID   string `json:"id"`
+		Name string `json:"name"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("レスポンス解析エラー: %w", err)
+	}
+
+	versions := make(map[string]string, len(result))
+	for _, v := range result {
+		versions[v.Name] = v.ID
+	}
+
+	return versions, nil
+}
+
+// CreateVersion はプロジェクトにバージョンを作成し、そのIDを返します
+func (j *JiraClient) CreateVersion(name string) (string, error) {
+	url := fmt.Sprintf("%s/version", j.apiBase)
+
+	payload := map[string]interface{}{
+		"name":    name,
+		"project": j.config.JiraProjectKey,
+	}
+
+	payloadBytes, err := json.Marshal(payload)
+	if err != nil {
+		return "", fmt.Errorf("JSONエンコードエラー: %w", err)
+	}
+
+	req, err := http.NewRequest("POST", url, bytes.NewBuffer(payloadBytes))
+	if err != nil {
+		return "", fmt.Errorf("リクエスト作成エラー: %w", err)
+	}
+
+	req.SetBasicAuth(j.config.JiraEmail, j.config.JiraAPIToken)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := j.retryOnRateLimit(req)
+	if err != nil {
+		return "", fmt.Errorf("リクエスト送信エラー: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated && resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("バージョン作成失敗: %w", j.fieldError(resp))
+	}
+
+	var result struct {
+		ID string `json:"id"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return "", fmt.Errorf("レスポンス解析エラー: %w", err)
+	}
+
+	return result.ID, nil
+}
+
+// SetFixVersions はイシューの修正バージョンを名前で設定します
+func (j *JiraClient) SetFixVersions(issueKey string, versions []string) error {
+	fixVersions := make([]map[string]string, 0, len(versions))
+	for _, name := range versions {
+		fixVersions = append(fixVersions, map[string]string{"name": name})
+	}
+	return j.UpdateIssue(issueKey, map[string]interface{}{"fixVersions": fixVersions})
+}
+
+// GetTransitions はイシューの利用可能なトランジションを取得します
+func (j *JiraClient) GetTransitions(issueKey string) (map[string]string, error) {
+	url := fmt.Sprintf("%s/issue/%s/transitions", j.apiBase, issueKey)
+
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("リクエスト作成エラー: %w", err)
+	}
+
+	req.SetBasicAuth(j.config.JiraEmail, j.config.JiraAPIToken)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := j.retryOnRateLimit(req)
+	if err != nil {
+		return nil, fmt.Errorf("リクエスト送信エラー: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("トランジション取得失敗: %w", newAPIError(resp))
+	}
+
+	var result map[string]interface{}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("レスポンス解析エラー: %w", err)
+	}
+
+	transitions, ok := result["transitions"].([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("トランジションが見つかりません")
+	}
+
+	transitionMap := make(map[string]string)
+
+	for _, t := range transitions {
+		transition, ok := t.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		id, ok := transition["id"].(string)
+		if !ok {
+			continue
+		}
+
+		to, ok := transition["to"].(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		name, ok := to["name"].(string)
+		if !ok {
+			continue
+		}
+
+		transitionMap[strings.ToLower(name)] = id
+	}
+
+	return transitionMap, nil
+}
+
+// UpdateStatus はJIRAイシューのステータスを更新します
+// TRANSITION_PATHS に経路が設定されている場合は中間ステータスを順に経由します
+func (j *JiraClient) UpdateStatus(issueKey, issueType, targetStatus string) error {
+	// 作成直後のイシューは初期ステータスから遷移する
+	return j.UpdateStatusFrom(issueKey, issueType, "", targetStatus)
+}
+
+// UpdateStatusFrom は現在のステータスが分かっている既存イシューのステータスを更新します
+// currentStatus が空の場合は作成直後の初期ステータスとして扱います
+func (j *JiraClient) UpdateStatusFrom(issueKey, issueType, currentStatus, targetStatus string) error {
+	if !j.config.NeedsTransition(targetStatus) {
+		utils.LogInfo("イシュー %s: '%s' ステータスはスキップします", issueKey, targetStatus)
+		return nil // 初期ステータス（SKIP_STATUSES）はスキップ
+	}
+
+	if currentStatus != "" && strings.EqualFold(currentStatus, targetStatus) {
+		return nil // 既に目的のステータス
+	}
+
+	steps := j.transitionSteps(targetStatus)
+
+	// 経路の途中に現在のステータスがあればそれ以降だけを辿る
+	for i, step := range steps {
+		if currentStatus != "" && strings.EqualFold(step, currentStatus) {
+			steps = steps[i+1:]
+			break
+		}
+	}
+
+	for _, step := range steps {
+		if err := j.walkTo(issueKey, issueType, currentStatus, step); err != nil {
+			return err
+		}
+		if !strings.EqualFold(step, targetStatus) {
+			utils.LogInfo("イシュー %s: 中間ステータス '%s' に遷移しました", issueKey, step)
+		}
+		currentStatus = step
+	}
+
+	return nil
+}
+
+// walkTo は目的のステータスへ遷移します
+// 直接遷移できない場合は、GetTransitions で分かったワークフローの遷移を幅優先探索し、最短の経路で中間ステータスを経由します
+// 経路がまだ分からない場合は、遷移の一覧を取得していない最も近いステータスへ遷移して探索を続けます
+// 遷移回数は MAX_TRANSITION_HOPS で制限し、到達できなかった場合はイシューを元のステータスに戻してからエラーを返します
+func (j *JiraClient) walkTo(issueKey, issueType, fromStatus, toStatus string) error {
+	transitionID, transitions, err := j.findTransition(issueKey, issueType, fromStatus, toStatus)
+	if err != nil {
+		return err
+	}
+	if transitionID != "" {
+		return j.doTransition(issueKey, transitionID, toStatus)
+	}
+
+	// 作成直後のイシューは初期ステータスの名前が分からないため、探索の前に取得する
+	start := strings.ToLower(fromStatus)
+	if start == "" {
+		issue, err := j.GetIssue(issueKey)
+		if err != nil {
+			return fmt.Errorf("現在のステータスの取得失敗: %w", err)
+		}
+		start = strings.ToLower(issue.Status)
+	}
+	target := strings.ToLower(toStatus)
+	if start == target {
+		return nil
+	}
+
+	graph := j.knownTransitions(issueType)
+	graph[start] = transitions
+
+	current := start
+	for hop := 1; hop <= j.config.MaxTransitionHops; hop++ {
+		path := shortestTransitionPath(graph, current, target)
+		if len(path) == 0 {
+			break
+		}
+		// 残りの遷移回数で到達できない経路は辿らない（探索の経路は到達までにさらに1回以上かかる）
+		remaining := j.config.MaxTransitionHops - hop + 1
+		if len(path) > remaining || (path[len(path)-1].to != target && len(path) >= remaining) {
+			break
+		}
+
+		step := path[0]
+		if err := j.doTransition(issueKey, step.id, step.to); err != nil {
+			j.restoreStatus(issueKey, graph, current, start)
+			if step.to == target {
+				return err
+			}
+			return fmt.Errorf("中間ステータス '%s' への遷移失敗: %w", step.to, err)
+		}
+		if step.to == target {
+			return nil
+		}
+		utils.LogInfo("イシュー %s: '%s' へ到達するため '%s' に遷移しました (%d 回目)", issueKey, toStatus, step.to, hop)
+		current = step.to
+
+		if _, explored := graph[current]; !explored {
+			transitions, _, err := j.getTransitionsCached(issueKey, issueType, current)
+			if err != nil {
+				j.restoreStatus(issueKey, graph, current, start)
+				return err
+			}
+			graph[current] = transitions
+		}
+	}
+
+	j.restoreStatus(issueKey, graph, current, start)
+	return fmt.Errorf("ステータス '%s' への遷移が見つかりません (最大遷移回数: %d)", toStatus, j.config.MaxTransitionHops)
+}
+
+// restoreStatus は目的のステータスに到達できなかったイシューを、分かっている遷移で元のステータスに戻します
+// 中間ステータスのまま残さないためで、戻せなかった場合は現在のステータスを警告します
+func (j *JiraClient) restoreStatus(issueKey string, graph map[string]map[string]string, current, original string) {
+	if current == original {
+		return
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("認証失敗: %s", string(body))
+	path := shortestTransitionPath(graph, current, original)
+	if len(path) == 0 || path[len(path)-1].to != original {
+		utils.LogWarn("イシュー %s: 元のステータス '%s' に戻す遷移が見つからないため、'%s' のままになっています", issueKey, original, current)
+		return
+	}
+	for _, step := range path {
+		if err := j.doTransition(issueKey, step.id, step.to); err != nil {
+			utils.LogWarn("イシュー %s: 元のステータス '%s' に戻せず、'%s' のままになっています: %v", issueKey, original, current, err)
+			return
+		}
+		current = step.to
 	}
+	utils.LogInfo("イシュー %s: 目的のステータスに到達できなかったため、元のステータス '%s' に戻しました", issueKey, original)
+}
 
-	return nil
+// knownTransitions はキャッシュ済みのトランジションから、イシュータイプのワークフローのうち分かっている遷移を返します
+// 戻り値はステータス → 遷移先ステータス → トランジションID で、ステータスはすべて小文字です
+func (j *JiraClient) knownTransitions(issueType string) map[string]map[string]string {
+	graph := make(map[string]map[string]string)
+	prefix := transitionCacheKey(issueType, "")
+
+	j.cacheMutex.Lock()
+	defer j.cacheMutex.Unlock()
+	for key, transitions := range j.transitionCache {
+		// 作成直後（遷移元ステータスが空）のキャッシュは、ステータス名が分からないため使わない
+		if status, ok := strings.CutPrefix(key, prefix); ok && status != "" {
+			graph[status] = transitions
+		}
+	}
+	return graph
 }
 
-// CreateIssue はJIRAイシューを作成します
-func (j *JiraClient) CreateIssue(summary, description string, labels []string, issueType string, reporter string, assignee string) (string, error) {
-	url := fmt.Sprintf("%s/rest/api/2/issue", j.config.JiraURL)
+// transitionStep はワークフロー上の1回の遷移です
+type transitionStep struct {
+	to string // 遷移先のステータス（小文字）
+	id string // トランジションID
+}
 
-	// サマリーから改行文字を削除
-	summary = strings.ReplaceAll(summary, "\n", " ")
-	summary = strings.ReplaceAll(summary, "\r", " ")
+// shortestTransitionPath は graph（ステータス → 遷移先ステータス → トランジションID）を from から幅優先探索し、target までの最短の経路を返します
+// target への経路が分からない場合は、遷移の一覧を取得していない最も近いステータスまでの経路（探索の続き）を返します
+// どちらもない場合は nil を返します。同じ距離の遷移はトランジションIDの順（ワークフローの定義順に近い）に辿ります
+func shortestTransitionPath(graph map[string]map[string]string, from, target string) []transitionStep {
+	parent := map[string]string{}
+	reachedBy := map[string]string{} // ステータス → そのステータスへのトランジションID
+	seen := map[string]bool{from: true}
+	queue := []string{from}
+	unexplored := ""
+
+	for len(queue) > 0 {
+		status := queue[0]
+		queue = queue[1:]
+		if status == target {
+			return buildTransitionPath(parent, reachedBy, from, target)
+		}
 
-	// 連続する空白を単一の空白に置換
-	summary = strings.Join(strings.Fields(summary), " ")
+		transitions, explored := graph[status]
+		if !explored {
+			if unexplored == "" {
+				unexplored = status
+			}
+			continue
+		}
 
-	// ラベルが空でないことを確認
-	if labels == nil {
-		labels = []string{}
+		next := make([]string, 0, len(transitions))
+		for to := range transitions {
+			next = append(next, to)
+		}
+		sort.Slice(next, func(a, b int) bool {
+			return compareTransitionID(transitions[next[a]], transitions[next[b]]) < 0
+		})
+		for _, to := range next {
+			if seen[to] {
+				continue
+			}
+			seen[to] = true
+			parent[to] = status
+			reachedBy[to] = transitions[to]
+			queue = append(queue, to)
+		}
 	}
 
-	// フィールドの作成
-	fields := map[string]interface{}{
-		"project":     map[string]string{"key": j.config.JiraProjectKey},
-		"summary":     summary,
-		"description": description,
-		"issuetype":   map[string]string{"name": issueType},
-		"labels":      labels,
+	if unexplored == "" {
+		return nil
 	}
+	return buildTransitionPath(parent, reachedBy, from, unexplored)
+}
 
-	//　担当者と報告者が指定されている場合のマッピング対応
-	j.prepareUserFields(fields, assignee, reporter, description)
-
-	// ペイロードの作成
-	payload := map[string]interface{}{
-		"fields": fields,
+// buildTransitionPath は幅優先探索の結果から from → to の遷移の一覧を作成します
+func buildTransitionPath(parent, reachedBy map[string]string, from, to string) []transitionStep {
+	var path []transitionStep
+	for status := to; status != from; status = parent[status] {
+		path = append(path, transitionStep{to: status, id: reachedBy[status]})
 	}
+	slices.Reverse(path)
+	return path
+}
 
-	payloadBytes, err := json.Marshal(payload)
-	if err != nil {
-		return "", fmt.Errorf("JSONエンコードエラー: %w", err)
+// compareTransitionID はトランジションIDを数値として比較します
+func compareTransitionID(a, b string) int {
+	if len(a) != len(b) {
+		return len(a) - len(b)
 	}
+	return strings.Compare(a, b)
+}
 
-	req, err := http.NewRequest("POST", url, bytes.NewBuffer(payloadBytes))
-	if err != nil {
-		return "", fmt.Errorf("リクエスト作成エラー: %w", err)
+// transitionSteps は目的のステータスに到達するまでに経由するステータスの一覧を返します
+func (j *JiraClient) transitionSteps(targetStatus string) []string {
+	var steps []string
+	for status, path := range j.config.TransitionPaths {
+		if strings.EqualFold(status, targetStatus) {
+			steps = append(steps, path...)
+			break
+		}
 	}
 
-	req.SetBasicAuth(j.config.JiraEmail, j.config.JiraAPIToken)
-	req.Header.Set("Content-Type", "application/json")
-
-	resp, err := j.retryOnRateLimit(req)
-	if err != nil {
-		return "", fmt.Errorf("リクエスト送信エラー: %w", err)
+	// 経路の最後は必ず目的のステータスにする
+	if len(steps) == 0 || !strings.EqualFold(steps[len(steps)-1], targetStatus) {
+		steps = append(steps, targetStatus)
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusCreated {
-		body, _ := io.ReadAll(resp.Body)
-		return "", fmt.Errorf("イシュー作成失敗: %s", string(body))
-	}
+	return steps
+}
 
-	var result map[string]interface{}
-	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		return "", fmt.Errorf("レスポンス解析エラー: %w", err)
+// findTransition は遷移元ステータスから目的のステータスへのトランジションIDを探します
+// 見つからない場合は空文字と利用可能なトランジションを返します
+func (j *JiraClient) findTransition(issueKey, issueType, fromStatus, toStatus string) (string, map[string]string, error) {
+	transitions, cached, err := j.getTransitionsCached(issueKey, issueType, fromStatus)
+	if err != nil {
+		return "", nil, err
 	}
 
-	issueKey, ok := result["key"].(string)
-	if !ok {
-		return "", fmt.Errorf("イシューキーが見つかりません")
+	transitionID, ok := transitions[strings.ToLower(toStatus)]
+	if !ok && cached {
+		// キャッシュが古い可能性があるため取得し直す
+		j.invalidateTransitions(issueType, fromStatus)
+		transitions, _, err = j.getTransitionsCached(issueKey, issueType, fromStatus)
+		if err != nil {
+			return "", nil, err
+		}
+		transitionID = transitions[strings.ToLower(toStatus)]
 	}
 
-	return issueKey, nil
+	return transitionID, transitions, nil
 }
 
-// UpdateStoryPoints はJIRAイシューのストーリーポイントを更新します
-func (j *JiraClient) UpdateStoryPoints(issueKey string, storyPoints int) error {
-	url := fmt.Sprintf("%s/rest/api/2/issue/%s", j.config.JiraURL, issueKey)
+// doTransition はトランジションを実行します
+func (j *JiraClient) doTransition(issueKey, transitionID, toStatus string) error {
+	url := fmt.Sprintf("%s/issue/%s/transitions", j.apiBase, issueKey)
 
 	payload := map[string]interface{}{
-		"fields": map[string]interface{}{
-			j.config.StoryPointField: storyPoints,
+		"transition": map[string]string{
+			"id": transitionID,
 		},
 	}
 
+	// 解決状況のマッピングがある場合は遷移と同時に設定
+	if resolution := j.resolutionFor(toStatus); resolution != "" {
+		payload["fields"] = map[string]interface{}{
+			"resolution": map[string]string{"name": resolution},
+		}
+	}
+
 	payloadBytes, err := json.Marshal(payload)
 	if err != nil {
 		return fmt.Errorf("JSONエンコードエラー: %w", err)
 	}
 
-	req, err := http.NewRequest("PUT", url, bytes.NewBuffer(payloadBytes))
+	req, err := http.NewRequest("POST", url, bytes.NewBuffer(payloadBytes))
 	if err != nil {
 		return fmt.Errorf("リクエスト作成エラー: %w", err)
 	}
@@ -155,98 +1286,100 @@ func (j *JiraClient) UpdateStoryPoints(issueKey string, storyPoints int) error {
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusNoContent {
-		body, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("ストーリーポイント更新失敗: %s", string(body))
+		return fmt.Errorf("ステータス更新失敗: %w", newAPIError(resp))
 	}
 
 	return nil
 }
 
-// GetTransitions はイシューの利用可能なトランジションを取得します
-func (j *JiraClient) GetTransitions(issueKey string) (map[string]string, error) {
-	url := fmt.Sprintf("%s/rest/api/2/issue/%s/transitions", j.config.JiraURL, issueKey)
-
-	req, err := http.NewRequest("GET", url, nil)
-	if err != nil {
-		return nil, fmt.Errorf("リクエスト作成エラー: %w", err)
-	}
-
-	req.SetBasicAuth(j.config.JiraEmail, j.config.JiraAPIToken)
-	req.Header.Set("Content-Type", "application/json")
-
-	resp, err := j.retryOnRateLimit(req)
-	if err != nil {
-		return nil, fmt.Errorf("リクエスト送信エラー: %w", err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("トランジション取得失敗: %s", string(body))
-	}
-
-	var result map[string]interface{}
-	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		return nil, fmt.Errorf("レスポンス解析エラー: %w", err)
-	}
-
-	transitions, ok := result["transitions"].([]interface{})
-	if !ok {
-		return nil, fmt.Errorf("トランジションが見つかりません")
+// getTransitionsCached はイシュータイプと遷移元ステータスごとにキャッシュしたトランジションを返します
+// 2番目の戻り値はキャッシュから取得したかどうかを示します
+func (j *JiraClient) getTransitionsCached(issueKey, issueType, fromStatus string) (map[string]string, bool, error) {
+	if j.config.DisableTransitionCache {
+		transitions, err := j.GetTransitions(issueKey)
+		return transitions, false, err
 	}
 
-	transitionMap := make(map[string]string)
+	cacheKey := transitionCacheKey(issueType, fromStatus)
 
-	for _, t := range transitions {
-		transition, ok := t.(map[string]interface{})
-		if !ok {
-			continue
+	for {
+		j.cacheMutex.Lock()
+		transitions, ok := j.transitionCache[cacheKey]
+		if ok {
+			j.cacheMutex.Unlock()
+			atomic.AddInt64(&j.transitionCallsSaved, 1)
+			utils.LogDebug("イシュー %s: トランジションをキャッシュから取得しました (%s)", issueKey, cacheKey)
+			return transitions, true, nil
 		}
 
-		id, ok := transition["id"].(string)
-		if !ok {
+		// 同じ組み合わせを別のgoroutineが取得中なら完了を待ってキャッシュを再確認する
+		if done, fetching := j.transitionFetches[cacheKey]; fetching {
+			j.cacheMutex.Unlock()
+			<-done
 			continue
 		}
 
-		to, ok := transition["to"].(map[string]interface{})
-		if !ok {
-			continue
-		}
+		done := make(chan struct{})
+		j.transitionFetches[cacheKey] = done
+		j.cacheMutex.Unlock()
 
-		name, ok := to["name"].(string)
-		if !ok {
-			continue
+		transitions, err := j.GetTransitions(issueKey)
+
+		j.cacheMutex.Lock()
+		if err == nil {
+			j.transitionCache[cacheKey] = transitions
 		}
+		delete(j.transitionFetches, cacheKey)
+		close(done)
+		j.cacheMutex.Unlock()
 
-		transitionMap[strings.ToLower(name)] = id
+		if err != nil {
+			return nil, false, err
+		}
+		return transitions, false, nil
 	}
+}
 
-	return transitionMap, nil
+// TransitionCallsSaved はキャッシュによって省略したトランジション取得APIの呼び出し回数を返します
+func (j *JiraClient) TransitionCallsSaved() int64 {
+	return atomic.LoadInt64(&j.transitionCallsSaved)
 }
 
-// UpdateStatus はJIRAイシューのステータスを更新します
-func (j *JiraClient) UpdateStatus(issueKey, targetStatus string) error {
-	if strings.ToLower(targetStatus) == "backlog" {
-		utils.LogInfo("イシュー %s: 'Backlog' ステータスはスキップします", issueKey)
-		return nil // Backlogステータスはスキップ
-	}
+// invalidateTransitions はキャッシュしたトランジションを破棄します
+func (j *JiraClient) invalidateTransitions(issueType, fromStatus string) {
+	j.cacheMutex.Lock()
+	defer j.cacheMutex.Unlock()
+	delete(j.transitionCache, transitionCacheKey(issueType, fromStatus))
+}
 
-	transitions, err := j.GetTransitions(issueKey)
-	if err != nil {
-		return err
-	}
+// transitionCacheKey はトランジションキャッシュのキーを作成します
+func transitionCacheKey(issueType, fromStatus string) string {
+	return strings.ToLower(issueType) + "|" + strings.ToLower(fromStatus)
+}
 
-	transitionID, ok := transitions[strings.ToLower(targetStatus)]
-	if !ok {
-		return fmt.Errorf("ステータス '%s' への遷移が見つかりません", targetStatus)
+// RankIssues はAgile APIを使ってイシューを指定した順序で並べ替えます
+// issues の先頭のイシューを基準に、残りのイシューをその後ろへ順に配置します
+func (j *JiraClient) RankIssues(issues []string) error {
+	// Agile APIは1回のリクエストで最大50件まで
+	const batchSize = 50
+
+	for start := 1; start < len(issues); start += batchSize {
+		end := min(start+batchSize, len(issues))
+		if err := j.rankAfter(issues[start:end], issues[start-1]); err != nil {
+			return err
+		}
 	}
 
-	url := fmt.Sprintf("%s/rest/api/2/issue/%s/transitions", j.config.JiraURL, issueKey)
+	return nil
+}
+
+// rankAfter はイシューを指定したイシューの後ろに配置します
+func (j *JiraClient) rankAfter(issues []string, rankAfterIssue string) error {
+	url := fmt.Sprintf("%s/issue/rank", j.agileBase)
 
 	payload := map[string]interface{}{
-		"transition": map[string]string{
-			"id": transitionID,
-		},
+		"issues":         issues,
+		"rankAfterIssue": rankAfterIssue,
 	}
 
 	payloadBytes, err := json.Marshal(payload)
@@ -254,7 +1387,7 @@ func (j *JiraClient) UpdateStatus(issueKey, targetStatus string) error {
 		return fmt.Errorf("JSONエンコードエラー: %w", err)
 	}
 
-	req, err := http.NewRequest("POST", url, bytes.NewBuffer(payloadBytes))
+	req, err := http.NewRequest("PUT", url, bytes.NewBuffer(payloadBytes))
 	if err != nil {
 		return fmt.Errorf("リクエスト作成エラー: %w", err)
 	}
@@ -269,63 +1402,145 @@ func (j *JiraClient) UpdateStatus(issueKey, targetStatus string) error {
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusNoContent {
-		body, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("ステータス更新失敗: %s", string(body))
+		return fmt.Errorf("ランク更新失敗: %w", newAPIError(resp))
 	}
 
 	return nil
 }
 
+// resolutionFor はJIRAステータスに対応する解決状況名を返します（未設定の場合は空文字）
+func (j *JiraClient) resolutionFor(status string) string {
+	return j.config.ResolutionFor(status)
+}
+
+// UserMapping はPivotalのユーザー名からJIRAアカウントID（Server/Data Center の場合はユーザー名）へのマッピングです
+var UserMapping = map[string]string{
+	"pivotal_user1": "jira_user1",
+	// 必要に応じて追加
+}
+
+// MapUser はPivotalのユーザー名に対応するJIRAのユーザーを返します
+func MapUser(pivotalUser string) (string, bool) {
+	user, ok := UserMapping[pivotalUser]
+	return user, ok
+}
+
 // prepareUserFields はユーザーマッピングを処理し、フィールドマップを更新します
 func (j *JiraClient) prepareUserFields(fields map[string]interface{}, assignee, reporter, description string) {
-	// ユーザー名からJIRAアカウントIDへのマッピング
-	userMapping := map[string]string{
-		"pivotal_user1": "jira_user1",
-		// 必要に応じて追加
-	}
-
 	// 現在の説明文
 	currentDesc := description
 
 	// 担当者の設定
 	if assignee != "" {
-		if accountId, ok := userMapping[assignee]; ok {
-			fields["assignee"] = map[string]string{"id": accountId}
+		if accountId, ok := MapUser(assignee); ok {
+			fields["assignee"] = j.userField(accountId)
 		} else {
 			// マッピングにない場合は説明文に追記
-			currentDesc += fmt.Sprintf("\n\n担当者: %s", assignee)
+			currentDesc += fmt.Sprintf("\n\n%s %s", j.config.AssigneeNotePrefix, assignee)
 		}
 	}
 
 	// 報告者の設定
 	if reporter != "" {
-		if accountId, ok := userMapping[reporter]; ok {
-			fields["reporter"] = map[string]string{"id": accountId}
+		if accountId, ok := MapUser(reporter); ok {
+			fields["reporter"] = j.userField(accountId)
 		} else {
 			// マッピングにない場合は説明文に追記
-			currentDesc += fmt.Sprintf("\n\n報告者: %s", reporter)
+			currentDesc += fmt.Sprintf("\n\n%s %s", j.config.ReporterNotePrefix, reporter)
 		}
 	}
 
 	// 説明文が更新された場合のみ設定
 	if currentDesc != description {
-		fields["description"] = currentDesc
+		fields["description"] = strings.TrimSpace(currentDesc)
+	}
+}
+
+// userField はデプロイ形態に応じたユーザー指定フィールドを作成します
+// Cloud はアカウントID ({"id": ...})、Server/Data Center はユーザー名 ({"name": ...}) で指定します
+func (j *JiraClient) userField(user string) map[string]string {
+	if j.config.IsServerDeployment() {
+		return map[string]string{"name": user}
+	}
+	return map[string]string{"id": user}
+}
+
+// UserListField は複数のJIRAユーザーを、ユーザーピッカー（複数）のフィールドの値（userField の配列）にします
+func (j *JiraClient) UserListField(users []string) []map[string]string {
+	value := make([]map[string]string, len(users))
+	for i, user := range users {
+		value[i] = j.userField(user)
+	}
+	return value
+}
+
+// FindUser はユーザーを検索し、最初に一致したユーザーを返します（見つからない場合は nil）
+func (j *JiraClient) FindUser(query string) (*models.JiraUser, error) {
+	// Cloud は query、Server/Data Center は username パラメータで検索する
+	param := "query"
+	if j.config.IsServerDeployment() {
+		param = "username"
+	}
+
+	endpoint := fmt.Sprintf("%s/user/search?%s=%s", j.apiBase, param, url.QueryEscape(query))
+
+	req, err := http.NewRequest("GET", endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("リクエスト作成エラー: %w", err)
+	}
+
+	req.SetBasicAuth(j.config.JiraEmail, j.config.JiraAPIToken)
+
+	resp, err := j.retryOnRateLimit(req)
+	if err != nil {
+		return nil, fmt.Errorf("リクエスト送信エラー: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("ユーザー検索失敗: %w", newAPIError(resp))
+	}
+
+	var users []models.JiraUser
+	if err := json.NewDecoder(resp.Body).Decode(&users); err != nil {
+		return nil, fmt.Errorf("レスポンス解析エラー: %w", err)
 	}
+
+	if len(users) == 0 {
+		return nil, nil
+	}
+
+	return &users[0], nil
 }
 
 // AddComment はJIRAイシューにコメントを追加します
 func (j *JiraClient) AddComment(issueKey, comment string) error {
+	return j.AddCommentWithVisibility(issueKey, comment, nil)
+}
+
+// CommentVisibility はコメントを閲覧できる範囲（ロールまたはグループ）です
+type CommentVisibility struct {
+	Type  string `json:"type"` // role または group
+	Value string `json:"value"`
+}
+
+// AddCommentWithVisibility はJIRAイシューにコメントを追加します
+// visibility を指定した場合は、そのロール・グループのユーザーのみが閲覧できるコメントとして追加します（nil の場合は制限しない）
+func (j *JiraClient) AddCommentWithVisibility(issueKey, comment string, visibility *CommentVisibility) error {
 	// コメントが空の場合は何もしない
 	if comment == "" {
 		return nil
 	}
 
-	url := fmt.Sprintf("%s/rest/api/2/issue/%s/comment", j.config.JiraURL, issueKey)
+	url := fmt.Sprintf("%s/issue/%s/comment", j.apiBase, issueKey)
 
 	// ペイロードの作成
-	payload := map[string]string{
+	payload := map[string]interface{}{
 		"body": comment,
 	}
+	if visibility != nil {
+		payload["visibility"] = visibility
+	}
 
 	payloadBytes, err := json.Marshal(payload)
 	if err != nil {
@@ -347,8 +1562,7 @@ func (j *JiraClient) AddComment(issueKey, comment string) error {
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusCreated && resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("コメント追加失敗: %s", string(body))
+		return fmt.Errorf("コメント追加失敗: %w", newAPIError(resp))
 	}
 
 	return nil
@@ -356,83 +1570,257 @@ func (j *JiraClient) AddComment(issueKey, comment string) error {
 
 // UploadAttachment はJIRAイシューに添付ファイルをアップロードします
 func (j *JiraClient) UploadAttachment(issueKey, filePath string) error {
-	url := fmt.Sprintf("%s/rest/api/2/issue/%s/attachments", j.config.JiraURL, issueKey)
+	return j.UploadAttachmentAs(issueKey, filePath, filepath.Base(filePath))
+}
 
-	file, err := os.Open(filePath)
-	if err != nil {
-		return fmt.Errorf("ファイルオープンエラー: %w", err)
+// UploadAttachmentAs はJIRAイシューに添付ファイルを fileName の名前でアップロードします
+func (j *JiraClient) UploadAttachmentAs(issueKey, filePath, fileName string) error {
+	_, err := j.UploadAttachmentsAs(issueKey, []string{filePath}, []string{fileName})
+	return err
+}
+
+// UploadAttachments はJIRAイシューに複数の添付ファイルを1回のリクエストでアップロードします
+// 戻り値はアップロードできたファイル名です
+func (j *JiraClient) UploadAttachments(issueKey string, filePaths []string) ([]string, error) {
+	fileNames := make([]string, len(filePaths))
+	for i, filePath := range filePaths {
+		fileNames[i] = filepath.Base(filePath)
 	}
-	defer file.Close()
+	return j.UploadAttachmentsAs(issueKey, filePaths, fileNames)
+}
+
+// UploadAttachmentsAs はJIRAイシューに複数の添付ファイルを fileNames の名前で1回のリクエストでアップロードします
+// 読み込めないファイルは除いて残りをアップロードし、読み込めなかったファイルのエラーをまとめて返します
+// 戻り値はアップロードできたファイル名（fileNames の値）です
+func (j *JiraClient) UploadAttachmentsAs(issueKey string, filePaths, fileNames []string) ([]string, error) {
+	url := fmt.Sprintf("%s/issue/%s/attachments", j.apiBase, issueKey)
 
 	body := &bytes.Buffer{}
 	writer := multipart.NewWriter(body)
 
-	part, err := writer.CreateFormFile("file", filepath.Base(filePath))
-	if err != nil {
-		return fmt.Errorf("multipartフォーム作成エラー: %w", err)
+	var sent []string
+	var fileErrs []error
+	for i, filePath := range filePaths {
+		file, err := os.Open(filePath)
+		if err != nil {
+			fileErrs = append(fileErrs, fmt.Errorf("ファイルオープンエラー: %w", err))
+			continue
+		}
+		// 途中まで書き込んだパートは取り消せないため、コピーに失敗した場合はリクエスト全体を中止する
+		err = writeAttachmentPart(writer, file, fileNames[i])
+		file.Close()
+		if err != nil {
+			return nil, err
+		}
+		sent = append(sent, fileNames[i])
 	}
-
-	_, err = io.Copy(part, file)
-	if err != nil {
-		return fmt.Errorf("ファイルコピーエラー: %w", err)
+	if len(sent) == 0 {
+		return nil, errors.Join(fileErrs...)
 	}
 
-	err = writer.Close()
+	err := writer.Close()
 	if err != nil {
-		return fmt.Errorf("writerクローズエラー: %w", err)
+		return nil, fmt.Errorf("writerクローズエラー: %w", err)
 	}
 
 	req, err := http.NewRequest("POST", url, body)
 	if err != nil {
-		return fmt.Errorf("リクエスト作成エラー: %w", err)
+		return nil, fmt.Errorf("リクエスト作成エラー: %w", err)
 	}
 
 	req.SetBasicAuth(j.config.JiraEmail, j.config.JiraAPIToken)
 	req.Header.Set("Content-Type", writer.FormDataContentType())
 	req.Header.Set("X-Atlassian-Token", "no-check")
 
-	resp, err := j.retryOnRateLimit(req)
+	// 大きなファイルのアップロードは作成などより時間がかかるため、JIRA_UPLOAD_TIMEOUT を適用する
+	resp, err := j.retryOnRateLimit(j.withUploadTimeout(req))
 	if err != nil {
-		return fmt.Errorf("リクエスト送信エラー: %w", err)
+		return nil, fmt.Errorf("リクエスト送信エラー: %w", err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		bodyBytes, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("添付ファイルアップロード失敗: %s", string(bodyBytes))
+		return nil, fmt.Errorf("添付ファイルアップロード失敗: %w", newAPIError(resp))
+	}
+
+	// レスポンスは作成された添付ファイルの一覧。読み取れない場合は送信したファイルをすべて成功とみなす
+	var attachments []struct {
+		Filename string `json:"filename"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&attachments); err != nil || len(attachments) == 0 {
+		return sent, errors.Join(fileErrs...)
+	}
+	created := make(map[string]bool, len(attachments))
+	for _, attachment := range attachments {
+		created[attachment.Filename] = true
+	}
+
+	var uploaded []string
+	for _, name := range sent {
+		if !created[name] {
+			fileErrs = append(fileErrs, fmt.Errorf("添付ファイル %s がレスポンスに含まれていません", name))
+			continue
+		}
+		uploaded = append(uploaded, name)
+	}
+
+	return uploaded, errors.Join(fileErrs...)
+}
+
+// writeAttachmentPart はファイルの内容を fileName の名前で multipart の file パートとして書き込みます
+func writeAttachmentPart(writer *multipart.Writer, file io.Reader, fileName string) error {
+	part, err := writer.CreateFormFile("file", fileName)
+	if err != nil {
+		return fmt.Errorf("multipartフォーム作成エラー: %w", err)
 	}
 
+	if _, err := io.Copy(part, file); err != nil {
+		return fmt.Errorf("ファイルコピーエラー: %w", err)
+	}
 	return nil
 }
 
-// retryOnRateLimit はレート制限エラー(429)の場合に10秒待機して再試行します
+// TunedConcurrency は AUTO_TUNE_CONCURRENCY で調整した現在の並列数を返します（無効な場合は0）
+func (j *JiraClient) TunedConcurrency() int {
+	return j.concurrency.Limit()
+}
+
+// HTTPTime はJIRAへのリクエストにかかった時間の合計を返します
+// 並列に送信したリクエストの時間もそれぞれ加算するため、経過時間より長くなることがあります
+// レート制限や並列数の調整による待機時間は含みません
+func (j *JiraClient) HTTPTime() time.Duration {
+	return time.Duration(j.httpNanos.Load())
+}
+
+// requestTimeoutKey はリクエストのコンテキストに JIRA_REQUEST_TIMEOUT 以外のタイムアウトを指定するキーです
+type requestTimeoutKey struct{}
+
+// withUploadTimeout はリクエストに JIRA_UPLOAD_TIMEOUT のタイムアウトを適用するよう指定します
+func (j *JiraClient) withUploadTimeout(req *http.Request) *http.Request {
+	timeout := time.Duration(j.config.UploadTimeoutSec) * time.Second
+	return req.WithContext(context.WithValue(req.Context(), requestTimeoutKey{}, timeout))
+}
+
+// requestTimeout はリクエストに適用するタイムアウトを返します（0の場合は無制限）
+func (j *JiraClient) requestTimeout(req *http.Request) time.Duration {
+	if timeout, ok := req.Context().Value(requestTimeoutKey{}).(time.Duration); ok {
+		return timeout
+	}
+	return time.Duration(j.config.RequestTimeoutSec) * time.Second
+}
+
+// cancelOnClose はレスポンスボディを閉じたときにリクエストのコンテキストを解放します
+type cancelOnClose struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+// Close はボディを閉じてからコンテキストを解放します
+func (b cancelOnClose) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancel()
+	return err
+}
+
+// send は並列数の調整とレート制限を適用してリクエストを1回送信します
+// タイムアウトはレスポンスボディを閉じるまでの時間に対して、送信ごとに適用します
+// MIGRATION_DEADLINE を過ぎている場合は送信せず、送信中に過ぎた場合はリクエストを中断します
+func (j *JiraClient) send(req *http.Request) (*http.Response, error) {
+	if j.limits.ctx.Err() != nil {
+		return nil, ErrDeadlineExceeded
+	}
+
+	// 期限を過ぎたら送信中のリクエストも中断する
+	ctx, cancelRun := context.WithCancel(req.Context())
+	stop := context.AfterFunc(j.limits.ctx, cancelRun)
+	cancelTimeout := context.CancelFunc(func() {})
+	if timeout := j.requestTimeout(req); timeout > 0 {
+		ctx, cancelTimeout = context.WithTimeout(ctx, timeout)
+	}
+	req = req.WithContext(ctx)
+	release := func() {
+		stop()
+		cancelTimeout()
+		cancelRun()
+	}
+
+	j.concurrency.Acquire()
+	j.limiter.Wait()
+	if utils.DebugEnabled() {
+		logRequest(req)
+	}
+	start := time.Now()
+	resp, err := j.client.Do(req)
+	elapsed := time.Since(start)
+	j.httpNanos.Add(int64(elapsed))
+	if utils.DebugEnabled() {
+		logResponse(req, resp, err, elapsed)
+	}
+	j.concurrency.Release(err == nil && resp.StatusCode == http.StatusTooManyRequests)
+
+	if err != nil {
+		release()
+		if j.limits.ctx.Err() != nil {
+			return nil, fmt.Errorf("%w: %w", ErrDeadlineExceeded, err)
+		}
+		return nil, err
+	}
+	resp.Body = cancelOnClose{ReadCloser: resp.Body, cancel: release}
+	return resp, nil
+}
+
+// rateLimitRetryDelay はレート制限エラー(429)の後に再試行するまでの待機時間です
+var rateLimitRetryDelay = 10 * time.Second
+
+// retryOnRateLimit はレート制限エラー(429)の場合に rateLimitRetryDelay だけ待機して再試行します
+// 最初の送信でボディは読み終わっているため、再試行では GetBody で作り直したボディを送ります
 func (j *JiraClient) retryOnRateLimit(req *http.Request) (*http.Response, error) {
-    // 最初の試行
-    resp, err := j.client.Do(req)
-    if err != nil {
-        return nil, err
-    }
-
-    // 429（レート制限）でなければそのまま返す
-    if resp.StatusCode != 429 {
-        return resp, nil
-    }
-
-    // レート制限エラーの場合、レスポンスボディを読んでクローズ
-    body, _ := io.ReadAll(resp.Body)
-    resp.Body.Close()
-
-    // 10秒待機して再試行
-    utils.LogWarn("レート制限に達しました。10秒後に再試行します。エラー: %s", string(body))
-    time.Sleep(10 * time.Second)
-
-    // リクエストのボディを再設定（必要な場合）
-    if req.Body != nil {
-        bodyBytes, _ := io.ReadAll(req.Body)
-        req.Body.Close()
-        req.Body = io.NopCloser(bytes.NewBuffer(bodyBytes))
-    }
-
-    // 再試行
-    return j.client.Do(req)
+	// GetBody がないボディは再送できないよう、最初の送信前にメモリに読み込んでおく
+	if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+		bodyBytes, err := io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("リクエストボディ読み込みエラー: %w", err)
+		}
+		req.Body = io.NopCloser(bytes.NewReader(bodyBytes))
+		req.GetBody = func() (io.ReadCloser, error) {
+			return io.NopCloser(bytes.NewReader(bodyBytes)), nil
+		}
+	}
+
+	// 最初の試行
+	resp, err := j.send(req)
+	if err != nil {
+		return nil, err
+	}
+
+	// 429（レート制限）でなければそのまま返す
+	if resp.StatusCode != 429 {
+		return resp, nil
+	}
+
+	// レート制限エラーの場合、レスポンスボディを読んでクローズ
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+
+	// 再試行の予算（RETRY_BUDGET）が残っていれば10秒待機して再試行
+	if !j.limits.useRetry() {
+		return nil, fmt.Errorf("レート制限: %w", ErrRetryBudgetExhausted)
+	}
+	utils.LogWarn("レート制限に達しました。%s 後に再試行します。エラー: %s", rateLimitRetryDelay, string(body))
+	if err := j.limits.wait(rateLimitRetryDelay); err != nil {
+		return nil, err
+	}
+
+	// 最初の送信で読み終わったボディの代わりに、同じ内容のボディを作り直す
+	retry := req.Clone(req.Context())
+	if req.GetBody != nil {
+		retry.Body, err = req.GetBody()
+		if err != nil {
+			return nil, fmt.Errorf("リクエストボディ再作成エラー: %w", err)
+		}
+	}
+
+	// 再試行
+	return j.send(retry)
 }
@@ -0,0 +1,966 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"pivotaltojira/config"
+)
+
+// testJiraURL はテスト用クライアントの接続先です（実際には接続せず handlerTransport で処理します）
+const testJiraURL = "https://jira.example.test"
+
+// handlerTransport はリクエストをネットワークに送らずに handler で処理する http.RoundTripper です
+// net/http の Transport と異なり、送信済みのボディを GetBody で自動的に巻き戻さないため、再送の誤りをそのまま検出できます
+type handlerTransport struct {
+	handler http.Handler
+}
+
+func (t handlerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	// サーバー側のリクエストと同様に、ボディがない場合も空のボディを渡す
+	served := req
+	if req.Body == nil {
+		served = req.Clone(req.Context())
+		served.Body = http.NoBody
+	}
+	defer served.Body.Close()
+	rec := httptest.NewRecorder()
+	t.handler.ServeHTTP(rec, served)
+	resp := rec.Result()
+	resp.Request = req
+	return resp, nil
+}
+
+// newTestClient は handler で応答するJIRAクライアントを作成します
+// 設定は環境変数から読み込むため、追加の設定は t.Setenv で指定してから呼び出します
+func newTestClient(t *testing.T, handler http.Handler) *JiraClient {
+	t.Helper()
+
+	t.Setenv("JIRA_URL", testJiraURL)
+	t.Setenv("JIRA_EMAIL", "tester@example.com")
+	t.Setenv("JIRA_API_TOKEN", "test-api-token")
+	t.Setenv("JIRA_PROJECT_KEY", "TEST")
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		t.Fatalf("設定の読み込みに失敗しました: %v", err)
+	}
+	return NewJiraClientWithClient(cfg, &http.Client{Transport: handlerTransport{handler: handler}})
+}
+
+// shortRetryDelay はテストの間だけ429の後の待機時間を短くします
+func shortRetryDelay(t *testing.T) {
+	t.Helper()
+	saved := rateLimitRetryDelay
+	rateLimitRetryDelay = time.Millisecond
+	t.Cleanup(func() { rateLimitRetryDelay = saved })
+}
+
+// recordedRequest はテスト用の handler が受け取ったリクエストです
+type recordedRequest struct {
+	Method string
+	Path   string
+	Query  string
+	Header http.Header
+	Body   string
+}
+
+// requestRecorder は受け取ったリクエストを記録し、responses の順に応答します
+type requestRecorder struct {
+	mu        sync.Mutex
+	requests  []recordedRequest
+	responses []func(w http.ResponseWriter)
+}
+
+func (r *requestRecorder) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	body, _ := io.ReadAll(req.Body)
+
+	r.mu.Lock()
+	n := len(r.requests)
+	r.requests = append(r.requests, recordedRequest{Method: req.Method, Path: req.URL.Path, Query: req.URL.RawQuery, Header: req.Header.Clone(), Body: string(body)})
+	respond := r.responses[min(n, len(r.responses)-1)]
+	r.mu.Unlock()
+
+	respond(w)
+}
+
+func (r *requestRecorder) recorded() []recordedRequest {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]recordedRequest(nil), r.requests...)
+}
+
+// reply は status と body を返す応答を作成します
+func reply(status int, body string) func(w http.ResponseWriter) {
+	return func(w http.ResponseWriter) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		io.WriteString(w, body)
+	}
+}
+
+func TestRetryOnRateLimitResendsJSONBody(t *testing.T) {
+	shortRetryDelay(t)
+	recorder := &requestRecorder{responses: []func(http.ResponseWriter){
+		reply(http.StatusTooManyRequests, `{"errorMessages":["Rate limit exceeded"]}`),
+		reply(http.StatusCreated, `{"key":"TEST-1"}`),
+	}}
+	client := newTestClient(t, recorder)
+
+	key, err := client.CreateIssue("タイトル", "説明", []string{"pivotal-1"}, "Story", "", "")
+	if err != nil {
+		t.Fatalf("CreateIssue がエラーを返しました: %v", err)
+	}
+	if key != "TEST-1" {
+		t.Errorf("イシューキー = %q, want TEST-1", key)
+	}
+
+	requests := recorder.recorded()
+	if len(requests) != 2 {
+		t.Fatalf("リクエスト数 = %d, want 2", len(requests))
+	}
+	if requests[1].Body == "" {
+		t.Fatal("再試行のリクエストのボディが空です")
+	}
+	if requests[1].Body != requests[0].Body {
+		t.Errorf("再試行のボディが最初の送信と異なります\n1回目: %s\n2回目: %s", requests[0].Body, requests[1].Body)
+	}
+	if client.RetryCount() != 1 {
+		t.Errorf("RetryCount() = %d, want 1", client.RetryCount())
+	}
+}
+
+func TestRetryOnRateLimitResendsMultipartBody(t *testing.T) {
+	shortRetryDelay(t)
+	recorder := &requestRecorder{responses: []func(http.ResponseWriter){
+		reply(http.StatusTooManyRequests, ``),
+		reply(http.StatusOK, `[{"filename":"report.txt"}]`),
+	}}
+	client := newTestClient(t, recorder)
+
+	path := filepath.Join(t.TempDir(), "report.txt")
+	if err := os.WriteFile(path, []byte("添付ファイルの内容"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := client.UploadAttachment("TEST-1", path); err != nil {
+		t.Fatalf("UploadAttachment がエラーを返しました: %v", err)
+	}
+
+	requests := recorder.recorded()
+	if len(requests) != 2 {
+		t.Fatalf("リクエスト数 = %d, want 2", len(requests))
+	}
+	if !strings.Contains(requests[1].Body, "添付ファイルの内容") {
+		t.Errorf("再試行のボディにファイルの内容が含まれていません: %q", requests[1].Body)
+	}
+	if requests[1].Body != requests[0].Body {
+		t.Error("再試行のボディが最初の送信と異なります")
+	}
+}
+
+// jiraStub はパスごとの応答を返すテスト用の handler です
+type jiraStub struct {
+	requestRecorder
+	routes map[string]func(w http.ResponseWriter, body string) // "METHOD パス" → 応答
+}
+
+func newJiraStub() *jiraStub {
+	return &jiraStub{routes: make(map[string]func(http.ResponseWriter, string))}
+}
+
+// handle は method と path（apiBase からの相対パス。例: /issue）への応答を登録します
+func (s *jiraStub) handle(method, path string, respond func(w http.ResponseWriter, body string)) {
+	s.routes[method+" /rest/api/2"+path] = respond
+}
+
+func (s *jiraStub) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	body, _ := io.ReadAll(req.Body)
+
+	s.mu.Lock()
+	s.requests = append(s.requests, recordedRequest{Method: req.Method, Path: req.URL.Path, Query: req.URL.RawQuery, Header: req.Header.Clone(), Body: string(body)})
+	respond, ok := s.routes[req.Method+" "+req.URL.Path]
+	s.mu.Unlock()
+
+	if !ok {
+		http.NotFound(w, req)
+		return
+	}
+	respond(w, string(body))
+}
+
+// requestsTo は method と path（apiBase からの相対パス）へのリクエストを返します
+func (s *jiraStub) requestsTo(method, path string) []recordedRequest {
+	var matched []recordedRequest
+	for _, r := range s.recorded() {
+		if r.Method == method && r.Path == "/rest/api/2"+path {
+			matched = append(matched, r)
+		}
+	}
+	return matched
+}
+
+// respondWith は常に status と body を返す応答を作成します
+func respondWith(status int, body string) func(http.ResponseWriter, string) {
+	return func(w http.ResponseWriter, _ string) {
+		reply(status, body)(w)
+	}
+}
+
+func TestCreateIssue(t *testing.T) {
+	stub := newJiraStub()
+	stub.handle("POST", "/issue", respondWith(http.StatusCreated, `{"id":"10001","key":"TEST-1"}`))
+	client := newTestClient(t, stub)
+
+	key, err := client.CreateIssue("  タイトル\n 2行目 ", "説明", []string{"pivotal-100"}, "Story", "", "")
+	if err != nil {
+		t.Fatalf("CreateIssue がエラーを返しました: %v", err)
+	}
+	if key != "TEST-1" {
+		t.Errorf("イシューキー = %q, want TEST-1", key)
+	}
+
+	requests := stub.requestsTo("POST", "/issue")
+	if len(requests) != 1 {
+		t.Fatalf("作成のリクエスト数 = %d, want 1", len(requests))
+	}
+	if user, pass, ok := (&http.Request{Header: requests[0].Header}).BasicAuth(); !ok || user != "tester@example.com" || pass != "test-api-token" {
+		t.Errorf("Basic認証 = %q/%q (%v)", user, pass, ok)
+	}
+	fields := decodeFields(t, requests[0].Body)
+	assertJSON(t, fields["project"], `{"key":"TEST"}`)
+	assertJSON(t, fields["issuetype"], `{"name":"Story"}`)
+	assertJSON(t, fields["summary"], `"タイトル 2行目"`)
+	assertJSON(t, fields["labels"], `["pivotal-100"]`)
+}
+
+func TestCreateIssueBadRequest(t *testing.T) {
+	stub := newJiraStub()
+	stub.handle("POST", "/issue", respondWith(http.StatusBadRequest,
+		`{"errorMessages":[],"errors":{"customfield_10016":"Field cannot be set."}}`))
+	stub.handle("GET", "/field", respondWith(http.StatusOK,
+		`[{"id":"customfield_10016","name":"Story Points"}]`))
+	client := newTestClient(t, stub)
+
+	_, err := client.CreateIssue("タイトル", "", nil, "Story", "", "")
+	if err == nil {
+		t.Fatal("400 の場合はエラーになるべきです")
+	}
+	if StatusCode(err) != http.StatusBadRequest {
+		t.Errorf("StatusCode(err) = %d, want 400", StatusCode(err))
+	}
+	if !strings.Contains(err.Error(), "field 'Story Points' (customfield_10016): Field cannot be set.") {
+		t.Errorf("エラーにフィールド名が含まれていません: %v", err)
+	}
+	if got := len(stub.requestsTo("POST", "/issue")); got != 1 {
+		t.Errorf("400 は再試行しないはずですが %d 回送信しました", got)
+	}
+}
+
+func TestCreateIssueRateLimited(t *testing.T) {
+	shortRetryDelay(t)
+	stub := newJiraStub()
+	stub.handle("POST", "/issue", respondWith(http.StatusTooManyRequests, `{"errorMessages":["Rate limit exceeded"]}`))
+	client := newTestClient(t, stub)
+
+	_, err := client.CreateIssue("タイトル", "", nil, "Story", "", "")
+	if StatusCode(err) != http.StatusTooManyRequests {
+		t.Fatalf("StatusCode(err) = %d, want 429 (err: %v)", StatusCode(err), err)
+	}
+	// 429 は1回だけ再試行する
+	if got := len(stub.requestsTo("POST", "/issue")); got != 2 {
+		t.Errorf("送信回数 = %d, want 2", got)
+	}
+}
+
+func TestCreateIssueRateLimitRetryBudget(t *testing.T) {
+	shortRetryDelay(t)
+	t.Setenv("RETRY_BUDGET", "1")
+	stub := newJiraStub()
+	stub.handle("POST", "/issue", respondWith(http.StatusTooManyRequests, ``))
+	client := newTestClient(t, stub)
+
+	client.CreateIssue("1件目", "", nil, "Story", "", "")
+	_, err := client.CreateIssue("2件目", "", nil, "Story", "", "")
+	if !errors.Is(err, ErrRetryBudgetExhausted) {
+		t.Fatalf("再試行の予算を使い切った場合は ErrRetryBudgetExhausted になるべきです: %v", err)
+	}
+	if !errors.Is(client.Stopped(), ErrRetryBudgetExhausted) {
+		t.Errorf("Stopped() = %v, want ErrRetryBudgetExhausted", client.Stopped())
+	}
+}
+
+func TestCreateIssueTruncatesLongSummary(t *testing.T) {
+	stub := newJiraStub()
+	stub.handle("POST", "/issue", respondWith(http.StatusCreated, `{"id":"10001","key":"TEST-1"}`))
+	client := newTestClient(t, stub)
+
+	title := strings.Repeat("長いタイトル", 50) // 300文字（マルチバイト）
+	if _, err := client.CreateIssue(title, "元の説明", nil, "Story", "", ""); err != nil {
+		t.Fatalf("CreateIssue がエラーを返しました: %v", err)
+	}
+
+	fields := decodeFields(t, stub.requestsTo("POST", "/issue")[0].Body)
+	var summary, description string
+	json.Unmarshal(fields["summary"], &summary)
+	json.Unmarshal(fields["description"], &description)
+
+	// 文字（rune）単位で切り詰め、マルチバイト文字を途中で切らない
+	if !utf8.ValidString(summary) || utf8.RuneCountInString(summary) != maxSummaryLength {
+		t.Errorf("サマリー = %d 文字（UTF-8として正しい: %v）, want %d 文字", utf8.RuneCountInString(summary), utf8.ValidString(summary), maxSummaryLength)
+	}
+	if want := string([]rune(title)[:maxSummaryLength-1]) + "…"; summary != want {
+		t.Errorf("サマリー = %q, want %q", summary, want)
+	}
+	// 元のタイトルは説明文の先頭に残す
+	if want := "元のタイトル: " + title + "\n\n元の説明"; description != want {
+		t.Errorf("説明文 = %q, want %q", description, want)
+	}
+}
+
+func TestCreateIssueKeepsSummaryAtLimit(t *testing.T) {
+	stub := newJiraStub()
+	stub.handle("POST", "/issue", respondWith(http.StatusCreated, `{"id":"10001","key":"TEST-1"}`))
+	client := newTestClient(t, stub)
+
+	title := strings.Repeat("あ", maxSummaryLength)
+	if _, err := client.CreateIssue(title, "", nil, "Story", "", ""); err != nil {
+		t.Fatalf("CreateIssue がエラーを返しました: %v", err)
+	}
+
+	fields := decodeFields(t, stub.requestsTo("POST", "/issue")[0].Body)
+	assertJSON(t, fields["summary"], `"`+title+`"`)
+	if _, ok := fields["description"]; ok {
+		t.Errorf("上限ちょうどのサマリーで説明文が追加されています: %s", fields["description"])
+	}
+}
+
+func TestBuildCreatePayloadOmitsEmptyDescription(t *testing.T) {
+	client := newTestClient(t, http.NotFoundHandler())
+
+	for _, description := range []string{"", "  \n\t "} {
+		payload, err := client.BuildCreatePayload("タイトル", description, nil, "Story", "", "", nil)
+		if err != nil {
+			t.Fatalf("BuildCreatePayload がエラーを返しました: %v", err)
+		}
+		fields := payload["fields"].(map[string]interface{})
+		for _, key := range []string{"description", "labels", "assignee", "reporter"} {
+			if value, ok := fields[key]; ok {
+				t.Errorf("説明文 %q の %s = %v, want なし", description, key, value)
+			}
+		}
+	}
+
+	payload, err := client.BuildCreatePayload("タイトル", "説明", nil, "Story", "", "", nil)
+	if err != nil {
+		t.Fatalf("BuildCreatePayload がエラーを返しました: %v", err)
+	}
+	if got := payload["fields"].(map[string]interface{})["description"]; got != "説明" {
+		t.Errorf("description = %v, want 説明", got)
+	}
+}
+
+func TestUserFieldsByDeployment(t *testing.T) {
+	tests := []struct {
+		deployment string
+		wantUser   string
+		wantQuery  string
+	}{
+		{"cloud", `{"id":"jira_user1"}`, "query=someone%40example.com"},
+		{"server", `{"name":"jira_user1"}`, "username=someone%40example.com"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.deployment, func(t *testing.T) {
+			t.Setenv("JIRA_DEPLOYMENT", tt.deployment)
+			stub := newJiraStub()
+			stub.handle("POST", "/issue", respondWith(http.StatusCreated, `{"id":"10001","key":"TEST-1"}`))
+			stub.handle("GET", "/user/search", respondWith(http.StatusOK, `[{"accountId":"abc","name":"someone"}]`))
+			client := newTestClient(t, stub)
+
+			// 担当者・報告者・ユーザーピッカーのフィールドは同じ形式で指定する
+			reviewers := map[string]interface{}{"customfield_10200": client.UserListField([]string{"jira_user1"})}
+			if _, err := client.CreateIssueWithFields("タイトル", "", nil, "Story", "pivotal_user1", "pivotal_user1", reviewers); err != nil {
+				t.Fatalf("CreateIssueWithFields がエラーを返しました: %v", err)
+			}
+			fields := decodeFields(t, stub.requestsTo("POST", "/issue")[0].Body)
+			assertJSON(t, fields["assignee"], tt.wantUser)
+			assertJSON(t, fields["reporter"], tt.wantUser)
+			assertJSON(t, fields["customfield_10200"], "["+tt.wantUser+"]")
+
+			// ユーザー検索のパラメータも形態に合わせる
+			if _, err := client.FindUser("someone@example.com"); err != nil {
+				t.Fatalf("FindUser がエラーを返しました: %v", err)
+			}
+			if got := stub.requestsTo("GET", "/user/search")[0].Query; got != tt.wantQuery {
+				t.Errorf("ユーザー検索のクエリ = %q, want %q", got, tt.wantQuery)
+			}
+		})
+	}
+}
+
+func TestContextPathURLs(t *testing.T) {
+	t.Setenv("JIRA_CONTEXT_PATH", "jira/")
+	recorder := &requestRecorder{responses: []func(http.ResponseWriter){
+		reply(http.StatusCreated, `{"id":"10001","key":"TEST-1"}`),
+		reply(http.StatusOK, `{"key":"TEST-1","fields":{"summary":"タイトル","status":{"name":"To Do"}}}`),
+		reply(http.StatusNoContent, ``),
+	}}
+	client := newTestClient(t, recorder)
+
+	if _, err := client.CreateIssue("タイトル", "", nil, "Story", "", ""); err != nil {
+		t.Fatalf("CreateIssue がエラーを返しました: %v", err)
+	}
+	if _, err := client.GetIssue("TEST-1"); err != nil {
+		t.Fatalf("GetIssue がエラーを返しました: %v", err)
+	}
+	if err := client.RankIssues([]string{"TEST-1", "TEST-2"}); err != nil {
+		t.Fatalf("RankIssues がエラーを返しました: %v", err)
+	}
+
+	var paths []string
+	for _, r := range recorder.recorded() {
+		paths = append(paths, r.Method+" "+r.Path)
+	}
+	want := []string{
+		"POST /jira/rest/api/2/issue",
+		"GET /jira/rest/api/2/issue/TEST-1",
+		"PUT /jira/rest/agile/1.0/issue/rank",
+	}
+	if !reflect.DeepEqual(paths, want) {
+		t.Errorf("リクエスト = %q, want %q", paths, want)
+	}
+	if got := client.config.BrowseURL("TEST-1"); got != testJiraURL+"/jira/browse/TEST-1" {
+		t.Errorf("BrowseURL = %q, want %q", got, testJiraURL+"/jira/browse/TEST-1")
+	}
+}
+
+func TestUpdateStatus(t *testing.T) {
+	stub := newJiraStub()
+	stub.handle("GET", "/issue/TEST-1/transitions", respondWith(http.StatusOK, `{"transitions":[
+		{"id":"11","to":{"name":"To Do"}},
+		{"id":"21","to":{"name":"In Progress"}},
+		{"id":"31","to":{"name":"Done"}}]}`))
+	stub.handle("POST", "/issue/TEST-1/transitions", respondWith(http.StatusNoContent, ``))
+	client := newTestClient(t, stub)
+
+	// ステータス名は大文字小文字を区別せずに照合する
+	if err := client.UpdateStatus("TEST-1", "Story", "in progress"); err != nil {
+		t.Fatalf("UpdateStatus がエラーを返しました: %v", err)
+	}
+
+	posts := stub.requestsTo("POST", "/issue/TEST-1/transitions")
+	if len(posts) != 1 {
+		t.Fatalf("遷移のリクエスト数 = %d, want 1", len(posts))
+	}
+	var payload map[string]json.RawMessage
+	if err := json.Unmarshal([]byte(posts[0].Body), &payload); err != nil {
+		t.Fatal(err)
+	}
+	assertJSON(t, payload["transition"], `{"id":"21"}`)
+	if _, ok := payload["fields"]; ok {
+		t.Errorf("解決状況のマッピングがないステータスでは fields を送信しないはずです: %s", posts[0].Body)
+	}
+}
+
+func TestUpdateStatusSetsResolution(t *testing.T) {
+	stub := newJiraStub()
+	stub.handle("GET", "/issue/TEST-1/transitions", respondWith(http.StatusOK, `{"transitions":[{"id":"31","to":{"name":"Done"}}]}`))
+	stub.handle("POST", "/issue/TEST-1/transitions", respondWith(http.StatusNoContent, ``))
+	client := newTestClient(t, stub)
+
+	if err := client.UpdateStatus("TEST-1", "Story", "Done"); err != nil {
+		t.Fatalf("UpdateStatus がエラーを返しました: %v", err)
+	}
+	posts := stub.requestsTo("POST", "/issue/TEST-1/transitions")
+	if len(posts) != 1 {
+		t.Fatalf("遷移のリクエスト数 = %d, want 1", len(posts))
+	}
+	assertJSON(t, json.RawMessage(posts[0].Body), `{"fields":{"resolution":{"name":"Done"}},"transition":{"id":"31"}}`)
+}
+
+func TestUpdateStatusSkipsInitialStatus(t *testing.T) {
+	stub := newJiraStub()
+	client := newTestClient(t, stub)
+
+	if err := client.UpdateStatus("TEST-1", "Story", "Backlog"); err != nil {
+		t.Fatalf("UpdateStatus がエラーを返しました: %v", err)
+	}
+	if got := len(stub.recorded()); got != 0 {
+		t.Errorf("SKIP_STATUSES のステータスではリクエストを送信しないはずですが %d 件送信しました", got)
+	}
+}
+
+func TestUpdateStatusCustomSkipStatuses(t *testing.T) {
+	t.Setenv("SKIP_STATUSES", "To Do, 未着手")
+	stub := newJiraStub()
+	client := newTestClient(t, stub)
+
+	for _, status := range []string{"To Do", "to do", "未着手"} {
+		if err := client.UpdateStatus("TEST-1", "Story", status); err != nil {
+			t.Fatalf("UpdateStatus(%q) がエラーを返しました: %v", status, err)
+		}
+	}
+	if got := len(stub.recorded()); got != 0 {
+		t.Errorf("SKIP_STATUSES のステータスではリクエストを送信しないはずですが %d 件送信しました", got)
+	}
+
+	// 一覧を指定するとデフォルトの backlog は遷移の対象になる
+	client.UpdateStatus("TEST-1", "Story", "Backlog")
+	if got := len(stub.recorded()); got == 0 {
+		t.Error("SKIP_STATUSES にない Backlog で遷移のリクエストを送信していません")
+	}
+}
+
+func TestUploadAttachmentsMultipart(t *testing.T) {
+	stub := newJiraStub()
+	stub.handle("POST", "/issue/TEST-1/attachments", respondWith(http.StatusOK,
+		`[{"filename":"設計書.txt"},{"filename":"image.png"}]`))
+	client := newTestClient(t, stub)
+
+	dir := t.TempDir()
+	contents := map[string]string{"a.txt": "設計の内容", "b.png": "\x89PNG\r\n"}
+	for name, content := range contents {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	uploaded, err := client.UploadAttachmentsAs("TEST-1",
+		[]string{filepath.Join(dir, "a.txt"), filepath.Join(dir, "b.png")},
+		[]string{"設計書.txt", "image.png"})
+	if err != nil {
+		t.Fatalf("UploadAttachmentsAs がエラーを返しました: %v", err)
+	}
+	if strings.Join(uploaded, ",") != "設計書.txt,image.png" {
+		t.Errorf("アップロードしたファイル = %v", uploaded)
+	}
+
+	requests := stub.requestsTo("POST", "/issue/TEST-1/attachments")
+	if len(requests) != 1 {
+		t.Fatalf("アップロードのリクエスト数 = %d, want 1", len(requests))
+	}
+	req := requests[0]
+	if got := req.Header.Get("X-Atlassian-Token"); got != "no-check" {
+		t.Errorf("X-Atlassian-Token = %q, want no-check", got)
+	}
+
+	_, params, err := mime.ParseMediaType(req.Header.Get("Content-Type"))
+	if err != nil {
+		t.Fatalf("Content-Type を解析できません: %v", err)
+	}
+	reader := multipart.NewReader(strings.NewReader(req.Body), params["boundary"])
+	want := []struct{ name, content string }{{"設計書.txt", "設計の内容"}, {"image.png", "\x89PNG\r\n"}}
+	for _, w := range want {
+		part, err := reader.NextPart()
+		if err != nil {
+			t.Fatalf("パート %s を読み込めません: %v", w.name, err)
+		}
+		if part.FormName() != "file" {
+			t.Errorf("フォーム名 = %q, want file", part.FormName())
+		}
+		if part.FileName() != w.name {
+			t.Errorf("ファイル名 = %q, want %q", part.FileName(), w.name)
+		}
+		content, _ := io.ReadAll(part)
+		if string(content) != w.content {
+			t.Errorf("%s の内容 = %q, want %q", w.name, content, w.content)
+		}
+	}
+	if _, err := reader.NextPart(); err != io.EOF {
+		t.Errorf("余分なパートがあります: %v", err)
+	}
+}
+
+func TestUploadAttachmentsSkipsUnreadableFiles(t *testing.T) {
+	stub := newJiraStub()
+	stub.handle("POST", "/issue/TEST-1/attachments", respondWith(http.StatusOK, `[{"filename":"ok.txt"}]`))
+	client := newTestClient(t, stub)
+
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "ok.txt"), []byte("ok"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	uploaded, err := client.UploadAttachments("TEST-1", []string{filepath.Join(dir, "missing.txt"), filepath.Join(dir, "ok.txt")})
+	if err == nil {
+		t.Error("読み込めないファイルのエラーが返されていません")
+	}
+	if strings.Join(uploaded, ",") != "ok.txt" {
+		t.Errorf("アップロードしたファイル = %v, want [ok.txt]", uploaded)
+	}
+}
+
+// decodeFields は作成・更新のペイロードの fields を返します
+func decodeFields(t *testing.T, body string) map[string]json.RawMessage {
+	t.Helper()
+	var payload struct {
+		Fields map[string]json.RawMessage `json:"fields"`
+	}
+	if err := json.Unmarshal([]byte(body), &payload); err != nil {
+		t.Fatalf("ペイロードを解析できません: %v\n%s", err, body)
+	}
+	return payload.Fields
+}
+
+// assertJSON は got が want と同じJSONの値かを確認します
+func assertJSON(t *testing.T, got json.RawMessage, want string) {
+	t.Helper()
+	var g, w interface{}
+	if err := json.Unmarshal(got, &g); err != nil {
+		t.Errorf("JSONを解析できません: %v (%s)", err, got)
+		return
+	}
+	if err := json.Unmarshal([]byte(want), &w); err != nil {
+		t.Fatalf("期待値のJSONが不正です: %v", err)
+	}
+	if !reflect.DeepEqual(g, w) {
+		t.Errorf("JSON = %s, want %s", got, want)
+	}
+}
+
+// workflowStub はワークフローの定義に従ってイシュー TEST-1 のステータスを遷移させるテスト用の handler です
+type workflowStub struct {
+	mu       sync.Mutex
+	status   string
+	workflow map[string][]workflowTransition // ステータス → 利用可能な遷移
+	taken    []string                        // 実行した遷移の遷移先
+	failTo   string                          // この遷移先への遷移は400で失敗させる
+}
+
+type workflowTransition struct {
+	id, to string
+}
+
+func (s *workflowStub) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	switch {
+	case req.Method == "GET" && req.URL.Path == "/rest/api/2/issue/TEST-1":
+		body, _ := json.Marshal(map[string]interface{}{
+			"key":    "TEST-1",
+			"fields": map[string]interface{}{"status": map[string]string{"name": s.status}},
+		})
+		reply(http.StatusOK, string(body))(w)
+
+	case req.Method == "GET" && req.URL.Path == "/rest/api/2/issue/TEST-1/transitions":
+		var transitions []map[string]interface{}
+		for _, t := range s.workflow[s.status] {
+			transitions = append(transitions, map[string]interface{}{"id": t.id, "to": map[string]string{"name": t.to}})
+		}
+		body, _ := json.Marshal(map[string]interface{}{"transitions": transitions})
+		reply(http.StatusOK, string(body))(w)
+
+	case req.Method == "POST" && req.URL.Path == "/rest/api/2/issue/TEST-1/transitions":
+		var payload struct {
+			Transition struct {
+				ID string `json:"id"`
+			} `json:"transition"`
+		}
+		json.NewDecoder(req.Body).Decode(&payload)
+		for _, t := range s.workflow[s.status] {
+			if t.id != payload.Transition.ID {
+				continue
+			}
+			if t.to == s.failTo {
+				reply(http.StatusBadRequest, `{"errorMessages":["Transition validation failed"]}`)(w)
+				return
+			}
+			s.status = t.to
+			s.taken = append(s.taken, t.to)
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		reply(http.StatusBadRequest, `{"errorMessages":["It seems that you have tried to perform a workflow operation that is not valid from the current state."]}`)(w)
+
+	default:
+		http.NotFound(w, req)
+	}
+}
+
+func (s *workflowStub) result() (string, []string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.status, append([]string(nil), s.taken...)
+}
+
+// simpleWorkflow は To Do → In Progress → Done の順にしか進めないワークフローです
+func simpleWorkflow() map[string][]workflowTransition {
+	return map[string][]workflowTransition{
+		"To Do":       {{"21", "In Progress"}},
+		"In Progress": {{"11", "To Do"}, {"31", "Done"}},
+		"Done":        {{"41", "In Progress"}},
+	}
+}
+
+func TestUpdateStatusWalksIntermediateStatus(t *testing.T) {
+	stub := &workflowStub{status: "To Do", workflow: simpleWorkflow()}
+	client := newTestClient(t, stub)
+
+	// 作成直後のイシュー（遷移元ステータスが空）から、直接遷移できない Done へ
+	if err := client.UpdateStatus("TEST-1", "Story", "done"); err != nil {
+		t.Fatalf("UpdateStatus がエラーを返しました: %v", err)
+	}
+
+	status, taken := stub.result()
+	if status != "Done" {
+		t.Errorf("ステータス = %q, want Done", status)
+	}
+	if got := strings.Join(taken, " > "); got != "In Progress > Done" {
+		t.Errorf("遷移 = %s, want In Progress > Done", got)
+	}
+}
+
+func TestUpdateStatusFindsShortestPath(t *testing.T) {
+	// IDの小さい遷移から順に辿ると Blocked で行き止まりになるワークフロー
+	workflow := simpleWorkflow()
+	workflow["To Do"] = []workflowTransition{{"5", "Blocked"}, {"21", "In Progress"}}
+	workflow["Blocked"] = []workflowTransition{{"6", "To Do"}}
+
+	stub := &workflowStub{status: "To Do", workflow: workflow}
+	client := newTestClient(t, stub)
+
+	// 1件目は Blocked・In Progress のどちらから Done に行けるかまだ分からないため探索する
+	if err := client.UpdateStatusFrom("TEST-1", "Story", "To Do", "Done"); err != nil {
+		t.Fatalf("UpdateStatusFrom がエラーを返しました: %v", err)
+	}
+	if status, _ := stub.result(); status != "Done" {
+		t.Fatalf("ステータス = %q, want Done", status)
+	}
+
+	// 2件目は1件目で分かったワークフローから最短の経路だけを辿る
+	stub.mu.Lock()
+	stub.status, stub.taken = "To Do", nil
+	stub.mu.Unlock()
+	if err := client.UpdateStatusFrom("TEST-1", "Story", "To Do", "Done"); err != nil {
+		t.Fatalf("UpdateStatusFrom がエラーを返しました: %v", err)
+	}
+	if _, taken := stub.result(); strings.Join(taken, " > ") != "In Progress > Done" {
+		t.Errorf("遷移 = %s, want In Progress > Done", strings.Join(taken, " > "))
+	}
+}
+
+func TestUpdateStatusRestoresStatusWhenUnreachable(t *testing.T) {
+	stub := &workflowStub{status: "To Do", workflow: simpleWorkflow()}
+	client := newTestClient(t, stub)
+
+	err := client.UpdateStatus("TEST-1", "Story", "Closed")
+	if err == nil {
+		t.Fatal("到達できないステータスではエラーになるべきです")
+	}
+
+	// 探索のために遷移した中間ステータスに残さず、元のステータスに戻す
+	if status, _ := stub.result(); status != "To Do" {
+		t.Errorf("ステータス = %q, want To Do", status)
+	}
+}
+
+func TestUpdateStatusRestoresStatusWhenFinalTransitionFails(t *testing.T) {
+	stub := &workflowStub{status: "To Do", workflow: simpleWorkflow(), failTo: "Done"}
+	client := newTestClient(t, stub)
+
+	if err := client.UpdateStatus("TEST-1", "Story", "Done"); StatusCode(err) != http.StatusBadRequest {
+		t.Fatalf("最後の遷移の失敗がそのまま返されていません: %v", err)
+	}
+	if status, taken := stub.result(); status != "To Do" {
+		t.Errorf("ステータス = %q (遷移: %v), want To Do", status, taken)
+	}
+}
+
+func TestUpdateStatusHonorsMaxHops(t *testing.T) {
+	t.Setenv("MAX_TRANSITION_HOPS", "1")
+	stub := &workflowStub{status: "To Do", workflow: simpleWorkflow()}
+	client := newTestClient(t, stub)
+
+	if err := client.UpdateStatus("TEST-1", "Story", "Done"); err == nil {
+		t.Fatal("MAX_TRANSITION_HOPS を超える経路ではエラーになるべきです")
+	}
+	// 残りの遷移回数で到達できないため、中間ステータスへも遷移しない
+	if status, taken := stub.result(); status != "To Do" || len(taken) != 0 {
+		t.Errorf("ステータス = %q (遷移: %v), want To Do", status, taken)
+	}
+}
+
+// transitionStub は TEST-1〜TEST-3 に同じトランジションを返す jiraStub を作成します
+func transitionStub(transitions string) *jiraStub {
+	stub := newJiraStub()
+	for _, key := range []string{"TEST-1", "TEST-2", "TEST-3"} {
+		stub.handle("GET", "/issue/"+key+"/transitions", respondWith(http.StatusOK, transitions))
+		stub.handle("POST", "/issue/"+key+"/transitions", respondWith(http.StatusNoContent, ``))
+	}
+	return stub
+}
+
+// countRequests は method と apiBase からの相対パスが suffix で終わるリクエストの数を返します
+func countRequests(stub *jiraStub, method, suffix string) int {
+	n := 0
+	for _, r := range stub.recorded() {
+		if r.Method == method && strings.HasSuffix(r.Path, suffix) {
+			n++
+		}
+	}
+	return n
+}
+
+func TestTransitionCacheHit(t *testing.T) {
+	stub := transitionStub(`{"transitions":[{"id":"21","to":{"name":"In Progress"}}]}`)
+	client := newTestClient(t, stub)
+
+	for _, key := range []string{"TEST-1", "TEST-2", "TEST-3"} {
+		if err := client.UpdateStatus(key, "Story", "In Progress"); err != nil {
+			t.Fatalf("%s: UpdateStatus がエラーを返しました: %v", key, err)
+		}
+	}
+
+	// 同じイシュータイプ・遷移元ステータスのトランジションは1回だけ取得する
+	if got := countRequests(stub, "GET", "/transitions"); got != 1 {
+		t.Errorf("トランジションの取得回数 = %d, want 1", got)
+	}
+	if got := client.TransitionCallsSaved(); got != 2 {
+		t.Errorf("TransitionCallsSaved() = %d, want 2", got)
+	}
+	if got := countRequests(stub, "POST", "/transitions"); got != 3 {
+		t.Errorf("遷移の回数 = %d, want 3", got)
+	}
+}
+
+func TestTransitionCacheMissPerIssueType(t *testing.T) {
+	stub := transitionStub(`{"transitions":[{"id":"21","to":{"name":"In Progress"}}]}`)
+	client := newTestClient(t, stub)
+
+	client.UpdateStatus("TEST-1", "Story", "In Progress")
+	client.UpdateStatus("TEST-2", "Bug", "In Progress")
+	client.UpdateStatusFrom("TEST-3", "Story", "To Do", "In Progress")
+
+	// イシュータイプと遷移元ステータスの組み合わせが異なればキャッシュは使わない
+	if got := countRequests(stub, "GET", "/transitions"); got != 3 {
+		t.Errorf("トランジションの取得回数 = %d, want 3", got)
+	}
+	if got := client.TransitionCallsSaved(); got != 0 {
+		t.Errorf("TransitionCallsSaved() = %d, want 0", got)
+	}
+}
+
+func TestTransitionCacheRefetchesWhenTargetMissing(t *testing.T) {
+	stub := transitionStub(`{"transitions":[{"id":"21","to":{"name":"In Progress"}}]}`)
+	client := newTestClient(t, stub)
+
+	if err := client.UpdateStatus("TEST-1", "Story", "In Progress"); err != nil {
+		t.Fatal(err)
+	}
+
+	// ワークフローが変更され、キャッシュにない遷移が使えるようになった場合
+	stub.handle("GET", "/issue/TEST-2/transitions", respondWith(http.StatusOK,
+		`{"transitions":[{"id":"21","to":{"name":"In Progress"}},{"id":"51","to":{"name":"In Review"}}]}`))
+	if err := client.UpdateStatus("TEST-2", "Story", "In Review"); err != nil {
+		t.Fatalf("キャッシュにない遷移を取得し直していません: %v", err)
+	}
+	if got := countRequests(stub, "GET", "/transitions"); got != 2 {
+		t.Errorf("トランジションの取得回数 = %d, want 2", got)
+	}
+}
+
+func TestTransitionCacheDisabled(t *testing.T) {
+	t.Setenv("DISABLE_TRANSITION_CACHE", "true")
+	stub := transitionStub(`{"transitions":[{"id":"21","to":{"name":"In Progress"}}]}`)
+	client := newTestClient(t, stub)
+
+	for _, key := range []string{"TEST-1", "TEST-2", "TEST-3"} {
+		if err := client.UpdateStatus(key, "Story", "In Progress"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if got := countRequests(stub, "GET", "/transitions"); got != 3 {
+		t.Errorf("トランジションの取得回数 = %d, want 3", got)
+	}
+	if got := client.TransitionCallsSaved(); got != 0 {
+		t.Errorf("TransitionCallsSaved() = %d, want 0", got)
+	}
+}
+
+func TestTransitionCacheConcurrentFetch(t *testing.T) {
+	stub := transitionStub(`{"transitions":[{"id":"21","to":{"name":"In Progress"}}]}`)
+	client := newTestClient(t, stub)
+
+	// 同時に遷移しても、取得中の組み合わせは完了を待ってキャッシュを使う
+	var wg sync.WaitGroup
+	for _, key := range []string{"TEST-1", "TEST-2", "TEST-3"} {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := client.UpdateStatus(key, "Story", "In Progress"); err != nil {
+				t.Errorf("%s: %v", key, err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if got := countRequests(stub, "GET", "/transitions"); got != 1 {
+		t.Errorf("トランジションの取得回数 = %d, want 1", got)
+	}
+}
+
+func TestCreateIssueIfAbsent(t *testing.T) {
+	tests := []struct {
+		name        string
+		search      string // 検索のレスポンス
+		wantKey     string
+		wantCreated bool
+		wantErr     string // 空の場合はエラーにならない
+	}{
+		{"既存のイシューを使う", `{"issues":[{"key":"TEST-7","fields":{}}]}`, "TEST-7", false, ""},
+		{"見つからない場合は作成する", `{"issues":[]}`, "TEST-8", true, ""},
+		{"複数ある場合はエラー", `{"issues":[{"key":"TEST-7","fields":{}},{"key":"TEST-9","fields":{}}]}`, "", false, "複数あります"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stub := newJiraStub()
+			stub.handle("GET", "/search", respondWith(http.StatusOK, tt.search))
+			stub.handle("POST", "/issue", respondWith(http.StatusCreated, `{"key":"TEST-8"}`))
+			client := newTestClient(t, stub)
+
+			key, created, err := client.CreateIssueIfAbsent("pivotal-100", "ログイン画面", "", []string{"pivotal-100"}, "Story", "", "", nil)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("err = %v, want %q を含むエラー", err, tt.wantErr)
+				}
+			} else if err != nil {
+				t.Fatalf("CreateIssueIfAbsent がエラーを返しました: %v", err)
+			}
+			if key != tt.wantKey || created != tt.wantCreated {
+				t.Errorf("CreateIssueIfAbsent = (%q, %v), want (%q, %v)", key, created, tt.wantKey, tt.wantCreated)
+			}
+
+			searches := stub.requestsTo("GET", "/search")
+			if len(searches) != 1 {
+				t.Fatalf("検索のリクエスト数 = %d, want 1", len(searches))
+			}
+			query, _ := url.ParseQuery(searches[0].Query)
+			if got, want := query.Get("jql"), `project = "TEST" AND labels = "pivotal-100"`; got != want {
+				t.Errorf("jql = %q, want %q", got, want)
+			}
+
+			wantCreates := 0
+			if tt.wantCreated {
+				wantCreates = 1
+			}
+			if got := len(stub.requestsTo("POST", "/issue")); got != wantCreates {
+				t.Errorf("作成のリクエスト数 = %d, want %d", got, wantCreates)
+			}
+		})
+	}
+}
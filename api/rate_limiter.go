@@ -0,0 +1,40 @@
+package api
+
+import (
+	"sync"
+	"time"
+)
+
+// rateLimiter はリクエストの送信間隔を一定以上に保ちます
+type rateLimiter struct {
+	mu       sync.Mutex
+	interval time.Duration
+	next     time.Time
+}
+
+// newRateLimiter は1秒あたり requestsPerSec 件までに制限するリミッターを作成します
+// requestsPerSec が0以下の場合は nil（制限なし）を返します
+func newRateLimiter(requestsPerSec float64) *rateLimiter {
+	if requestsPerSec <= 0 {
+		return nil
+	}
+	return &rateLimiter{interval: time.Duration(float64(time.Second) / requestsPerSec)}
+}
+
+// Wait は次のリクエストを送信できるまで待機します
+func (r *rateLimiter) Wait() {
+	if r == nil {
+		return
+	}
+
+	r.mu.Lock()
+	now := time.Now()
+	if r.next.Before(now) {
+		r.next = now
+	}
+	wait := r.next.Sub(now)
+	r.next = r.next.Add(r.interval)
+	r.mu.Unlock()
+
+	time.Sleep(wait)
+}
@@ -0,0 +1,80 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"time"
+)
+
+// 移行全体の期限・再試行の予算により処理を止めた理由です
+var (
+	ErrDeadlineExceeded     = errors.New("移行の期限 (MIGRATION_DEADLINE) を超えたため中断しました")
+	ErrRetryBudgetExhausted = errors.New("再試行の回数が上限 (RETRY_BUDGET) に達したため中断しました")
+)
+
+// runLimits は移行全体の期限（MIGRATION_DEADLINE）と、すべてのリクエストで共有する再試行の予算（RETRY_BUDGET）を管理します
+// 期限はクライアントの作成時から数え、期限を過ぎると送信中のリクエストも中断します
+type runLimits struct {
+	ctx       context.Context
+	cancel    context.CancelFunc
+	budget    int64 // 再試行できる回数の合計（0の場合は無制限）
+	retries   atomic.Int64
+	exhausted atomic.Bool
+}
+
+// newRunLimits は deadline（0の場合は無制限）と budget（0の場合は無制限）から runLimits を作成します
+func newRunLimits(deadline time.Duration, budget int) *runLimits {
+	l := &runLimits{budget: int64(max(budget, 0))}
+	if deadline > 0 {
+		l.ctx, l.cancel = context.WithTimeout(context.Background(), deadline)
+	} else {
+		l.ctx, l.cancel = context.WithCancel(context.Background())
+	}
+	return l
+}
+
+// stopped は期限を過ぎたか再試行の予算を使い切った場合にその理由を返します
+func (l *runLimits) stopped() error {
+	if l.ctx.Err() != nil {
+		return ErrDeadlineExceeded
+	}
+	if l.exhausted.Load() {
+		return ErrRetryBudgetExhausted
+	}
+	return nil
+}
+
+// useRetry は再試行の予算を1回分使い、再試行してよい場合に true を返します
+func (l *runLimits) useRetry() bool {
+	n := l.retries.Add(1)
+	if l.budget > 0 && n > l.budget {
+		l.retries.Add(-1)
+		l.exhausted.Store(true)
+		return false
+	}
+	return true
+}
+
+// wait は d だけ待機します。待機中に期限を過ぎた場合は ErrDeadlineExceeded を返します
+func (l *runLimits) wait(d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-l.ctx.Done():
+		return ErrDeadlineExceeded
+	}
+}
+
+// Stopped は MIGRATION_DEADLINE を超えたか RETRY_BUDGET を使い切った場合に、その理由のエラーを返します（それ以外は nil）
+// 移行処理は新しい行やファイルの処理を始める前にこれを確認し、処理済みの結果を書き出して中断します
+func (j *JiraClient) Stopped() error {
+	return j.limits.stopped()
+}
+
+// RetryCount はこれまでに再試行したリクエストの回数を返します
+func (j *JiraClient) RetryCount() int64 {
+	return j.limits.retries.Load()
+}
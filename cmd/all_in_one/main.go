@@ -4,6 +4,7 @@ import (
 	"flag"
 	"fmt"
 	"os"
+	"path/filepath"
 	"time"
 
 	"pivotaltojira/api"
@@ -17,7 +18,23 @@ func main() {
 	convertOnly := flag.Bool("convert-only", false, "CSVの変換のみを実行する")
 	importOnly := flag.Bool("import-only", false, "イシューのインポートのみを実行する")
 	attachmentsOnly := flag.Bool("attachments-only", false, "添付ファイルのアップロードのみを実行する")
+	projectKey := flag.String("project", "", "インポート先のJIRAプロジェクトキー（指定しない場合は環境変数から取得）")
 	maxConcurrent := flag.Int("concurrent", 0, "並列処理の最大数（0の場合は設定ファイルの値を使用）")
+	filterState := flag.String("filter-state", "", "指定したPivotalのステータスの行のみインポートする（カンマ区切り）")
+	filterLabel := flag.String("filter-label", "", "指定したラベルのいずれかを含む行のみインポートする（カンマ区切り）")
+	maxErrors := flag.Int("max-errors", 0, "失敗した行がこの数を超えたらインポートを中断する（0の場合は設定ファイルの値を使用）")
+	continueOnAuthWarning := flag.Bool("continue-on-auth-warning", false, "インポート中に認証エラー (401) が返されても中断せずに続行する")
+	noTransitionCache := flag.Bool("no-transition-cache", false, "ステータス遷移のキャッシュを無効にする")
+	updateExisting := flag.Bool("update", false, "JIRA Issue Key が設定済みの行は既存イシューを更新する")
+	var verbose, quiet bool
+	flag.BoolVar(&verbose, "verbose", false, "デバッグログを出力する")
+	flag.BoolVar(&verbose, "v", false, "-verbose の短縮形")
+	flag.BoolVar(&quiet, "quiet", false, "警告とエラーのみ出力する")
+	flag.BoolVar(&quiet, "q", false, "-quiet の短縮形")
+	yes := flag.Bool("yes", false, "本番環境への書き込みの確認 (CONFIRM_PRODUCTION) を省略する")
+	configFile := flag.String("config", "", "設定ファイル(YAML)のパス（環境変数が優先）")
+	envName := flag.String("env", "", "読み込む .env.<名前> の名前（指定しない場合は環境変数 APP_ENV から取得）")
+	showVersion := flag.Bool("version", false, "バージョン情報を表示する")
 	help := flag.Bool("help", false, "ヘルプを表示する")
 
 	// フラグのパース
@@ -29,23 +46,78 @@ func main() {
 		return
 	}
 
+	// バージョンフラグが指定された場合はバージョンを表示
+	if *showVersion {
+		fmt.Printf("%s %s\n", filepath.Base(os.Args[0]), utils.VersionString())
+		return
+	}
+
 	// 開始時間の記録
 	startTime := time.Now()
 
 	// 設定の読み込み
-	cfg, err := config.LoadConfig()
+	config.EnvName = *envName
+	cfg, err := config.LoadConfigFile(*configFile)
 	if err != nil {
-		utils.LogError("設定の読み込みに失敗しました: %v", err)
+		utils.LogError(utils.T("config.load_failed", err))
 		os.Exit(1)
 	}
 
+	// ログレベルの設定（フラグが環境変数より優先）
+	utils.ConfigureLogLevel(verbose, quiet, cfg.LogLevel)
+	utils.SetWarnRepeatLimit(cfg.WarnRepeatLimit)
+	utils.SetLanguage(cfg.Language)
+
+	// プロジェクトキーの上書き（指定された場合のみ）
+	if *projectKey != "" {
+		cfg.JiraProjectKey = *projectKey
+	}
+	utils.LogInfo(utils.T("option.project", cfg.JiraProjectKey))
+
 	// 並列処理数の上書き（指定された場合のみ）
 	if *maxConcurrent > 0 {
 		cfg.MaxConcurrent = *maxConcurrent
 	}
 
-	utils.LogInfo("Pivotal → JIRA 移行ツール (v1.0.0)")
-	utils.LogInfo("設定読み込み完了 (Max Concurrent: %d)", cfg.MaxConcurrent)
+	// インポート対象のフィルター（指定された場合のみ）
+	if *filterState != "" {
+		cfg.FilterStates = config.SplitList(*filterState)
+	}
+	if *filterLabel != "" {
+		cfg.FilterLabels = config.SplitList(*filterLabel)
+	}
+
+	// 中断するエラー数の上書き（指定された場合のみ）
+	if *maxErrors > 0 {
+		cfg.MaxErrors = *maxErrors
+	}
+
+	// 認証エラー (401) でも中断しない（指定された場合のみ）
+	if *continueOnAuthWarning {
+		cfg.ContinueOnAuthWarning = true
+	}
+
+	// 既存イシューの更新モード（指定された場合のみ）
+	if *updateExisting {
+		cfg.UpdateExisting = true
+		utils.LogInfo(utils.T("option.update_existing"))
+	}
+
+	// トランジションキャッシュの無効化（指定された場合のみ）
+	if *noTransitionCache {
+		cfg.DisableTransitionCache = true
+	}
+
+	// 並列数がレート制限に見合っているか確認
+	cfg.CheckConcurrency()
+
+	utils.LogInfo(utils.T("tool.all_in_one", utils.Version))
+	utils.LogInfo(utils.T("config.loaded", cfg.MaxConcurrent))
+
+	// 本番環境への書き込みの確認（CONFIRM_PRODUCTION、変換のみの場合は書き込まないため不要）
+	if !*convertOnly && !cfg.ConfirmProductionWrite(*yes) {
+		os.Exit(1)
+	}
 
 	// 必要なサービスの初期化
 	jiraClient := api.NewJiraClient(cfg)
@@ -55,13 +127,15 @@ func main() {
 	// 移行の実行
 	err = migrationService.RunMigration(*convertOnly, *importOnly, *attachmentsOnly)
 	if err != nil {
-		utils.LogError("移行処理に失敗しました: %v", err)
+		utils.ReportRepeatedWarnings()
+		utils.LogError(utils.T("migration.failed", err))
 		os.Exit(1)
 	}
+	utils.ReportRepeatedWarnings()
 
 	// 合計実行時間の表示
 	elapsed := time.Since(startTime)
-	utils.LogInfo("移行処理が完了しました。合計実行時間: %s", elapsed)
+	utils.LogInfo(utils.T("migration.finished", elapsed))
 }
 
 // ヘルプメッセージを表示する関数
@@ -77,18 +151,98 @@ Pivotal Tracker → JIRA 移行ツール
   -import-only        イシューのインポートのみを実行する
   -attachments-only   添付ファイルのアップロードのみを実行する
   -concurrent=N       並列処理の最大数を指定する
+  -project キー       インポート先のJIRAプロジェクトキー (JIRA_PROJECT_KEY より優先)
+  -filter-state 値    指定したPivotalのステータスの行のみインポートする (カンマ区切り, 例: accepted)
+  -filter-label 値    指定したラベルのいずれかを含む行のみインポートする (カンマ区切り)
+  -max-errors 件数    失敗した行がこの数を超えたらインポートを中断する (MAX_ERRORS より優先)
+  -continue-on-auth-warning  インポート中に認証エラー (401) が返されても中断せずに続行する
+  -no-transition-cache  ステータス遷移のキャッシュを無効にする
+  -update             JIRA Issue Key が設定済みの行は既存イシューを更新する
+  -v, -verbose        デバッグログを出力する
+  -q, -quiet          警告とエラーのみ出力する
+  -yes                本番環境への書き込みの確認 (CONFIRM_PRODUCTION) を省略する
+  -config=PATH        設定ファイル(YAML)のパス (環境変数とフラグが優先)
+  -env=NAME           .env に加えて .env.NAME を読み込む (APP_ENV でも指定可, .env.NAME が優先)
+  -version            バージョン情報を表示する
   -help               このヘルプを表示する
 
 環境変数:
   JIRA_URL            JIRA URL (必須)
-  JIRA_EMAIL          JIRA APIアカウントのメールアドレス (必須)
-  JIRA_API_TOKEN      JIRA APIトークン (必須)
+  JIRA_CONTEXT_PATH   コンテキストパス付きのServer/Data Center の場合のパス (例: /jira)
+  JIRA_DEPLOYMENT     JIRAのデプロイ形態 cloud/server (デフォルト: cloud)
+  JIRA_EMAIL          JIRA APIアカウントのメールアドレス (server の場合はユーザー名, 必須)
+  JIRA_API_TOKEN      JIRA APIトークン (server の場合はパスワード, 必須)
+  JIRA_PROXY          JIRAへの接続に使うプロキシURL (未指定時は HTTP_PROXY/HTTPS_PROXY を使用)
+  JIRA_CA_CERT        信頼するCA証明書(PEM)のパス
+  JIRA_INSECURE_SKIP_VERIFY  TLS証明書の検証をスキップする (非推奨, デフォルト: false)
+  CONFIRM_PRODUCTION  本番環境と判定したJIRAに書き込む前に確認する (デフォルト: false)
+  PRODUCTION_URL_PATTERN  本番環境と判定するJIRA_URLの正規表現 (未指定時は NON_PRODUCTION_URLS 以外すべて)
+  NON_PRODUCTION_URLS 確認なしで書き込んでよいJIRA_URLまたはホスト名 (カンマ区切り)
   JIRA_PROJECT_KEY    JIRAプロジェクトキー (必須)
   JIRA_STORY_POINT_FIELD  JIRAのストーリーポイントフィールドID (デフォルト: customfield_10016)
+  RESOLUTION_MAPPING  ステータス遷移時に設定する解決状況 (例: Done:Done,受け入れ済み:Done)
+  TRANSITION_PATHS    目的ステータスまでの遷移経路 (例: 受け入れ済み:進行中>REVIEWS>受け入れ済み)
+  DISABLE_TRANSITION_CACHE  ステータス遷移のキャッシュを無効にする (デフォルト: false)
+  MAX_TRANSITION_HOPS 目的ステータスへ到達するまでの最大遷移回数 (デフォルト: 5)
+  STRICT_STATUS       ステータス遷移に失敗した行をエラーとして扱う（作成したキーは残し、再実行時は遷移のみ行う） (デフォルト: false)
+  SKIP_STATUSES       遷移不要として扱うステータス (カンマ区切り, 大文字小文字を区別しない, デフォルト: backlog)
+  PRESERVE_RANK       Pivotalの並び順をJIRAのランクに反映する (デフォルト: false)
+  UPDATE_EXISTING     JIRA Issue Key が設定済みの行は既存イシューを更新する (デフォルト: false)
+  DEDUP_ON_CREATE     作成前にPivotal IDのラベルで検索し、既存イシューがあれば作成しない (デフォルト: false)
+  EPICS_FIRST         エピックをすべて作成してから他のイシューを作成する (デフォルト: false)
+  SKIP_COMMENTS       コメントを追加しない (デフォルト: false)
+  COMMENT_VISIBILITY  コメントを閲覧できる範囲 role:ロール名 / group:グループ名 (デフォルト: 制限しない)
+  COMMENT_INTERNAL_MARKER  この文字列で始まるコメントのみ COMMENT_VISIBILITY で制限する (デフォルト: すべて制限)
+  SKIP_STATUS_UPDATE  ステータスを遷移しない (デフォルト: false)
+  SKIP_STORY_POINTS   ストーリーポイントを設定しない (デフォルト: false)
+  RELEASES_AS_VERSIONS  リリース行をJIRAのバージョンとして作成する (デフォルト: false)
+  FILTER_STATES       インポートするPivotalのステータス (カンマ区切り, デフォルト: すべて)
+  FILTER_LABELS       いずれかを含む行のみインポートするラベル (カンマ区切り, デフォルト: すべて)
+  MAX_ERRORS          失敗した行がこの数を超えたらインポートを中断する (デフォルト: 0 = 無制限)
+  CONTINUE_ON_AUTH_WARNING  インポート中に認証エラー (401) が返されても中断せずに続行する (デフォルト: false)
+  PIVOTAL_ID_LABEL_PREFIX  Pivotal ID ラベルの接頭辞 (デフォルト: pivotal-, 空にすると付与しない)
+  GLOBAL_LABELS            すべてのイシューに付与するラベル (カンマ区切り, 例: migrated-2024)
+  PIVOTAL_ID_FIELD    Pivotal IDを保存する文字列のカスタムフィールドID (デフォルト: 保存しない)
+  ORIGINAL_CREATED_FIELD   Pivotalでの作成日時を保存する日付のカスタムフィールドID (未指定時は説明文に追記)
+  ORIGINAL_RESOLVED_FIELD  Pivotalでの受け入れ日時を保存する日付のカスタムフィールドID (未指定時は説明文に追記)
+  CUSTOM_DATE_FIELD_MAP    Pivotalの日時のカラムと保存する日付のカスタムフィールドID (例: Started at:customfield_10060)
+  JIRA_REVIEWER_FIELD      Reviewers 列のユーザーを保存するユーザーピッカー(複数)のカスタムフィールドID (未指定時は説明文に追記)
+  SUBTASK_ISSUE_TYPE  Pivotalのタスクから作成するサブタスクのイシュータイプ (デフォルト: Sub-task)
+  DEFAULT_ISSUE_TYPE  マッピングにない種別のストーリーに使うイシュータイプ (デフォルト: Task)
+  TYPE_MAPPING_FILE   種別ごとのイシュータイプと初期ステータスのマッピングJSON
+  SUMMARY_PREFIX_FORMAT  サマリーのテンプレート (デフォルト: [{id}] {title})
+  MIGRATION_FOOTER    説明文の末尾に追記するフッター ({date}, {version}, {id} が使用可能, デフォルト: 追記しない)
+  ASSIGNEE_NOTE_PREFIX  マッピングにない担当者を説明文に追記する際の見出し (デフォルト: 担当者:)
+  REPORTER_NOTE_PREFIX  マッピングにない報告者を説明文に追記する際の見出し (デフォルト: 報告者:)
   PIVOTAL_CSV         Pivotal Trackerから出力したCSVファイルパス (デフォルト: project_history.csv)
   JIRA_CSV            JIRA用に変換したCSVファイルパス (デフォルト: jira_import_ready.csv)
-  ATTACHMENTS_FOLDER  添付ファイルのフォルダパス (デフォルト: attachments)
+  JIRA_CSV_HEADERS    JIRA CSVに出力するカラムの順序 (カンマ区切り)
+  MERGE_COLUMNS       重複時に結合するカラム (例: Comment,Label:comma,Owned By:newline)
+  HEADER_ALIASES      Pivotal CSVのヘッダーの別名 (例: Status:Current State, State などはデフォルトで対応)
+  COMMENT_ORDER       コメントを結合する順序 source/oldest-first/newest-first (デフォルト: source)
+  STATUS_MAPPING_FILE イシュータイプ別のステータスマッピングJSON
+  MAPPING_JSON        Pivotal ID → JIRA Key のマッピングJSON (デフォルト: id_mapping.json)
+  IMPORT_JOURNAL      作成したイシューを作成直後に追記するジャーナル (デフォルト: import_journal.log)
+  ATTACHMENTS_FOLDER  添付ファイルのフォルダパス (カンマ区切り・グロブで複数指定可, デフォルト: attachments)
+  ATTACHMENT_BATCH_MB 1回のリクエストでまとめてアップロードする合計サイズ (MB, デフォルト: 10, 0で1ファイルずつ)
+  SKIP_EXISTING_ATTACHMENTS  イシューに同じ名前の添付ファイルがある場合はアップロードしない (デフォルト: false)
+  ATTACHMENT_MATCH_SIZE  SKIP_EXISTING_ATTACHMENTS でファイルサイズも一致する場合のみスキップする (デフォルト: false)
+  SUMMARY_JSON        移行結果の集計を書き出すJSONファイル (デフォルト: migration_summary.json)
   MAX_CONCURRENT      並列処理の最大数 (デフォルト: 10)
+  MAX_IDLE_CONNS_PER_HOST  ホストごとのアイドル接続数 (デフォルト: MAX_CONCURRENT)
+  MAX_CONNS_PER_HOST  ホストごとの最大接続数 (デフォルト: MAX_CONCURRENTの2倍)
+  IDLE_CONN_TIMEOUT   アイドル接続を保持する秒数 (デフォルト: 90)
+  JIRA_REQUEST_TIMEOUT  JIRAへの1回のリクエストのタイムアウト秒数 (デフォルト: 60, 0で無制限)
+  JIRA_UPLOAD_TIMEOUT  添付ファイルのアップロード1回のタイムアウト秒数 (デフォルト: 600, 0で無制限)
+  MIGRATION_DEADLINE   移行全体の期限 (例: 2h, 30m。超えると処理済みの結果を書き出して中断。未指定で無制限)
+  RETRY_BUDGET         全リクエストで共有する再試行回数の上限 (デフォルト: 0 = 無制限)
+  JIRA_RATE_LIMIT     1秒あたりの最大リクエスト数 (デフォルト: 0 = 制限なし)
+  AUTO_CLAMP_CONCURRENCY  並列数をレート制限に見合う値に自動で抑える (デフォルト: false)
+  AUTO_TUNE_CONCURRENCY   429の発生状況に応じて同時リクエスト数を MAX_CONCURRENT 以下で自動調整する (デフォルト: false)
+  LOG_LEVEL           ログレベル (debug/info/warn/error, デフォルト: info)
+  WARN_REPEAT_LIMIT   同じ警告を出力する最大回数 (超えた分は最後に回数を出力, デフォルト: 5, 0で省略しない)
+  TOOL_LANG           ログメッセージの言語 (ja/en, 未指定時は LANG, デフォルト: ja)
+  ORDERED_LOG         インポート中の行ごとのログを元の行順に並べて出力する (デフォルト: false)
 
 例:
   # すべての処理を実行
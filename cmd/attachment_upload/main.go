@@ -4,6 +4,7 @@ import (
 	"flag"
 	"fmt"
 	"os"
+	"path/filepath"
 	"time"
 
 	"pivotaltojira/api"
@@ -17,6 +18,16 @@ func main() {
 	jiraCSV := flag.String("csv", "", "JIRAイシューマッピングCSVファイルのパス（指定しない場合は環境変数から取得）")
 	attachmentsFolder := flag.String("folder", "", "添付ファイルのフォルダパス（指定しない場合は環境変数から取得）")
 	maxConcurrent := flag.Int("concurrent", 0, "並列処理の最大数（0の場合は設定ファイルの値を使用）")
+	skipExisting := flag.Bool("skip-existing", false, "イシューに同じ名前の添付ファイルがある場合はアップロードしない")
+	var verbose, quiet bool
+	flag.BoolVar(&verbose, "verbose", false, "デバッグログを出力する")
+	flag.BoolVar(&verbose, "v", false, "-verbose の短縮形")
+	flag.BoolVar(&quiet, "quiet", false, "警告とエラーのみ出力する")
+	flag.BoolVar(&quiet, "q", false, "-quiet の短縮形")
+	yes := flag.Bool("yes", false, "本番環境への書き込みの確認 (CONFIRM_PRODUCTION) を省略する")
+	configFile := flag.String("config", "", "設定ファイル(YAML)のパス（環境変数が優先）")
+	envName := flag.String("env", "", "読み込む .env.<名前> の名前（指定しない場合は環境変数 APP_ENV から取得）")
+	showVersion := flag.Bool("version", false, "バージョン情報を表示する")
 	help := flag.Bool("help", false, "ヘルプを表示する")
 
 	// フラグのパース
@@ -28,44 +39,63 @@ func main() {
 		return
 	}
 
+	// バージョンフラグが指定された場合はバージョンを表示
+	if *showVersion {
+		fmt.Printf("%s %s\n", filepath.Base(os.Args[0]), utils.VersionString())
+		return
+	}
+
 	// 開始時間の記録
 	startTime := time.Now()
 
-	utils.LogInfo("JIRA 添付ファイルアップロードツール")
+	utils.LogInfo(utils.T("tool.attachment_upload"))
 
 	// 設定の読み込み
-	cfg, err := config.LoadConfig()
+	config.EnvName = *envName
+	cfg, err := config.LoadConfigFile(*configFile)
 	if err != nil {
-		utils.LogError("設定の読み込みに失敗しました: %v", err)
+		utils.LogError(utils.T("config.load_failed", err))
 		os.Exit(1)
 	}
 
+	// ログレベルの設定（フラグが環境変数より優先）
+	utils.ConfigureLogLevel(verbose, quiet, cfg.LogLevel)
+	utils.SetWarnRepeatLimit(cfg.WarnRepeatLimit)
+	utils.SetLanguage(cfg.Language)
+
 	// コマンドラインでパスが指定された場合、設定を上書き
 	if *jiraCSV != "" {
 		cfg.JiraCSV = *jiraCSV
-		utils.LogInfo("CSVファイルを指定: %s", cfg.JiraCSV)
+		utils.LogInfo(utils.T("option.csv_file", cfg.JiraCSV))
 	}
 
 	if *attachmentsFolder != "" {
 		cfg.AttachmentsFolder = *attachmentsFolder
-		utils.LogInfo("添付ファイルフォルダを指定: %s", cfg.AttachmentsFolder)
+		utils.LogInfo(utils.T("option.attachments_folder", cfg.AttachmentsFolder))
 	}
 
 	// 並列処理数の上書き（指定された場合のみ）
 	if *maxConcurrent > 0 {
 		cfg.MaxConcurrent = *maxConcurrent
-		utils.LogInfo("並列処理数を指定: %d", cfg.MaxConcurrent)
+		utils.LogInfo(utils.T("option.concurrent", cfg.MaxConcurrent))
 	}
 
+	if *skipExisting {
+		cfg.SkipExistingAttachments = true
+	}
+
+	// 並列数がレート制限に見合っているか確認
+	cfg.CheckConcurrency()
+
 	// JIRA認証情報の確認
-	utils.LogInfo("JIRA認証情報を確認しています...")
+	utils.LogInfo(utils.T("auth.checking"))
 	jiraClient := api.NewJiraClient(cfg)
 	if err := jiraClient.CheckAuth(); err != nil {
-		utils.LogError("JIRA認証エラー: %v", err)
-		utils.LogError("JIRAの認証情報を確認してください。")
+		utils.LogError(utils.T("auth.error", err))
+		utils.LogError(api.AuthErrorHint(err))
 		os.Exit(1)
 	}
-	utils.LogInfo("JIRA認証成功")
+	utils.LogInfo(utils.T("auth.success"))
 
 	// CSVプロセッサの初期化
 	csvProc := services.NewCSVProcessor(cfg)
@@ -75,27 +105,39 @@ func main() {
 
 	// CSVファイルの存在確認
 	if _, err := os.Stat(cfg.JiraCSV); os.IsNotExist(err) {
-		utils.LogError("JIRAイシューマッピングCSVファイルが見つかりません: %s", cfg.JiraCSV)
-		utils.LogError("先に issue_import ツールを実行して、JIRAイシューを作成してください。")
+		utils.LogError(utils.T("attachments.mapping_not_found", cfg.JiraCSV))
+		utils.LogError(utils.T("attachments.run_issue_import"))
 		os.Exit(1)
 	}
 
 	// 添付ファイルフォルダの確認
-	if _, err := os.Stat(cfg.AttachmentsFolder); os.IsNotExist(err) {
-		utils.LogError("添付ファイルフォルダが見つかりません: %s", cfg.AttachmentsFolder)
+	folders, err := cfg.AttachmentFolders()
+	if err != nil {
+		utils.LogError(utils.T("attachments.error", err))
+		os.Exit(1)
+	}
+	if len(folders) == 0 {
+		utils.LogError(utils.T("attachments.folder_not_found", cfg.AttachmentsFolder))
+		os.Exit(1)
+	}
+
+	// 本番環境への書き込みの確認（CONFIRM_PRODUCTION）
+	if !cfg.ConfirmProductionWrite(*yes) {
 		os.Exit(1)
 	}
 
 	// 添付ファイルのアップロード実行
-	utils.LogInfo("添付ファイルのアップロードを開始します...")
+	utils.LogInfo(utils.T("attachments.start_cli"))
 	if err := migrationService.UploadAttachments(); err != nil {
-		utils.LogError("添付ファイルアップロードエラー: %v", err)
+		utils.ReportRepeatedWarnings()
+		utils.LogError(utils.T("attachments.error", err))
 		os.Exit(1)
 	}
+	utils.ReportRepeatedWarnings()
 
 	// 処理時間の表示
 	elapsed := time.Since(startTime)
-	utils.LogInfo("添付ファイルのアップロードが完了しました。処理時間: %s", elapsed)
+	utils.LogInfo(utils.T("attachments.finished", elapsed))
 }
 
 // ヘルプメッセージを表示する関数
@@ -110,15 +152,46 @@ JIRA 添付ファイルアップロードツール
   -csv ファイル        JIRAイシューマッピングCSV
   -folder パス         添付ファイルのフォルダパス
   -concurrent 数       並列処理の最大数
+  -skip-existing      イシューに同じ名前の添付ファイルがある場合はアップロードしない
+  -v, -verbose        デバッグログを出力する
+  -q, -quiet          警告とエラーのみ出力する
+  -yes                本番環境への書き込みの確認 (CONFIRM_PRODUCTION) を省略する
+  -config=PATH        設定ファイル(YAML)のパス (環境変数とフラグが優先)
+  -env=NAME           .env に加えて .env.NAME を読み込む (APP_ENV でも指定可, .env.NAME が優先)
+  -version            バージョン情報を表示する
   -help                このヘルプを表示する
 
 環境変数:
   JIRA_URL            JIRA URL (必須)
-  JIRA_EMAIL          JIRA APIアカウントのメールアドレス (必須)
-  JIRA_API_TOKEN      JIRA APIトークン (必須)
+  JIRA_CONTEXT_PATH   コンテキストパス付きのServer/Data Center の場合のパス (例: /jira)
+  JIRA_DEPLOYMENT     JIRAのデプロイ形態 cloud/server (デフォルト: cloud)
+  JIRA_EMAIL          JIRA APIアカウントのメールアドレス (server の場合はユーザー名, 必須)
+  JIRA_API_TOKEN      JIRA APIトークン (server の場合はパスワード, 必須)
+  JIRA_PROXY          JIRAへの接続に使うプロキシURL (未指定時は HTTP_PROXY/HTTPS_PROXY を使用)
+  JIRA_CA_CERT        信頼するCA証明書(PEM)のパス
+  JIRA_INSECURE_SKIP_VERIFY  TLS証明書の検証をスキップする (非推奨, デフォルト: false)
+  CONFIRM_PRODUCTION  本番環境と判定したJIRAに書き込む前に確認する (デフォルト: false)
+  PRODUCTION_URL_PATTERN  本番環境と判定するJIRA_URLの正規表現 (未指定時は NON_PRODUCTION_URLS 以外すべて)
+  NON_PRODUCTION_URLS 確認なしで書き込んでよいJIRA_URLまたはホスト名 (カンマ区切り)
   JIRA_CSV            JIRAイシューマッピングCSVファイルパス (デフォルト: jira_import_ready.csv)
-  ATTACHMENTS_FOLDER  添付ファイルのフォルダパス (デフォルト: attachments)
+  ATTACHMENTS_FOLDER  添付ファイルのフォルダパス (カンマ区切り・グロブで複数指定可, デフォルト: attachments)
+  ATTACHMENT_BATCH_MB 1回のリクエストでまとめてアップロードする合計サイズ (MB, デフォルト: 10, 0で1ファイルずつ)
+  SKIP_EXISTING_ATTACHMENTS  イシューに同じ名前の添付ファイルがある場合はアップロードしない (デフォルト: false)
+  ATTACHMENT_MATCH_SIZE  SKIP_EXISTING_ATTACHMENTS でファイルサイズも一致する場合のみスキップする (デフォルト: false)
   MAX_CONCURRENT      並列処理の最大数 (デフォルト: 10)
+  MAX_IDLE_CONNS_PER_HOST  ホストごとのアイドル接続数 (デフォルト: MAX_CONCURRENT)
+  MAX_CONNS_PER_HOST  ホストごとの最大接続数 (デフォルト: MAX_CONCURRENTの2倍)
+  IDLE_CONN_TIMEOUT   アイドル接続を保持する秒数 (デフォルト: 90)
+  JIRA_REQUEST_TIMEOUT  JIRAへの1回のリクエストのタイムアウト秒数 (デフォルト: 60, 0で無制限)
+  JIRA_UPLOAD_TIMEOUT  添付ファイルのアップロード1回のタイムアウト秒数 (デフォルト: 600, 0で無制限)
+  MIGRATION_DEADLINE   移行全体の期限 (例: 2h, 30m。超えると処理済みの結果を書き出して中断。未指定で無制限)
+  RETRY_BUDGET         全リクエストで共有する再試行回数の上限 (デフォルト: 0 = 無制限)
+  JIRA_RATE_LIMIT     1秒あたりの最大リクエスト数 (デフォルト: 0 = 制限なし)
+  AUTO_CLAMP_CONCURRENCY  並列数をレート制限に見合う値に自動で抑える (デフォルト: false)
+  AUTO_TUNE_CONCURRENCY   429の発生状況に応じて同時リクエスト数を MAX_CONCURRENT 以下で自動調整する (デフォルト: false)
+  LOG_LEVEL           ログレベル (debug/info/warn/error, デフォルト: info)
+  WARN_REPEAT_LIMIT   同じ警告を出力する最大回数 (超えた分は最後に回数を出力, デフォルト: 5, 0で省略しない)
+  TOOL_LANG           ログメッセージの言語 (ja/en, 未指定時は LANG, デフォルト: ja)
 
 説明:
   このツールはPivotal Trackerからエクスポートした添付ファイルを
@@ -133,6 +206,9 @@ JIRA 添付ファイルアップロードツール
             ├── file3.docx
             └── file4.png
 
+  ATTACHMENTS_FOLDER に複数のフォルダを指定した場合、同じPivotal IDのフォルダにある
+  ファイルはすべて同じイシューにアップロードします（同名のファイルは "_1" などを付けて区別します）。
+
   CSVファイルの"JIRA Issue ID"と"JIRA Issue Key"列を使って
   Pivotal IDとJIRAイシューキーの対応関係を特定します。
 `, os.Args[0])
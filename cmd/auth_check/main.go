@@ -4,6 +4,7 @@ import (
 	"flag"
 	"fmt"
 	"os"
+	"path/filepath"
 
 	"pivotaltojira/api"
 	"pivotaltojira/config"
@@ -11,7 +12,15 @@ import (
 )
 
 func main() {
-	// ヘルプフラグの定義
+	// コマンドラインフラグの定義
+	var verbose, quiet bool
+	flag.BoolVar(&verbose, "verbose", false, "デバッグログを出力する")
+	flag.BoolVar(&verbose, "v", false, "-verbose の短縮形")
+	flag.BoolVar(&quiet, "quiet", false, "警告とエラーのみ出力する")
+	flag.BoolVar(&quiet, "q", false, "-quiet の短縮形")
+	configFile := flag.String("config", "", "設定ファイル(YAML)のパス（環境変数が優先）")
+	envName := flag.String("env", "", "読み込む .env.<名前> の名前（指定しない場合は環境変数 APP_ENV から取得）")
+	showVersion := flag.Bool("version", false, "バージョン情報を表示する")
 	help := flag.Bool("help", false, "ヘルプを表示する")
 
 	// フラグのパース
@@ -23,29 +32,67 @@ func main() {
 		return
 	}
 
-	utils.LogInfo("JIRA認証確認ツール")
+	// バージョンフラグが指定された場合はバージョンを表示
+	if *showVersion {
+		fmt.Printf("%s %s\n", filepath.Base(os.Args[0]), utils.VersionString())
+		return
+	}
+
+	utils.LogInfo(utils.T("tool.auth_check"))
 
 	// 設定の読み込み
-	cfg, err := config.LoadConfig()
+	config.EnvName = *envName
+	cfg, err := config.LoadConfigFile(*configFile)
 	if err != nil {
-		utils.LogError("設定の読み込みに失敗しました: %v", err)
+		utils.LogError(utils.T("config.load_failed", err))
 		os.Exit(1)
 	}
 
+	// ログレベルの設定（フラグが環境変数より優先）
+	utils.ConfigureLogLevel(verbose, quiet, cfg.LogLevel)
+	utils.SetLanguage(cfg.Language)
+
 	// JIRAクライアントの初期化
 	jiraClient := api.NewJiraClient(cfg)
 
 	// 認証チェック
-	utils.LogInfo("JIRA APIの認証を確認しています...")
-	err = jiraClient.CheckAuth()
+	utils.LogInfo(utils.T("auth.checking_api"))
+	user, err := jiraClient.VerifyAuth()
+	if err != nil {
+		utils.LogError(utils.T("auth.error", err))
+		utils.LogError(api.AuthErrorHint(err))
+		os.Exit(1)
+	}
+
+	utils.LogInfo(utils.T("auth.success_url", cfg.JiraURL))
+	utils.LogInfo(utils.T("auth.display_name", user.DisplayName))
+	utils.LogInfo(utils.T("auth.email", user.EmailAddress))
+	if user.AccountID != "" {
+		utils.LogInfo(utils.T("auth.account_id", user.AccountID))
+	} else {
+		utils.LogInfo(utils.T("auth.username", user.Name))
+	}
+
+	// プロジェクトキーの確認
+	utils.LogInfo(utils.T("auth.checking_project", cfg.JiraProjectKey))
+	if err := jiraClient.VerifyProject(); err != nil {
+		utils.LogError(utils.T("auth.project_error", err))
+		utils.LogError(utils.T("auth.check_project_key"))
+		os.Exit(1)
+	}
+
+	// イシュー作成権限の確認
+	canCreate, err := jiraClient.CanCreateIssues()
 	if err != nil {
-		utils.LogError("JIRA認証エラー: %v", err)
-		utils.LogError("認証情報を確認してください。")
+		utils.LogWarn(utils.T("auth.create_permission_unknown", err))
+	} else if !canCreate {
+		utils.LogError(utils.T("auth.create_permission_denied", cfg.JiraProjectKey))
 		os.Exit(1)
+	} else {
+		utils.LogInfo(utils.T("auth.create_permission_ok", cfg.JiraProjectKey))
 	}
 
-	utils.LogInfo("JIRA認証成功！ 接続先: %s", cfg.JiraURL)
-	utils.LogInfo("JIRA APIの認証情報は正常です。")
+	utils.LogInfo(utils.T("auth.all_ok"))
 }
 
 // ヘルプメッセージを表示する関数
@@ -57,15 +104,29 @@ JIRA認証確認ツール
   %s [オプション]
 
 オプション:
+  -v, -verbose        デバッグログを出力する
+  -q, -quiet          警告とエラーのみ出力する
+  -config=PATH        設定ファイル(YAML)のパス (環境変数とフラグが優先)
+  -env=NAME           .env に加えて .env.NAME を読み込む (APP_ENV でも指定可, .env.NAME が優先)
+  -version            バージョン情報を表示する
   -help               このヘルプを表示する
 
 環境変数:
   JIRA_URL            JIRA URL (必須)
-  JIRA_EMAIL          JIRA APIアカウントのメールアドレス (必須)
-  JIRA_API_TOKEN      JIRA APIトークン (必須)
+  JIRA_CONTEXT_PATH   コンテキストパス付きのServer/Data Center の場合のパス (例: /jira)
+  JIRA_DEPLOYMENT     JIRAのデプロイ形態 cloud/server (デフォルト: cloud)
+  JIRA_EMAIL          JIRA APIアカウントのメールアドレス (server の場合はユーザー名, 必須)
+  JIRA_API_TOKEN      JIRA APIトークン (server の場合はパスワード, 必須)
+  JIRA_PROJECT_KEY    JIRAプロジェクトキー (必須)
+  JIRA_PROXY          JIRAへの接続に使うプロキシURL (未指定時は HTTP_PROXY/HTTPS_PROXY を使用)
+  JIRA_CA_CERT        信頼するCA証明書(PEM)のパス
+  JIRA_INSECURE_SKIP_VERIFY  TLS証明書の検証をスキップする (非推奨, デフォルト: false)
+  LOG_LEVEL           ログレベル (debug/info/warn/error, デフォルト: info)
+  TOOL_LANG           ログメッセージの言語 (ja/en, 未指定時は LANG, デフォルト: ja)
 
 説明:
-  このツールはJIRA APIの認証情報が正しく設定されているかを確認します。
+  このツールはJIRA APIの認証情報とプロジェクトキーが正しく設定されているかを確認します。
+  認証したユーザーの情報と、対象プロジェクトでのイシュー作成権限も表示します。
   認証が成功すれば、他のツールも正常に動作する可能性が高いです。
 `, os.Args[0])
 }
@@ -4,6 +4,9 @@ import (
 	"flag"
 	"fmt"
 	"os"
+	"path/filepath"
+	"slices"
+	"strings"
 	"time"
 
 	"pivotaltojira/config"
@@ -15,6 +18,17 @@ func main() {
 	// コマンドラインフラグの定義
 	pivotalCSV := flag.String("input", "", "Pivotal Tracker CSVファイルのパス（指定しない場合は環境変数から取得）")
 	jiraCSV := flag.String("output", "", "JIRA用に変換されたCSVの出力先（指定しない場合は環境変数から取得）")
+	outputFormat := flag.String("output-format", services.OutputFormatCSV, "出力形式 (csv/json/ndjson)")
+	syncMode := flag.Bool("sync", false, "読み込み・変換・書き込みを並行せず、全行を読み込んでから順に処理する")
+	appendMode := flag.Bool("append", false, "出力先のJIRA CSVが既にある場合は上書きせず追記する（JIRA Issue ID が重複する行は追記しない）")
+	var verbose, quiet bool
+	flag.BoolVar(&verbose, "verbose", false, "デバッグログを出力する")
+	flag.BoolVar(&verbose, "v", false, "-verbose の短縮形")
+	flag.BoolVar(&quiet, "quiet", false, "警告とエラーのみ出力する")
+	flag.BoolVar(&quiet, "q", false, "-quiet の短縮形")
+	configFile := flag.String("config", "", "設定ファイル(YAML)のパス（環境変数が優先）")
+	envName := flag.String("env", "", "読み込む .env.<名前> の名前（指定しない場合は環境変数 APP_ENV から取得）")
+	showVersion := flag.Bool("version", false, "バージョン情報を表示する")
 	help := flag.Bool("help", false, "ヘルプを表示する")
 
 	// フラグのパース
@@ -26,59 +40,113 @@ func main() {
 		return
 	}
 
+	// バージョンフラグが指定された場合はバージョンを表示
+	if *showVersion {
+		fmt.Printf("%s %s\n", filepath.Base(os.Args[0]), utils.VersionString())
+		return
+	}
+
 	// 開始時間の記録
 	startTime := time.Now()
 
-	utils.LogInfo("Pivotal CSV → JIRA CSV 変換ツール")
+	utils.LogInfo(utils.T("tool.csv_convert"))
 
 	// 設定の読み込み
-	cfg, err := config.LoadConfig()
+	config.EnvName = *envName
+	cfg, err := config.LoadConfigFile(*configFile)
 	if err != nil {
-		utils.LogError("設定の読み込みに失敗しました: %v", err)
+		utils.LogError(utils.T("config.load_failed", err))
+		os.Exit(1)
+	}
+
+	// ログレベルの設定（フラグが環境変数より優先）
+	utils.ConfigureLogLevel(verbose, quiet, cfg.LogLevel)
+	utils.SetWarnRepeatLimit(cfg.WarnRepeatLimit)
+	utils.SetLanguage(cfg.Language)
+
+	if !slices.Contains(services.OutputFormats, *outputFormat) {
+		utils.LogError(utils.T("convert.invalid_output_format", *outputFormat, strings.Join(services.OutputFormats, "/")))
+		os.Exit(1)
+	}
+	if *appendMode && *outputFormat != services.OutputFormatCSV {
+		utils.LogError(utils.T("convert.append_format"))
 		os.Exit(1)
 	}
 
 	// コマンドラインでパスが指定された場合、設定を上書き
 	if *pivotalCSV != "" {
 		cfg.PivotalCSV = *pivotalCSV
-		utils.LogInfo("入力ファイルを指定: %s", cfg.PivotalCSV)
+		utils.LogInfo(utils.T("option.input_file", cfg.PivotalCSV))
 	}
 
 	if *jiraCSV != "" {
 		cfg.JiraCSV = *jiraCSV
-		utils.LogInfo("出力ファイルを指定: %s", cfg.JiraCSV)
+		utils.LogInfo(utils.T("option.output_file", cfg.JiraCSV))
 	}
 
 	// CSVプロセッサの初期化
 	csvProc := services.NewCSVProcessor(cfg)
 
+	// 読み込み・変換・書き込みを並行して行う（-sync の場合は従来どおり順に処理する）
+	// -append は既存のCSVと重複を確認してから追記するため、全行を変換してから書き込む
+	if !*syncMode && !*appendMode {
+		utils.LogInfo(utils.T("convert.streaming", cfg.PivotalCSV, cfg.JiraCSV))
+		coverage, err := csvProc.ConvertPivotalCSVStream(*outputFormat)
+		utils.ReportRepeatedWarnings()
+		if err != nil {
+			utils.LogError(utils.T("convert.error", err))
+			os.Exit(1)
+		}
+		utils.LogInfo(utils.T("convert.finished", coverage.Rows, time.Since(startTime)))
+		return
+	}
+
 	// Pivotal CSVの読み込み
-	utils.LogInfo("Pivotal CSVを読み込んでいます: %s", cfg.PivotalCSV)
+	utils.LogInfo(utils.T("convert.reading", cfg.PivotalCSV))
 	records, err := csvProc.ReadPivotalCSV()
 	if err != nil {
-		utils.LogError("Pivotal CSV読み込みエラー: %v", err)
+		utils.LogError(utils.T("convert.read_error", err))
 		os.Exit(1)
 	}
-	utils.LogInfo("Pivotal CSVを読み込みました: %d 件のレコード", len(records))
+	utils.LogInfo(utils.T("convert.read_done", len(records)))
 
 	// JIRA形式に変換
-	utils.LogInfo("JIRAフォーマットに変換しています...")
+	utils.LogInfo(utils.T("convert.converting"))
 	jiraRecords, err := csvProc.ProcessPivotalToJiraCSV(records)
 	if err != nil {
-		utils.LogError("CSV変換エラー: %v", err)
+		utils.LogError(utils.T("convert.error", err))
 		os.Exit(1)
 	}
 
-	// JIRA CSVとして保存
-	utils.LogInfo("JIRA CSVとして保存しています: %s", cfg.JiraCSV)
-	if err := csvProc.WriteJiraCSV(jiraRecords); err != nil {
-		utils.LogError("JIRA CSV書き込みエラー: %v", err)
+	// マッピングできなかったステータス・種別・ユーザーを報告
+	csvProc.ValidateConversion(jiraRecords)
+
+	// 既存のJIRA CSVに追記
+	if *appendMode {
+		utils.LogInfo(utils.T("convert.appending", cfg.JiraCSV))
+		appended, err := csvProc.AppendJiraCSV(jiraRecords)
+		utils.ReportRepeatedWarnings()
+		if err != nil {
+			utils.LogError(utils.T("convert.write_error", err))
+			os.Exit(1)
+		}
+		utils.LogInfo(utils.T("convert.appended", appended, len(jiraRecords)-appended))
+		utils.LogInfo(utils.T("convert.finished", len(jiraRecords), time.Since(startTime)))
+		return
+	}
+
+	// JIRA CSV（-output-format の形式）として保存
+	utils.LogInfo(utils.T("convert.writing", cfg.JiraCSV))
+	if err := csvProc.WriteJiraRecords(jiraRecords, *outputFormat); err != nil {
+		utils.LogError(utils.T("convert.write_error", err))
 		os.Exit(1)
 	}
 
+	utils.ReportRepeatedWarnings()
+
 	// 処理時間の表示
 	elapsed := time.Since(startTime)
-	utils.LogInfo("CSV変換が完了しました: %d 件のレコードを処理しました。処理時間: %s", len(jiraRecords), elapsed)
+	utils.LogInfo(utils.T("convert.finished", len(jiraRecords), elapsed))
 }
 
 // ヘルプメッセージを表示する関数
@@ -92,16 +160,38 @@ Pivotal CSV → JIRA CSV 変換ツール
 オプション:
   -input ファイル      入力するPivotal CSV
   -output ファイル     出力するJIRA CSV
+  -output-format 形式  出力形式 csv/json/ndjson (デフォルト: csv)
+  -sync               読み込み・変換・書き込みを並行せず、全行を読み込んでから順に処理する
+  -append             出力先のJIRA CSVが既にある場合は上書きせず追記する (csv のみ, JIRA Issue ID が重複する行は追記しない)
+  -v, -verbose        デバッグログを出力する
+  -q, -quiet          警告とエラーのみ出力する
+  -config=PATH        設定ファイル(YAML)のパス (環境変数とフラグが優先)
+  -env=NAME           .env に加えて .env.NAME を読み込む (APP_ENV でも指定可, .env.NAME が優先)
+  -version            バージョン情報を表示する
   -help               このヘルプを表示する
 
 環境変数:
   PIVOTAL_CSV         Pivotal Trackerから出力したCSVファイルパス (デフォルト: project_history.csv)
   JIRA_CSV            JIRA用に変換したCSVファイルパス (デフォルト: jira_import_ready.csv)
+  JIRA_CSV_HEADERS    JIRA CSVに出力するカラムの順序 (カンマ区切り)
+  MERGE_COLUMNS       重複時に結合するカラム (例: Comment,Label:comma,Owned By:newline)
+  HEADER_ALIASES      Pivotal CSVのヘッダーの別名 (例: Status:Current State, State などはデフォルトで対応)
+  COMMENT_ORDER       コメントを結合する順序 source/oldest-first/newest-first (デフォルト: source)
+  STATUS_MAPPING_FILE イシュータイプ別のステータスマッピングJSON
+  TYPE_MAPPING_FILE   種別ごとのイシュータイプと初期ステータスのマッピングJSON
+  CUSTOM_DATE_FIELD_MAP  日付のカスタムフィールドに保存するPivotalの日時のカラム (例: Started at:customfield_10060)
+  LOG_LEVEL           ログレベル (debug/info/warn/error, デフォルト: info)
+  WARN_REPEAT_LIMIT   同じ警告を出力する最大回数 (超えた分は最後に回数を出力, デフォルト: 5, 0で省略しない)
+  TOOL_LANG           ログメッセージの言語 (ja/en, 未指定時は LANG, デフォルト: ja)
 
 説明:
   このツールはPivotal Trackerからエクスポートしたプロジェクト履歴CSVを
   JIRA用のフォーマットに変換します。
 
   変換されたCSVファイルは、次のステップであるJIRAイシュー作成の入力として使用されます。
+  読み込み・変換・書き込みは並行して行い、すべての行を一度にメモリに保持しません
+  （出力の行順は入力と同じです）。MAX_CONCURRENT が変換の並列数になります。
+  json/ndjson はCSVと同じカラム名をキーにしたJSONで、他のスクリプトから利用するための形式です
+  （issue_import の入力には使用できません）。
 `, os.Args[0])
 }
@@ -0,0 +1,206 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"pivotaltojira/api"
+	"pivotaltojira/config"
+	"pivotaltojira/services"
+	"pivotaltojira/utils"
+)
+
+// slowLatency を超える応答時間の場合は警告します
+const slowLatency = time.Second
+
+// checker はチェック結果を出力し、重要なチェックの失敗数を数えます
+type checker struct {
+	failed int
+}
+
+// pass は成功したチェックを出力します
+func (c *checker) pass(msg string) {
+	utils.LogInfo("[OK]   %s", msg)
+}
+
+// warn は重要ではないチェックの失敗を出力します
+func (c *checker) warn(msg, hint string) {
+	utils.LogWarn("[WARN] %s", msg)
+	if hint != "" {
+		utils.LogWarn("       → %s", hint)
+	}
+}
+
+// fail は重要なチェックの失敗を出力します
+func (c *checker) fail(msg, hint string) {
+	c.failed++
+	utils.LogError("[NG]   %s", msg)
+	if hint != "" {
+		utils.LogError("       → %s", hint)
+	}
+}
+
+func main() {
+	// コマンドラインフラグの定義
+	var verbose, quiet bool
+	flag.BoolVar(&verbose, "verbose", false, "デバッグログを出力する")
+	flag.BoolVar(&verbose, "v", false, "-verbose の短縮形")
+	flag.BoolVar(&quiet, "quiet", false, "警告とエラーのみ出力する")
+	flag.BoolVar(&quiet, "q", false, "-quiet の短縮形")
+	configFile := flag.String("config", "", "設定ファイル(YAML)のパス（環境変数が優先）")
+	envName := flag.String("env", "", "読み込む .env.<名前> の名前（指定しない場合は環境変数 APP_ENV から取得）")
+	showVersion := flag.Bool("version", false, "バージョン情報を表示する")
+	help := flag.Bool("help", false, "ヘルプを表示する")
+
+	// フラグのパース
+	flag.Parse()
+
+	// ヘルプフラグが指定された場合はヘルプを表示
+	if *help {
+		printHelp()
+		return
+	}
+
+	// バージョンフラグが指定された場合はバージョンを表示
+	if *showVersion {
+		fmt.Printf("%s %s\n", filepath.Base(os.Args[0]), utils.VersionString())
+		return
+	}
+
+	// 設定の読み込み
+	config.EnvName = *envName
+	cfg, err := config.LoadConfigFile(*configFile)
+	if err != nil {
+		utils.LogError(utils.T("config.load_failed", err))
+		os.Exit(1)
+	}
+
+	// ログレベルの設定（フラグが環境変数より優先）
+	utils.ConfigureLogLevel(verbose, quiet, cfg.LogLevel)
+	utils.SetLanguage(cfg.Language)
+
+	utils.LogInfo(utils.T("tool.doctor", cfg.JiraURL))
+
+	c := &checker{}
+	jiraClient := api.NewJiraClient(cfg)
+
+	// 1. 認証と応答時間
+	start := time.Now()
+	user, err := jiraClient.WhoAmI()
+	latency := time.Since(start)
+	if err != nil {
+		c.fail(utils.T("doctor.auth_failed", err), api.AuthErrorHint(err))
+		finish(c)
+	}
+	c.pass(utils.T("doctor.auth_ok", user.DisplayName))
+
+	if latency > slowLatency {
+		c.warn(utils.T("doctor.latency_slow", latency.Round(time.Millisecond)), utils.T("doctor.latency_hint"))
+	} else {
+		c.pass(utils.T("doctor.latency_ok", latency.Round(time.Millisecond)))
+	}
+
+	// 2. プロジェクト
+	if err := jiraClient.VerifyProject(); err != nil {
+		c.fail(utils.T("doctor.project_failed", err), utils.T("doctor.project_hint"))
+		finish(c)
+	}
+	c.pass(utils.T("doctor.project_ok", cfg.JiraProjectKey))
+
+	// 3. イシュー作成権限
+	canCreate, err := jiraClient.CanCreateIssues()
+	switch {
+	case err != nil:
+		c.warn(utils.T("auth.create_permission_unknown", err), "")
+	case !canCreate:
+		c.fail(utils.T("auth.create_permission_denied", cfg.JiraProjectKey), utils.T("doctor.permission_hint"))
+	default:
+		c.pass(utils.T("auth.create_permission_ok", cfg.JiraProjectKey))
+	}
+
+	// 4. イシュータイプとストーリーポイントフィールド
+	issueTypes, err := jiraClient.GetCreateMeta()
+	if err != nil {
+		c.fail(utils.T("doctor.createmeta_failed", err), "")
+		finish(c)
+	}
+
+	for _, name := range services.MappedIssueTypes(cfg) {
+		if _, ok := issueTypes[name]; !ok {
+			c.fail(utils.T("doctor.issue_type_missing", name), utils.T("doctor.issue_type_hint"))
+			continue
+		}
+		c.pass(utils.T("doctor.issue_type_ok", name))
+	}
+
+	if err := api.CheckStoryPointField(issueTypes, cfg.StoryPointField); err != nil {
+		c.fail(utils.T("doctor.story_point_invalid", err), utils.T("doctor.story_point_hint"))
+	} else {
+		c.pass(utils.T("doctor.story_point_ok", cfg.StoryPointField))
+	}
+
+	if cfg.PivotalIDField != "" {
+		if err := api.CheckPivotalIDField(issueTypes, cfg.PivotalIDField); err != nil {
+			c.fail(utils.T("doctor.pivotal_id_field_invalid", err), utils.T("doctor.pivotal_id_field_hint"))
+		} else {
+			c.pass(utils.T("doctor.pivotal_id_field_ok", cfg.PivotalIDField))
+		}
+	}
+
+	finish(c)
+}
+
+// finish は結果を出力して終了します（重要なチェックが失敗していれば終了コード1）
+func finish(c *checker) {
+	if c.failed > 0 {
+		utils.LogError(utils.T("doctor.failed", c.failed))
+		os.Exit(1)
+	}
+	utils.LogInfo(utils.T("doctor.all_ok"))
+	os.Exit(0)
+}
+
+// ヘルプメッセージを表示する関数
+func printHelp() {
+	fmt.Printf(`
+移行前の診断ツール
+
+使用方法:
+  %s [オプション]
+
+オプション:
+  -v, -verbose        デバッグログを出力する
+  -q, -quiet          警告とエラーのみ出力する
+  -config=PATH        設定ファイル(YAML)のパス (環境変数とフラグが優先)
+  -env=NAME           .env に加えて .env.NAME を読み込む (APP_ENV でも指定可, .env.NAME が優先)
+  -version            バージョン情報を表示する
+  -help               このヘルプを表示する
+
+環境変数:
+  JIRA_URL            JIRA URL (必須)
+  JIRA_CONTEXT_PATH   コンテキストパス付きのServer/Data Center の場合のパス (例: /jira)
+  JIRA_DEPLOYMENT     JIRAのデプロイ形態 cloud/server (デフォルト: cloud)
+  JIRA_EMAIL          JIRA APIアカウントのメールアドレス (server の場合はユーザー名, 必須)
+  JIRA_API_TOKEN      JIRA APIトークン (server の場合はパスワード, 必須)
+  JIRA_PROJECT_KEY    JIRAプロジェクトキー (必須)
+  JIRA_STORY_POINT_FIELD  JIRAのストーリーポイントフィールドID (デフォルト: customfield_10016)
+  RELEASES_AS_VERSIONS  リリース行をJIRAのバージョンとして作成する (デフォルト: false)
+  PIVOTAL_ID_FIELD    Pivotal IDを保存する文字列のカスタムフィールドID (デフォルト: 保存しない)
+  JIRA_PROXY          JIRAへの接続に使うプロキシURL (未指定時は HTTP_PROXY/HTTPS_PROXY を使用)
+  JIRA_CA_CERT        信頼するCA証明書(PEM)のパス
+  JIRA_INSECURE_SKIP_VERIFY  TLS証明書の検証をスキップする (非推奨, デフォルト: false)
+  LOG_LEVEL           ログレベル (debug/info/warn/error, デフォルト: info)
+  TOOL_LANG           ログメッセージの言語 (ja/en, 未指定時は LANG, デフォルト: ja)
+
+説明:
+  移行を始める前に、次の項目をまとめて確認します。
+    - 認証と応答時間
+    - プロジェクトの存在とイシュー作成権限
+    - インポートで使うイシュータイプがプロジェクトにあるか
+    - ストーリーポイントのフィールドが作成画面にあるか
+  重要な項目が1つでも失敗した場合は終了コード1で終了します。
+`, os.Args[0])
+}
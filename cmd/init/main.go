@@ -0,0 +1,121 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"pivotaltojira/config"
+	"pivotaltojira/utils"
+)
+
+// 出力形式ごとの既定の出力先
+var defaultOutputs = map[string]string{
+	"env":  ".env.example",
+	"yaml": "config.example.yaml",
+}
+
+func main() {
+	// コマンドラインフラグの定義
+	format := flag.String("format", "env", "出力形式 (env/yaml)")
+	output := flag.String("o", "", "出力先のファイル（- の場合は標準出力。指定しない場合は .env.example または config.example.yaml）")
+	force := flag.Bool("force", false, "出力先のファイルが既にある場合も上書きする")
+	showVersion := flag.Bool("version", false, "バージョン情報を表示する")
+	help := flag.Bool("help", false, "ヘルプを表示する")
+
+	// フラグのパース
+	flag.Parse()
+
+	// ヘルプフラグが指定された場合はヘルプを表示
+	if *help {
+		printHelp()
+		return
+	}
+
+	// バージョンフラグが指定された場合はバージョンを表示
+	if *showVersion {
+		fmt.Printf("%s %s\n", filepath.Base(os.Args[0]), utils.VersionString())
+		return
+	}
+
+	write, ok := map[string]func(io.Writer, []config.ConfigKey) error{
+		"env":  config.WriteEnvSample,
+		"yaml": config.WriteYAMLSample,
+	}[*format]
+	if !ok {
+		utils.LogError(utils.T("init.invalid_format", *format))
+		os.Exit(1)
+	}
+
+	path := *output
+	if path == "" {
+		path = defaultOutputs[*format]
+	}
+
+	keys := config.ConfigKeys()
+	if path == "-" {
+		if err := write(os.Stdout, keys); err != nil {
+			utils.LogError("%v", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	// 既存の設定を消さないよう、-force がなければ上書きしない
+	flags := os.O_WRONLY | os.O_CREATE | os.O_EXCL
+	if *force {
+		flags = os.O_WRONLY | os.O_CREATE | os.O_TRUNC
+	}
+	file, err := os.OpenFile(path, flags, 0o644)
+	if errors.Is(err, fs.ErrExist) {
+		utils.LogError(utils.T("init.exists", path))
+		os.Exit(1)
+	}
+	if err != nil {
+		utils.LogError(utils.T("init.write_failed", err))
+		os.Exit(1)
+	}
+
+	err = write(file, keys)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		utils.LogError(utils.T("init.write_failed", err))
+		os.Exit(1)
+	}
+
+	utils.LogInfo(utils.T("init.written", path, len(keys)))
+}
+
+// ヘルプメッセージを表示する関数
+func printHelp() {
+	fmt.Printf(`
+サンプル設定の生成ツール
+
+使用方法:
+  %s [オプション]
+
+オプション:
+  -format=FORMAT      出力形式 env (.env 形式) / yaml (-config で指定する設定ファイルの形式) (デフォルト: env)
+  -o=PATH             出力先のファイル (- の場合は標準出力, デフォルト: .env.example または config.example.yaml)
+  -force              出力先のファイルが既にある場合も上書きする
+  -version            バージョン情報を表示する
+  -help               このヘルプを表示する
+
+説明:
+  このツールは、すべてのツールで使う設定項目（環境変数）を、デフォルト値と説明のコメント付きで書き出します。
+  設定項目の一覧は設定の定義から作成するため、新しい設定項目も自動的に含まれます。
+  STATUS_MAPPING_FILE・TYPE_MAPPING_FILE などのマッピングファイルの形式は README を参照してください。
+  出力先のファイルが既にある場合は、-force を指定しない限り上書きせずに終了します。
+
+例:
+  %s                         # .env.example を作成
+  %s -format=yaml            # config.example.yaml を作成
+  %s -o=- | less             # 標準出力に表示
+`, os.Args[0], os.Args[0], os.Args[0], os.Args[0])
+}
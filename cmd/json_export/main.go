@@ -0,0 +1,138 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"pivotaltojira/config"
+	"pivotaltojira/services"
+	"pivotaltojira/utils"
+)
+
+func main() {
+	// コマンドラインフラグの定義
+	jiraCSV := flag.String("input", "", "JIRA用に変換されたCSVファイルのパス（指定しない場合は環境変数から取得）")
+	jiraJSON := flag.String("output", "", "JSONインポートファイルの出力先（指定しない場合は環境変数から取得）")
+	projectKey := flag.String("project", "", "JSONに記載するJIRAプロジェクトキー（指定しない場合は環境変数から取得）")
+	var verbose, quiet bool
+	flag.BoolVar(&verbose, "verbose", false, "デバッグログを出力する")
+	flag.BoolVar(&verbose, "v", false, "-verbose の短縮形")
+	flag.BoolVar(&quiet, "quiet", false, "警告とエラーのみ出力する")
+	flag.BoolVar(&quiet, "q", false, "-quiet の短縮形")
+	configFile := flag.String("config", "", "設定ファイル(YAML)のパス（環境変数が優先）")
+	envName := flag.String("env", "", "読み込む .env.<名前> の名前（指定しない場合は環境変数 APP_ENV から取得）")
+	showVersion := flag.Bool("version", false, "バージョン情報を表示する")
+	help := flag.Bool("help", false, "ヘルプを表示する")
+
+	// フラグのパース
+	flag.Parse()
+
+	// ヘルプフラグが指定された場合はヘルプを表示
+	if *help {
+		printHelp()
+		return
+	}
+
+	// バージョンフラグが指定された場合はバージョンを表示
+	if *showVersion {
+		fmt.Printf("%s %s\n", filepath.Base(os.Args[0]), utils.VersionString())
+		return
+	}
+
+	// 開始時間の記録
+	startTime := time.Now()
+
+	utils.LogInfo(utils.T("tool.json_export"))
+
+	// 設定の読み込み
+	config.EnvName = *envName
+	cfg, err := config.LoadConfigFile(*configFile)
+	if err != nil {
+		utils.LogError(utils.T("config.load_failed", err))
+		os.Exit(1)
+	}
+
+	// ログレベルの設定（フラグが環境変数より優先）
+	utils.ConfigureLogLevel(verbose, quiet, cfg.LogLevel)
+	utils.SetLanguage(cfg.Language)
+
+	// コマンドラインでパスが指定された場合、設定を上書き
+	if *jiraCSV != "" {
+		cfg.JiraCSV = *jiraCSV
+		utils.LogInfo(utils.T("option.input_file", cfg.JiraCSV))
+	}
+
+	if *jiraJSON != "" {
+		cfg.JiraJSON = *jiraJSON
+		utils.LogInfo(utils.T("option.output_file", cfg.JiraJSON))
+	}
+
+	// プロジェクトキーの上書き（指定された場合のみ）
+	if *projectKey != "" {
+		cfg.JiraProjectKey = *projectKey
+	}
+	utils.LogInfo(utils.T("option.project", cfg.JiraProjectKey))
+
+	// JIRA CSVをJSONインポートファイルに変換
+	utils.LogInfo(utils.T("json_export.reading", cfg.JiraCSV))
+	exporter := services.NewJSONExporter(cfg, services.NewCSVProcessor(cfg))
+	importFile, err := exporter.Export()
+	if err != nil {
+		utils.LogError(utils.T("json_export.error", err))
+		os.Exit(1)
+	}
+
+	// 処理時間の表示
+	project := importFile.Projects[0]
+	elapsed := time.Since(startTime)
+	utils.LogInfo(utils.T("json_export.finished", cfg.JiraJSON, len(project.Issues), len(project.Versions), elapsed))
+}
+
+// ヘルプメッセージを表示する関数
+func printHelp() {
+	fmt.Printf(`
+JIRA CSV → JIRA JSONインポートファイル 変換ツール
+
+使用方法:
+  %s [オプション]
+
+オプション:
+  -input ファイル      入力するJIRA CSV
+  -output ファイル     出力するJSONインポートファイル
+  -project キー       JSONに記載するJIRAプロジェクトキー (JIRA_PROJECT_KEY より優先)
+  -v, -verbose        デバッグログを出力する
+  -q, -quiet          警告とエラーのみ出力する
+  -config=PATH        設定ファイル(YAML)のパス (環境変数とフラグが優先)
+  -env=NAME           .env に加えて .env.NAME を読み込む (APP_ENV でも指定可, .env.NAME が優先)
+  -version            バージョン情報を表示する
+  -help               このヘルプを表示する
+
+環境変数:
+  JIRA_PROJECT_KEY    JIRAプロジェクトキー (必須)
+  JIRA_CSV            JIRA用に変換したCSVファイルパス (デフォルト: jira_import_ready.csv)
+  JIRA_JSON           JSONインポートファイルの出力先 (デフォルト: jira_import.json)
+  ATTACHMENTS_FOLDER  添付ファイルフォルダ (<Pivotal ID>/ 以下のファイルを参照として出力)
+  JSON_ATTACHMENT_BASE_URL  添付ファイルURIの基点 (未指定の場合は file:// の絶対パス)
+  JSON_STORY_POINT_FIELD_NAME  ストーリーポイントのカスタムフィールド名 (デフォルト: Story Points)
+  RESOLUTION_MAPPING  ステータスに対応する解決状況 (例: Done:Done,受け入れ済み:Done)
+  RELEASES_AS_VERSIONS  リリース行をJIRAのバージョンとして出力する (デフォルト: false)
+  PIVOTAL_ID_LABEL_PREFIX  Pivotal ID ラベルの接頭辞 (デフォルト: pivotal-, 空にすると付与しない)
+  GLOBAL_LABELS            すべてのイシューに付与するラベル (カンマ区切り, 例: migrated-2024)
+  SUMMARY_PREFIX_FORMAT  サマリーのテンプレート (デフォルト: [{id}] {title})
+  LOG_LEVEL           ログレベル (debug/info/warn/error, デフォルト: info)
+  TOOL_LANG           ログメッセージの言語 (ja/en, 未指定時は LANG, デフォルト: ja)
+
+説明:
+  このツールはJIRA APIを使わず、変換済みのCSVをJIRAの外部システムインポート
+  (JSON) 用のファイルに変換します。大量のイシューを移行する場合に利用してください。
+
+  イシューのサマリー・ラベル・イシュータイプ・ステータスは issue_import と同じ規則で決まります。
+  Pivotal ID は externalId として出力されます。
+
+  添付ファイルのURIはJIRAサーバーから参照できる必要があります。
+  必要に応じて JSON_ATTACHMENT_BASE_URL にファイルを配置したURLを指定してください。
+`, os.Args[0])
+}
@@ -0,0 +1,157 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"pivotaltojira/api"
+	"pivotaltojira/config"
+	"pivotaltojira/services"
+	"pivotaltojira/utils"
+)
+
+func main() {
+	// コマンドラインフラグの定義
+	pivotalCSV := flag.String("input", "", "Pivotal Tracker CSVファイルのパス（指定しない場合は環境変数から取得）")
+	count := flag.Int("n", 3, "表示するレコード数")
+	projectKey := flag.String("project", "", "ペイロードに含めるJIRAプロジェクトキー（指定しない場合は環境変数から取得）")
+	var verbose, quiet bool
+	flag.BoolVar(&verbose, "verbose", false, "デバッグログを出力する")
+	flag.BoolVar(&verbose, "v", false, "-verbose の短縮形")
+	flag.BoolVar(&quiet, "quiet", false, "警告とエラーのみ出力する")
+	flag.BoolVar(&quiet, "q", false, "-quiet の短縮形")
+	configFile := flag.String("config", "", "設定ファイル(YAML)のパス（環境変数が優先）")
+	envName := flag.String("env", "", "読み込む .env.<名前> の名前（指定しない場合は環境変数 APP_ENV から取得）")
+	showVersion := flag.Bool("version", false, "バージョン情報を表示する")
+	help := flag.Bool("help", false, "ヘルプを表示する")
+
+	// フラグのパース
+	flag.Parse()
+
+	// ヘルプフラグが指定された場合はヘルプを表示
+	if *help {
+		printHelp()
+		return
+	}
+
+	// バージョンフラグが指定された場合はバージョンを表示
+	if *showVersion {
+		fmt.Printf("%s %s\n", filepath.Base(os.Args[0]), utils.VersionString())
+		return
+	}
+
+	// 設定の読み込み
+	config.EnvName = *envName
+	cfg, err := config.LoadConfigFile(*configFile)
+	if err != nil {
+		utils.LogError(utils.T("config.load_failed", err))
+		os.Exit(1)
+	}
+
+	// ログレベルの設定（フラグが環境変数より優先）
+	utils.ConfigureLogLevel(verbose, quiet, cfg.LogLevel)
+	utils.SetLanguage(cfg.Language)
+
+	utils.LogInfo(utils.T("tool.preview"))
+
+	// コマンドラインでパスが指定された場合、設定を上書き
+	if *pivotalCSV != "" {
+		cfg.PivotalCSV = *pivotalCSV
+		utils.LogInfo(utils.T("option.input_file", cfg.PivotalCSV))
+	}
+
+	// プロジェクトキーの上書き（指定された場合のみ）
+	if *projectKey != "" {
+		cfg.JiraProjectKey = *projectKey
+	}
+
+	// Pivotal CSVを読み込んでJIRA形式に変換（ファイルには書き出さない）
+	csvProc := services.NewCSVProcessor(cfg)
+	records, err := csvProc.ReadPivotalCSV()
+	if err != nil {
+		utils.LogError(utils.T("convert.read_error", err))
+		os.Exit(1)
+	}
+	jiraRecords, err := csvProc.ProcessPivotalToJiraCSV(records)
+	if err != nil {
+		utils.LogError(utils.T("convert.error", err))
+		os.Exit(1)
+	}
+
+	// JIRAのAPIは呼び出さず、作成時のペイロードだけを組み立てる
+	migrationService := services.NewMigrationService(cfg, api.NewJiraClient(cfg), csvProc)
+
+	shown := 0
+	for i, record := range jiraRecords {
+		if shown >= *count {
+			break
+		}
+		// リリースの行はイシューとして作成しない
+		if cfg.ReleasesAsVersions && services.IsRelease(record) {
+			continue
+		}
+
+		payload, err := migrationService.PreviewCreatePayload(record)
+		if err != nil {
+			utils.LogError(utils.T("preview.row_error", i+1, err))
+			continue
+		}
+		data, err := json.MarshalIndent(payload, "", "  ")
+		if err != nil {
+			utils.LogError(utils.T("preview.row_error", i+1, err))
+			continue
+		}
+
+		fmt.Printf("# %s\n%s\n\n", utils.T("preview.row", i+1, record["JIRA Issue ID"], record["JIRA Status"]), data)
+		shown++
+	}
+
+	utils.LogInfo(utils.T("preview.done", shown, len(jiraRecords)))
+}
+
+// ヘルプメッセージを表示する関数
+func printHelp() {
+	fmt.Printf(`
+イシュー作成ペイロードのプレビューツール
+
+使用方法:
+  %s [オプション]
+
+オプション:
+  -input ファイル      入力するPivotal CSV
+  -n 件数             表示するレコード数 (デフォルト: 3)
+  -project キー       ペイロードに含めるJIRAプロジェクトキー (JIRA_PROJECT_KEY より優先)
+  -v, -verbose        デバッグログを出力する
+  -q, -quiet          警告とエラーのみ出力する
+  -config=PATH        設定ファイル(YAML)のパス (環境変数とフラグが優先)
+  -env=NAME           .env に加えて .env.NAME を読み込む (APP_ENV でも指定可, .env.NAME が優先)
+  -version            バージョン情報を表示する
+  -help               このヘルプを表示する
+
+環境変数:
+  PIVOTAL_CSV         Pivotal Trackerから出力したCSVファイルパス (デフォルト: project_history.csv)
+  JIRA_PROJECT_KEY    JIRAプロジェクトキー
+  JIRA_DEPLOYMENT     JIRAのデプロイ形態 cloud/server (デフォルト: cloud)
+  JIRA_STORY_POINT_FIELD  JIRAのストーリーポイントフィールドID (デフォルト: customfield_10016)
+  STATUS_MAPPING_FILE イシュータイプ別のステータスマッピングJSON
+  TYPE_MAPPING_FILE   種別ごとのイシュータイプと初期ステータスのマッピングJSON
+  PIVOTAL_ID_LABEL_PREFIX  Pivotal ID ラベルの接頭辞 (デフォルト: pivotal-, 空にすると付与しない)
+  GLOBAL_LABELS            すべてのイシューに付与するラベル (カンマ区切り, 例: migrated-2024)
+  PIVOTAL_ID_FIELD    Pivotal IDを保存する文字列のカスタムフィールドID (デフォルト: 保存しない)
+  SUMMARY_PREFIX_FORMAT  サマリーのテンプレート (デフォルト: [{id}] {title})
+  MIGRATION_FOOTER    説明文の末尾に追記するフッター ({date}, {version}, {id} が使用可能, デフォルト: 追記しない)
+  RELEASES_AS_VERSIONS  リリース行をJIRAのバージョンとして作成する (デフォルト: false)
+  LOG_LEVEL           ログレベル (debug/info/warn/error, デフォルト: info)
+  TOOL_LANG           ログメッセージの言語 (ja/en, 未指定時は LANG, デフォルト: ja)
+
+説明:
+  このツールはJIRAのAPIを呼び出さずに、Pivotal CSVの先頭から指定した件数について
+  issue_import がイシュー作成時に送信するJSONペイロードを表示します。
+  フィールド・ステータス・イシュータイプのマッピングを事前に確認するのに使用してください。
+
+  ステータスは作成後の遷移で設定されるため、ペイロードには含まれません（見出しに表示します）。
+`, os.Args[0])
+}
@@ -1,29 +1,325 @@
 package config
 
 import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"math"
+	"net/url"
 	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
 	"strconv"
 	"strings"
+	"time"
 
-	"github.com/joho/godotenv"
+	"pivotaltojira/utils"
 )
 
 // Config はアプリケーション全体の設定を保持します
+// env タグは読み込む環境変数名、desc タグは説明、section タグは設定の区分で、サンプル設定の生成 (ConfigKeys) に使います
 type Config struct {
 	// JIRA API設定
-	JiraURL         string
-	JiraEmail       string
-	JiraAPIToken    string
-	JiraProjectKey  string
-	StoryPointField string
+	JiraURL         string `env:"JIRA_URL" desc:"JIRAのURL（必須。例: https://your-domain.atlassian.net）" section:"JIRA API設定"`
+	JiraContextPath string `env:"JIRA_CONTEXT_PATH" desc:"コンテキストパス付きでデプロイしたServer/Data Center の場合のパス（例: /jira）"`
+	JiraEmail       string `env:"JIRA_EMAIL" desc:"JIRA APIアカウントのメールアドレス（server の場合はユーザー名。必須）"`
+	JiraAPIToken    string `env:"JIRA_API_TOKEN" desc:"JIRA APIトークン（server の場合はパスワード。必須）"`
+	JiraDeployment  string `env:"JIRA_DEPLOYMENT" desc:"JIRAのデプロイ形態（cloud/server）"`
+	JiraProjectKey  string `env:"JIRA_PROJECT_KEY" desc:"JIRAプロジェクトキー（必須）"`
+	StoryPointField string `env:"JIRA_STORY_POINT_FIELD" desc:"ストーリーポイントのカスタムフィールドID"`
+
+	// 接続設定
+	JiraProxy              string `env:"JIRA_PROXY" desc:"JIRAへの接続に使うプロキシURL（未指定の場合は HTTP_PROXY/HTTPS_PROXY/NO_PROXY を使用）" section:"接続設定"`
+	JiraCACert             string `env:"JIRA_CA_CERT" desc:"信頼するCA証明書(PEM)のパス"`
+	JiraInsecureSkipVerify bool   `env:"JIRA_INSECURE_SKIP_VERIFY" desc:"TLS証明書の検証をスキップする（非推奨: JIRA_CA_CERT を優先してください）"`
+
+	// 本番環境の確認
+	ConfirmProduction    bool     `env:"CONFIRM_PRODUCTION" desc:"本番環境と判定したJIRAに書き込む前に確認する（-yes で省略可）" section:"本番環境の確認"`
+	ProductionURLPattern string   `env:"PRODUCTION_URL_PATTERN" desc:"本番環境と判定するJIRA_URLの正規表現（未指定の場合は NON_PRODUCTION_URLS 以外をすべて本番とみなす）"`
+	NonProductionURLs    []string `env:"NON_PRODUCTION_URLS" desc:"確認なしで書き込んでよいJIRA_URLまたはホスト名（カンマ区切り）"`
 
 	// ファイルパス
-	PivotalCSV        string
-	JiraCSV           string
-	AttachmentsFolder string
+	PivotalCSV              string            `env:"PIVOTAL_CSV" desc:"PivotalTrackerからエクスポートしたCSVファイル" section:"ファイルパス設定"`
+	JiraCSV                 string            `env:"JIRA_CSV" desc:"変換後のJIRA CSVファイル"`
+	AttachmentsFolder       string            `env:"ATTACHMENTS_FOLDER" desc:"添付ファイルのフォルダ（カンマ区切りやグロブ（例: exports/*/attachments）で複数指定可）"`
+	AttachmentBatchMB       int               `env:"ATTACHMENT_BATCH_MB" desc:"1回のリクエストでまとめてアップロードする添付ファイルの合計サイズ（MB。0にすると1ファイルずつ）"`
+	SkipExistingAttachments bool              `env:"SKIP_EXISTING_ATTACHMENTS" desc:"イシューに同じ名前の添付ファイルがある場合はアップロードしない"`
+	AttachmentMatchSize     bool              `env:"ATTACHMENT_MATCH_SIZE" desc:"SKIP_EXISTING_ATTACHMENTS でファイルサイズも一致する場合のみ添付済みとみなす"`
+	SummaryJSON             string            `env:"SUMMARY_JSON" desc:"移行結果の集計を書き出すJSONファイル（空にすると出力しない）"`
+	MappingJSON             string            `env:"MAPPING_JSON" desc:"Pivotal ID → JIRAキーのマッピングを書き出すJSONファイル"`
+	ImportJournal           string            `env:"IMPORT_JOURNAL" desc:"作成したイシューを作成直後に追記するジャーナル（空にすると出力しない）"`
+	JiraCSVHeaders          []string          `env:"JIRA_CSV_HEADERS" desc:"JIRA CSVに出力するカラムの順序（カンマ区切り。未指定のカラムは名前順で後ろに追加）"`
+	MergeColumns            map[string]string `env:"MERGE_COLUMNS" desc:"Pivotal CSVで重複する場合に結合するカラム（ヘッダー:結合方法 のカンマ区切り。comment/comma/newline/space）"`
+	HeaderAliases           map[string]string `env:"HEADER_ALIASES" desc:"Pivotal CSVのヘッダーの別名（別名:ヘッダー のカンマ区切り。例: Status:Current State）"`
+	CommentOrder            string            `env:"COMMENT_ORDER" desc:"複数のコメント列を結合する順序（source/oldest-first/newest-first）"`
+	JiraJSON                string            `env:"JIRA_JSON" desc:"JIRAのJSONインポーター用ファイルの出力先（json_export）"`
+
+	// JSONエクスポート設定
+	JSONAttachmentBaseURL   string `env:"JSON_ATTACHMENT_BASE_URL" desc:"JSONに記載する添付ファイルURIの基点（未指定の場合は file:// の絶対パス）" section:"JSONエクスポート設定"`
+	JSONStoryPointFieldName string `env:"JSON_STORY_POINT_FIELD_NAME" desc:"JSONに記載するストーリーポイントのカスタムフィールド名"`
+
+	// ログ設定
+	LogLevel   string `env:"LOG_LEVEL" desc:"ログレベル（debug/info/warn/error。未指定の場合は info）" section:"ログ設定"`
+	Language   string `env:"TOOL_LANG" desc:"ログメッセージの言語（ja/en。未指定の場合は LANG、どちらもなければ ja）"`
+	OrderedLog bool   `env:"ORDERED_LOG" desc:"インポート中の行ごとのログを元の行順に並べて出力する"`
+	// WarnRepeatLimit は同じ内容の警告を出力する最大回数です（超えた分は最後に回数をまとめて出力。0の場合は省略しない）
+	WarnRepeatLimit int `env:"WARN_REPEAT_LIMIT" desc:"同じ内容の警告を出力する最大回数（超えた分は最後に回数をまとめて出力。0にすると省略しない）"`
 
 	// 並列処理設定
-	MaxConcurrent int
+	MaxConcurrent       int     `env:"MAX_CONCURRENT" desc:"同時に処理するリクエストの最大数" section:"並列処理設定"`
+	MaxIdleConnsPerHost int     `env:"MAX_IDLE_CONNS_PER_HOST" desc:"ホストごとに保持するアイドル接続数（0の場合は MAX_CONCURRENT）"`
+	MaxConnsPerHost     int     `env:"MAX_CONNS_PER_HOST" desc:"ホストごとの最大接続数（0の場合は MAX_CONCURRENT の2倍）"`
+	IdleConnTimeoutSec  int     `env:"IDLE_CONN_TIMEOUT" desc:"アイドル接続を保持する秒数"`
+	RequestTimeoutSec   int     `env:"JIRA_REQUEST_TIMEOUT" desc:"JIRAへの1回のリクエストのタイムアウト秒数（0にすると無制限）"`
+	UploadTimeoutSec    int     `env:"JIRA_UPLOAD_TIMEOUT" desc:"添付ファイルのアップロード1回のタイムアウト秒数（0にすると無制限）"`
+	JiraRateLimit       float64 `env:"JIRA_RATE_LIMIT" desc:"1秒あたりの最大リクエスト数（0の場合は制限なし）"`
+	AutoClampConcurrent bool    `env:"AUTO_CLAMP_CONCURRENCY" desc:"並列数をレート制限に見合う値に自動で抑える"`
+	AutoTuneConcurrency bool    `env:"AUTO_TUNE_CONCURRENCY" desc:"429（レート制限）の発生状況に応じて同時リクエスト数を MAX_CONCURRENT 以下で自動調整する"`
+	// MigrationDeadline は移行全体の期限です（JIRAクライアントの作成時から数える。0の場合は無制限）
+	MigrationDeadline time.Duration `env:"MIGRATION_DEADLINE" desc:"移行全体の期限（例: 2h, 30m。超えると処理済みの結果を書き出して中断。未指定の場合は無制限）"`
+	RetryBudget       int           `env:"RETRY_BUDGET" desc:"全リクエストで共有する再試行回数の上限（使い切ると中断。0の場合は無制限）"`
+
+	// ステータス遷移設定
+	TypeStatusMapping      map[string]map[string]string `env:"STATUS_MAPPING_FILE" desc:"イシュータイプ別のステータスマッピング（JSONファイル。例: {\"Bug\": {\"accepted\": \"Closed\"}}）" section:"ステータス遷移設定"`
+	IssueTypeMapping       map[string]IssueTypeRule     `env:"TYPE_MAPPING_FILE" desc:"ストーリー種別ごとのイシュータイプと初期ステータス（JSONファイル。例: {\"chore\": {\"type\": \"Task\", \"status\": \"Done\"}}）"`
+	ResolutionMapping      map[string]string            `env:"RESOLUTION_MAPPING" desc:"JIRAステータスごとの解決状況（JIRAステータス:解決状況 のカンマ区切り）"`
+	TransitionPaths        map[string][]string          `env:"TRANSITION_PATHS" desc:"目的ステータスまでの遷移経路（目的ステータス:経由1>経由2>目的ステータス のカンマ区切り）"`
+	DisableTransitionCache bool                         `env:"DISABLE_TRANSITION_CACHE" desc:"イシュータイプ・ステータスごとの遷移の一覧をキャッシュしない"`
+	MaxTransitionHops      int                          `env:"MAX_TRANSITION_HOPS" desc:"目的ステータスへ到達するまでの最大遷移回数"`
+	StrictStatus           bool                         `env:"STRICT_STATUS" desc:"ステータス遷移に失敗した行をエラーとして扱う"`
+	SkipStatuses           []string                     `env:"SKIP_STATUSES" desc:"遷移不要として扱うステータス（カンマ区切り）"`
+
+	// インポート設定
+	PreserveRank          bool     `env:"PRESERVE_RANK" desc:"Pivotalの並び順をJIRAのランクに反映する" section:"インポート設定"`
+	UpdateExisting        bool     `env:"UPDATE_EXISTING" desc:"JIRA Issue Key が設定済みの行は作成せず既存イシューを更新する"`
+	DedupOnCreate         bool     `env:"DEDUP_ON_CREATE" desc:"作成前にPivotal IDのラベルで既存イシューを検索し、見つかった場合は作成しない"`
+	EpicsFirst            bool     `env:"EPICS_FIRST" desc:"エピックの行をすべて作成してから他の行を作成する"`
+	SkipComments          bool     `env:"SKIP_COMMENTS" desc:"コメントを追加しない（段階的な移行で後から追加する場合）"`
+	SkipStatusUpdate      bool     `env:"SKIP_STATUS_UPDATE" desc:"ステータスを遷移しない"`
+	SkipStoryPoints       bool     `env:"SKIP_STORY_POINTS" desc:"ストーリーポイントを設定しない"`
+	PivotalIDLabelPrefix  string   `env:"PIVOTAL_ID_LABEL_PREFIX" desc:"作成するイシューに付与する Pivotal ID ラベルの接頭辞（空にすると付与しない）"`
+	GlobalLabels          []string `env:"GLOBAL_LABELS" desc:"すべてのイシューに付与するラベル（カンマ区切り）"`
+	PivotalIDField        string   `env:"PIVOTAL_ID_FIELD" desc:"Pivotal IDを保存する文字列のカスタムフィールドID（空の場合は保存しない）"`
+	OriginalCreatedField  string   `env:"ORIGINAL_CREATED_FIELD" desc:"Pivotalでの作成日時を保存する日付のカスタムフィールドID（空の場合は説明文に追記）"`
+	OriginalResolvedField string   `env:"ORIGINAL_RESOLVED_FIELD" desc:"Pivotalでの受け入れ日時を保存する日付のカスタムフィールドID（空の場合は説明文に追記）"`
+	// CustomDateFieldMap はPivotal CSVの日時のカラム → 保存する日付のカスタムフィールドIDです（例: Started at → customfield_10060）
+	CustomDateFieldMap    map[string]string `env:"CUSTOM_DATE_FIELD_MAP" desc:"その他のPivotalの日時のカラムを保存する日付のカスタムフィールドID（カラム:フィールドID のカンマ区切り）"`
+	ImportStartRow        int               // インポートを開始する行番号（1始まり、0の場合は先頭から。-start フラグで指定）
+	ImportLimit           int               // インポートする最大行数（0の場合はすべて。-limit フラグで指定）
+	MaxErrors             int               `env:"MAX_ERRORS" desc:"失敗した行がこの数を超えたらインポートを中断する（0の場合は無制限）"`
+	ContinueOnAuthWarning bool              `env:"CONTINUE_ON_AUTH_WARNING" desc:"インポート中に認証エラー (401) が返されても認証を確認し直さずに続行する"`
+	ReleasesAsVersions    bool              `env:"RELEASES_AS_VERSIONS" desc:"Pivotalのリリース行をイシューではなくJIRAのバージョンとして作成する"`
+	SubtaskIssueType      string            `env:"SUBTASK_ISSUE_TYPE" desc:"Pivotalのタスクから作成するサブタスクのイシュータイプ"`
+	DefaultIssueType      string            `env:"DEFAULT_ISSUE_TYPE" desc:"マッピングにない種別のストーリーに使うイシュータイプ"`
+	FilterStates          []string          `env:"FILTER_STATES" desc:"インポートするPivotalのステータス（カンマ区切り。空の場合はすべて）"`
+	FilterLabels          []string          `env:"FILTER_LABELS" desc:"いずれかを含む行のみインポートするラベル（カンマ区切り。空の場合はすべて）"`
+	SummaryPrefixFormat   string            `env:"SUMMARY_PREFIX_FORMAT" desc:"サマリーのテンプレート（{id}: Pivotal ID, {title}: タイトル）"`
+	MigrationFooter       string            `env:"MIGRATION_FOOTER" desc:"作成・更新時に説明文の末尾に追記するフッター（{date}, {version}, {id} が使用可能。空の場合は追記しない）"`
+	AssigneeNotePrefix    string            `env:"ASSIGNEE_NOTE_PREFIX" desc:"マッピングにない担当者を説明文に追記する際の見出し"`
+	ReporterNotePrefix    string            `env:"REPORTER_NOTE_PREFIX" desc:"マッピングにない報告者を説明文に追記する際の見出し"`
+	ReviewerField         string            `env:"JIRA_REVIEWER_FIELD" desc:"レビュアーを保存するユーザーピッカー（複数）のカスタムフィールドID（空の場合は説明文に追記）"`
+	ReviewerNotePrefix    string            `env:"REVIEWER_NOTE_PREFIX" desc:"マッピングにないレビュアーを説明文に追記する際の見出し"`
+	CommentVisibility     string            `env:"COMMENT_VISIBILITY" desc:"移行したコメントを閲覧できる範囲（role:ロール名 または group:グループ名。空の場合は制限しない）"`
+	CommentInternalMarker string            `env:"COMMENT_INTERNAL_MARKER" desc:"この文字列で始まるコメントのみ COMMENT_VISIBILITY で制限する（空の場合はすべてのコメントを制限）"`
+}
+
+// IsSkipStatus は遷移不要として扱うステータスかどうかを大文字小文字を区別せずに判定します
+func (c *Config) IsSkipStatus(status string) bool {
+	for _, skip := range c.SkipStatuses {
+		if strings.EqualFold(skip, status) {
+			return true
+		}
+	}
+	return false
+}
+
+// NeedsTransition は作成・更新後にステータスの遷移が必要かどうかを返します
+// 空のステータスと SKIP_STATUSES に含まれる初期ステータス（大文字小文字を区別しない）は遷移しません
+// インポート・既存イシューの更新・差分確認はすべてこの判定を使います
+func (c *Config) NeedsTransition(status string) bool {
+	return strings.TrimSpace(status) != "" && !c.IsSkipStatus(strings.TrimSpace(status))
+}
+
+// ResolutionFor はJIRAステータスに対応する解決状況名を返します（未設定の場合は空文字）
+func (c *Config) ResolutionFor(status string) string {
+	if resolution, ok := c.ResolutionMapping[status]; ok {
+		return resolution
+	}
+
+	// 大文字小文字の違いは無視する
+	for s, resolution := range c.ResolutionMapping {
+		if strings.EqualFold(s, status) {
+			return resolution
+		}
+	}
+
+	return ""
+}
+
+// CommentVisibilityTypes は COMMENT_VISIBILITY で指定できる制限の種類です
+var CommentVisibilityTypes = []string{"role", "group"}
+
+// CommentRestriction は COMMENT_VISIBILITY を制限の種類 (role/group) と名前に分けて返します
+// 未指定の場合は空文字を返します
+func (c *Config) CommentRestriction() (string, string, error) {
+	if c.CommentVisibility == "" {
+		return "", "", nil
+	}
+
+	kind, name, ok := strings.Cut(c.CommentVisibility, ":")
+	kind = strings.ToLower(strings.TrimSpace(kind))
+	name = strings.TrimSpace(name)
+	if !ok || name == "" || !slices.Contains(CommentVisibilityTypes, kind) {
+		return "", "", fmt.Errorf("COMMENT_VISIBILITY は role:ロール名 または group:グループ名 の形式で指定してください: %s", c.CommentVisibility)
+	}
+	return kind, name, nil
+}
+
+// anyIssueType はイシュータイプ別ステータスマッピングで全タイプに適用するキーです
+const anyIssueType = "*"
+
+// MapStatus はPivotalステータスをJIRAステータスに変換します
+// イシュータイプ別の設定、"*" の設定、全体の StatusMapping の順に参照します
+func (c *Config) MapStatus(issueType, pivotalStatus string) string {
+	pivotalStatus = strings.ToLower(pivotalStatus)
+
+	for _, key := range []string{issueType, anyIssueType} {
+		for t, mapping := range c.TypeStatusMapping {
+			if !strings.EqualFold(t, key) {
+				continue
+			}
+			if status, ok := mapping[pivotalStatus]; ok {
+				return status
+			}
+		}
+	}
+
+	return StatusMapping[pivotalStatus]
+}
+
+// JSONファイルからイシュータイプ別のステータスマッピングを読み込む
+// 形式: {"Bug": {"accepted": "Closed"}, "*": {"started": "進行中"}}
+func loadTypeStatusMapping(path string) (map[string]map[string]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("ステータスマッピングファイル読み込みエラー: %w", err)
+	}
+
+	var raw map[string]map[string]string
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("ステータスマッピングファイル解析エラー (%s): %w", path, err)
+	}
+
+	return normalizeTypeStatusMapping(raw), nil
+}
+
+// Pivotalステータスは小文字で比較する
+func normalizeTypeStatusMapping(raw map[string]map[string]string) map[string]map[string]string {
+	mapping := make(map[string]map[string]string, len(raw))
+	for issueType, statuses := range raw {
+		mapping[issueType] = make(map[string]string, len(statuses))
+		for pivotalStatus, jiraStatus := range statuses {
+			mapping[issueType][strings.ToLower(pivotalStatus)] = jiraStatus
+		}
+	}
+
+	return mapping
+}
+
+// IssueTypeRule は TYPE_MAPPING_FILE の1件分で、Pivotalのストーリー種別から変換するイシュータイプです
+// Status を指定すると、Pivotalのステータスにかかわらずそのステータスでイシューを作成します（例: chore → 完了済みのTask）
+type IssueTypeRule struct {
+	Type   string `json:"type"`
+	Status string `json:"status,omitempty"`
+}
+
+// UnmarshalJSON はイシュータイプのみの文字列 ("Task") と、ステータスを含むオブジェクト ({"type": "Task", "status": "Done"}) の両方を受け付けます
+func (r *IssueTypeRule) UnmarshalJSON(data []byte) error {
+	var issueType string
+	if err := json.Unmarshal(data, &issueType); err == nil {
+		*r = IssueTypeRule{Type: issueType}
+		return nil
+	}
+
+	type rule IssueTypeRule
+	var v rule
+	if err := json.Unmarshal(data, &v); err != nil {
+		return fmt.Errorf("イシュータイプの文字列か {\"type\": ..., \"status\": ...} を指定してください: %w", err)
+	}
+	*r = IssueTypeRule(v)
+	return nil
+}
+
+// LookupIssueType は TYPE_MAPPING_FILE でPivotalのストーリー種別（大文字小文字を区別しない）に指定したマッピングを返します
+func (c *Config) LookupIssueType(pivotalType string) (IssueTypeRule, bool) {
+	rule, ok := c.IssueTypeMapping[strings.ToLower(strings.TrimSpace(pivotalType))]
+	return rule, ok
+}
+
+// JSONファイルからストーリー種別ごとのイシュータイプのマッピングを読み込む
+// 形式: {"bug": "Bug", "chore": {"type": "Task", "status": "Done"}}
+func loadIssueTypeMapping(path string) (map[string]IssueTypeRule, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("イシュータイプマッピングファイル読み込みエラー: %w", err)
+	}
+
+	var raw map[string]IssueTypeRule
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("イシュータイプマッピングファイル解析エラー (%s): %w", path, err)
+	}
+
+	// Pivotalの種別は小文字で比較する
+	mapping := make(map[string]IssueTypeRule, len(raw))
+	for pivotalType, rule := range raw {
+		if strings.TrimSpace(rule.Type) == "" {
+			return nil, fmt.Errorf("イシュータイプマッピングファイル解析エラー (%s): %s: type を指定してください", path, pivotalType)
+		}
+		mapping[strings.ToLower(strings.TrimSpace(pivotalType))] = rule
+	}
+
+	return mapping, nil
+}
+
+// JIRAのデプロイ形態
+const (
+	// DeploymentCloud はJIRA Cloud（メールアドレス + APIトークン認証、アカウントIDでユーザー指定）です
+	DeploymentCloud = "cloud"
+	// DeploymentServer はJIRA Server/Data Center（ユーザー名 + パスワード認証、ユーザー名でユーザー指定）です
+	DeploymentServer = "server"
+)
+
+// AttachmentFolders は ATTACHMENTS_FOLDER のカンマ区切りのフォルダを、グロブパターン（*, ?, [...]）を展開して返します
+// 存在しないフォルダとディレクトリ以外は除き、重複は1つにまとめます
+func (c *Config) AttachmentFolders() ([]string, error) {
+	var folders []string
+	seen := make(map[string]bool)
+	for _, item := range SplitList(c.AttachmentsFolder) {
+		matches := []string{item}
+		if strings.ContainsAny(item, "*?[") {
+			var err error
+			if matches, err = filepath.Glob(item); err != nil {
+				return nil, fmt.Errorf("ATTACHMENTS_FOLDER のパターンが不正です: %s: %w", item, err)
+			}
+		}
+
+		for _, match := range matches {
+			match = filepath.Clean(match)
+			if info, err := os.Stat(match); err != nil || !info.IsDir() || seen[match] {
+				continue
+			}
+			seen[match] = true
+			folders = append(folders, match)
+		}
+	}
+	return folders, nil
+}
+
+// IsServerDeployment はJIRA Server/Data Center に接続する設定かどうかを返します
+func (c *Config) IsServerDeployment() bool {
+	return c.JiraDeployment == DeploymentServer
 }
 
 // StatusMapping はPivotalステータスからJIRAステータスへのマッピングです
@@ -37,26 +333,399 @@ var StatusMapping = map[string]string{
 	"rejected":    "Backlog",
 }
 
+// DefaultResolutionMapping は遷移時に設定する解決状況のデフォルトマッピングです
+var DefaultResolutionMapping = map[string]string{
+	"Done":   "Done",
+	"受け入れ済み": "Done",
+}
+
 // LoadConfig は環境変数から設定を読み込みます
 func LoadConfig() (*Config, error) {
-	// .envファイルを読み込む
-	_ = godotenv.Load()
+	// .envファイル（-env または APP_ENV を指定した場合は .env.<名前> も）を読み込む
+	if _, err := loadDotEnv(); err != nil {
+		return nil, err
+	}
+
+	config := configFromEnv()
 
-	config := &Config{
-		JiraURL:          strings.TrimRight(os.Getenv("JIRA_URL"), "/"),
-		JiraEmail:        os.Getenv("JIRA_EMAIL"),
-		JiraAPIToken:     os.Getenv("JIRA_API_TOKEN"),
-		JiraProjectKey:   os.Getenv("JIRA_PROJECT_KEY"),
-		StoryPointField:  getEnvWithDefault("JIRA_STORY_POINT_FIELD", "customfield_10016"),
-		PivotalCSV:       getEnvWithDefault("PIVOTAL_CSV", "pivotal.csv"),
-		JiraCSV:          getEnvWithDefault("JIRA_CSV", "jira_import_ready.csv"),
-		AttachmentsFolder: getEnvWithDefault("ATTACHMENTS_FOLDER", "attachments"),
-		MaxConcurrent:    getEnvAsIntWithDefault("MAX_CONCURRENT", 10),
+	// APIトークンとBasic認証ヘッダーの値がログに出力されないよう登録
+	utils.RegisterSecret(config.JiraAPIToken)
+	if config.JiraAPIToken != "" {
+		utils.RegisterSecret(base64.StdEncoding.EncodeToString([]byte(config.JiraEmail + ":" + config.JiraAPIToken)))
+	}
+
+	// イシュータイプ別のステータスマッピング
+	if path := os.Getenv("STATUS_MAPPING_FILE"); path != "" {
+		mapping, err := loadTypeStatusMapping(path)
+		if err != nil {
+			return nil, err
+		}
+		config.TypeStatusMapping = mapping
+	}
+
+	// ストーリー種別ごとのイシュータイプ（と初期ステータス）のマッピング
+	if path := os.Getenv("TYPE_MAPPING_FILE"); path != "" {
+		mapping, err := loadIssueTypeMapping(path)
+		if err != nil {
+			return nil, err
+		}
+		config.IssueTypeMapping = mapping
+	}
+
+	jiraURL, err := normalizeJiraURL(config.JiraURL)
+	if err != nil {
+		return nil, err
+	}
+	config.JiraURL = jiraURL
+
+	if value := strings.TrimSpace(os.Getenv("MIGRATION_DEADLINE")); value != "" {
+		deadline, err := time.ParseDuration(value)
+		if err != nil || deadline < 0 {
+			return nil, fmt.Errorf("MIGRATION_DEADLINE は 2h30m のような期間で指定してください: %s", value)
+		}
+		config.MigrationDeadline = deadline
+	}
+
+	if config.JiraDeployment != DeploymentCloud && config.JiraDeployment != DeploymentServer {
+		return nil, fmt.Errorf("JIRA_DEPLOYMENT は %s または %s を指定してください: %s",
+			DeploymentCloud, DeploymentServer, config.JiraDeployment)
+	}
+
+	if config.ProductionURLPattern != "" {
+		if _, err := regexp.Compile(config.ProductionURLPattern); err != nil {
+			return nil, fmt.Errorf("PRODUCTION_URL_PATTERN の正規表現が不正です: %w", err)
+		}
+	}
+
+	// 既存イシューの検索にはPivotal IDのラベルが必要
+	if config.DedupOnCreate && config.PivotalIDLabelPrefix == "" {
+		return nil, fmt.Errorf("DEDUP_ON_CREATE を有効にする場合は PIVOTAL_ID_LABEL_PREFIX を空にできません")
+	}
+
+	if err := validateSummaryFormat(config.SummaryPrefixFormat); err != nil {
+		return nil, err
+	}
+	if err := validatePlaceholders("MIGRATION_FOOTER", config.MigrationFooter, FooterPlaceholders); err != nil {
+		return nil, err
+	}
+
+	if _, _, err := config.CommentRestriction(); err != nil {
+		return nil, err
+	}
+
+	if !slices.Contains(CommentOrders, config.CommentOrder) {
+		return nil, fmt.Errorf("COMMENT_ORDER が不正です: %s（%s のいずれかを指定してください）",
+			config.CommentOrder, strings.Join(CommentOrders, "/"))
+	}
+
+	for header, strategy := range config.MergeColumns {
+		if !slices.Contains(MergeStrategies, strategy) {
+			return nil, fmt.Errorf("MERGE_COLUMNS の結合方法が不正です (%s:%s)。%s のいずれかを指定してください",
+				header, strategy, strings.Join(MergeStrategies, "/"))
+		}
 	}
 
 	return config, nil
 }
 
+// configFromEnv は環境変数から設定を作成します（検証とマッピングファイルの読み込みは LoadConfig で行います）
+// 未設定の項目にはデフォルト値を使うため、環境変数がない状態で呼び出すとデフォルトの設定になります
+func configFromEnv() *Config {
+	return &Config{
+		JiraURL:                 os.Getenv("JIRA_URL"),
+		JiraContextPath:         normalizeContextPath(os.Getenv("JIRA_CONTEXT_PATH")),
+		JiraEmail:               os.Getenv("JIRA_EMAIL"),
+		JiraAPIToken:            os.Getenv("JIRA_API_TOKEN"),
+		JiraProjectKey:          os.Getenv("JIRA_PROJECT_KEY"),
+		JiraDeployment:          strings.ToLower(getEnvWithDefault("JIRA_DEPLOYMENT", DeploymentCloud)),
+		StoryPointField:         getEnvWithDefault("JIRA_STORY_POINT_FIELD", "customfield_10016"),
+		PivotalCSV:              getEnvWithDefault("PIVOTAL_CSV", "pivotal.csv"),
+		JiraCSV:                 getEnvWithDefault("JIRA_CSV", "jira_import_ready.csv"),
+		AttachmentsFolder:       getEnvWithDefault("ATTACHMENTS_FOLDER", "attachments"),
+		AttachmentBatchMB:       getEnvAsIntWithDefault("ATTACHMENT_BATCH_MB", 10),
+		SkipExistingAttachments: getEnvAsBoolWithDefault("SKIP_EXISTING_ATTACHMENTS", false),
+		AttachmentMatchSize:     getEnvAsBoolWithDefault("ATTACHMENT_MATCH_SIZE", false),
+		SummaryJSON:             getEnvAllowEmpty("SUMMARY_JSON", "migration_summary.json"),
+		MappingJSON:             getEnvWithDefault("MAPPING_JSON", "id_mapping.json"),
+		ImportJournal:           getEnvAllowEmpty("IMPORT_JOURNAL", "import_journal.log"),
+		JiraCSVHeaders:          getEnvAsList("JIRA_CSV_HEADERS"),
+		MergeColumns:            getEnvAsMergeColumns("MERGE_COLUMNS", "Comment,Labels,Label,Owned By"),
+		HeaderAliases:           getEnvAsHeaderAliases("HEADER_ALIASES"),
+		CommentOrder:            strings.ToLower(getEnvWithDefault("COMMENT_ORDER", CommentOrderSource)),
+		JiraJSON:                getEnvWithDefault("JIRA_JSON", "jira_import.json"),
+		MaxConcurrent:           getEnvAsIntWithDefault("MAX_CONCURRENT", 10),
+		LogLevel:                os.Getenv("LOG_LEVEL"),
+		Language:                getEnvWithDefault("TOOL_LANG", os.Getenv("LANG")),
+		OrderedLog:              getEnvAsBoolWithDefault("ORDERED_LOG", false),
+		WarnRepeatLimit:         getEnvAsIntWithDefault("WARN_REPEAT_LIMIT", 5),
+
+		// 接続設定
+		JiraProxy:              os.Getenv("JIRA_PROXY"),
+		JiraCACert:             os.Getenv("JIRA_CA_CERT"),
+		JiraInsecureSkipVerify: getEnvAsBoolWithDefault("JIRA_INSECURE_SKIP_VERIFY", false),
+		ConfirmProduction:      getEnvAsBoolWithDefault("CONFIRM_PRODUCTION", false),
+		ProductionURLPattern:   strings.TrimSpace(os.Getenv("PRODUCTION_URL_PATTERN")),
+		NonProductionURLs:      getEnvAsList("NON_PRODUCTION_URLS"),
+		MaxIdleConnsPerHost:    getEnvAsIntWithDefault("MAX_IDLE_CONNS_PER_HOST", 0),
+		MaxConnsPerHost:        getEnvAsIntWithDefault("MAX_CONNS_PER_HOST", 0),
+		IdleConnTimeoutSec:     getEnvAsIntWithDefault("IDLE_CONN_TIMEOUT", 90),
+		RequestTimeoutSec:      getEnvAsIntWithDefault("JIRA_REQUEST_TIMEOUT", 60),
+		UploadTimeoutSec:       getEnvAsIntWithDefault("JIRA_UPLOAD_TIMEOUT", 600),
+		RetryBudget:            getEnvAsIntWithDefault("RETRY_BUDGET", 0),
+		JiraRateLimit:          getEnvAsFloatWithDefault("JIRA_RATE_LIMIT", 0),
+		AutoClampConcurrent:    getEnvAsBoolWithDefault("AUTO_CLAMP_CONCURRENCY", false),
+		AutoTuneConcurrency:    getEnvAsBoolWithDefault("AUTO_TUNE_CONCURRENCY", false),
+
+		// JSONエクスポート設定
+		JSONAttachmentBaseURL:   os.Getenv("JSON_ATTACHMENT_BASE_URL"),
+		JSONStoryPointFieldName: getEnvWithDefault("JSON_STORY_POINT_FIELD_NAME", "Story Points"),
+
+		// ステータス遷移設定
+		TypeStatusMapping:      make(map[string]map[string]string),
+		IssueTypeMapping:       make(map[string]IssueTypeRule),
+		ResolutionMapping:      getEnvAsMapWithDefault("RESOLUTION_MAPPING", DefaultResolutionMapping),
+		TransitionPaths:        getEnvAsPathMap("TRANSITION_PATHS"),
+		DisableTransitionCache: getEnvAsBoolWithDefault("DISABLE_TRANSITION_CACHE", false),
+		MaxTransitionHops:      getEnvAsIntWithDefault("MAX_TRANSITION_HOPS", 5),
+		StrictStatus:           getEnvAsBoolWithDefault("STRICT_STATUS", false),
+		SkipStatuses:           getEnvAsListWithDefault("SKIP_STATUSES", []string{"backlog"}),
+
+		// インポート設定
+		PreserveRank:          getEnvAsBoolWithDefault("PRESERVE_RANK", false),
+		UpdateExisting:        getEnvAsBoolWithDefault("UPDATE_EXISTING", false),
+		DedupOnCreate:         getEnvAsBoolWithDefault("DEDUP_ON_CREATE", false),
+		EpicsFirst:            getEnvAsBoolWithDefault("EPICS_FIRST", false),
+		SkipComments:          getEnvAsBoolWithDefault("SKIP_COMMENTS", false),
+		SkipStatusUpdate:      getEnvAsBoolWithDefault("SKIP_STATUS_UPDATE", false),
+		SkipStoryPoints:       getEnvAsBoolWithDefault("SKIP_STORY_POINTS", false),
+		PivotalIDLabelPrefix:  getEnvAllowEmpty("PIVOTAL_ID_LABEL_PREFIX", "pivotal-"),
+		GlobalLabels:          getEnvAsList("GLOBAL_LABELS"),
+		PivotalIDField:        os.Getenv("PIVOTAL_ID_FIELD"),
+		OriginalCreatedField:  os.Getenv("ORIGINAL_CREATED_FIELD"),
+		OriginalResolvedField: os.Getenv("ORIGINAL_RESOLVED_FIELD"),
+		CustomDateFieldMap:    getEnvAsMapWithDefault("CUSTOM_DATE_FIELD_MAP", nil),
+		MaxErrors:             getEnvAsIntWithDefault("MAX_ERRORS", 0),
+		ContinueOnAuthWarning: getEnvAsBoolWithDefault("CONTINUE_ON_AUTH_WARNING", false),
+		ReleasesAsVersions:    getEnvAsBoolWithDefault("RELEASES_AS_VERSIONS", false),
+		SubtaskIssueType:      NormalizeIssueType(getEnvWithDefault("SUBTASK_ISSUE_TYPE", "Sub-task")),
+		DefaultIssueType:      NormalizeIssueType(getEnvWithDefault("DEFAULT_ISSUE_TYPE", "Task")),
+		FilterStates:          getEnvAsList("FILTER_STATES"),
+		FilterLabels:          getEnvAsList("FILTER_LABELS"),
+		SummaryPrefixFormat:   getEnvWithDefault("SUMMARY_PREFIX_FORMAT", "[{id}] {title}"),
+		MigrationFooter:       strings.TrimSpace(os.Getenv("MIGRATION_FOOTER")),
+		AssigneeNotePrefix:    getEnvWithDefault("ASSIGNEE_NOTE_PREFIX", "担当者:"),
+		ReporterNotePrefix:    getEnvWithDefault("REPORTER_NOTE_PREFIX", "報告者:"),
+		ReviewerField:         os.Getenv("JIRA_REVIEWER_FIELD"),
+		ReviewerNotePrefix:    getEnvWithDefault("REVIEWER_NOTE_PREFIX", "レビュアー:"),
+		CommentVisibility:     strings.TrimSpace(os.Getenv("COMMENT_VISIBILITY")),
+		CommentInternalMarker: strings.TrimSpace(os.Getenv("COMMENT_INTERNAL_MARKER")),
+	}
+}
+
+// StandardIssueTypes はJIRAのデフォルトのイシュータイプスキームに含まれるイシュータイプです
+var StandardIssueTypes = []string{"Story", "Task", "Bug", "Epic", "Sub-task"}
+
+// NormalizeIssueType はイシュータイプ名の前後の空白を除き、標準のイシュータイプと大文字小文字だけが異なる場合は標準の表記に揃えます
+// JIRAはイシュータイプ名の大文字小文字を区別するため、"story" などの指定で作成に失敗しないようにします
+func NormalizeIssueType(name string) string {
+	name = strings.TrimSpace(name)
+	for _, standard := range StandardIssueTypes {
+		if strings.EqualFold(name, standard) {
+			return standard
+		}
+	}
+	return name
+}
+
+// normalizeJiraURL はJIRA_URLを検証し、末尾の "/" や "/rest/..." を取り除いたベースURLを返します
+// CSV変換のみの場合など、未設定の場合はそのまま空文字を返します
+func normalizeJiraURL(raw string) (string, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return "", nil
+	}
+
+	u, err := url.Parse(raw)
+	if err != nil {
+		return "", fmt.Errorf("JIRA_URL が不正です: %s: %w", raw, err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return "", fmt.Errorf("JIRA_URL には http:// または https:// から始まるURLを指定してください: %s", raw)
+	}
+	if u.Host == "" {
+		return "", fmt.Errorf("JIRA_URL にホスト名がありません: %s", raw)
+	}
+	if u.RawQuery != "" || u.Fragment != "" {
+		return "", fmt.Errorf("JIRA_URL にクエリやフラグメントは指定できません: %s", raw)
+	}
+
+	// APIのパスまで含めて指定された場合はベースURLに戻す（コンテキストパス付きのServerは残す）
+	path := u.Path
+	if i := strings.Index(path+"/", "/rest/"); i >= 0 {
+		path = path[:i]
+	}
+	u.Path = strings.TrimRight(path, "/")
+	u.RawPath = ""
+
+	return u.String(), nil
+}
+
+// インポートで省略できる項目（集計の skipped_steps に記録する名前）
+const (
+	StepComments    = "comments"
+	StepStatus      = "status"
+	StepStoryPoints = "story_points"
+)
+
+// SkippedSteps は SKIP_COMMENTS などで省略する項目の一覧を返します
+func (c *Config) SkippedSteps() []string {
+	var steps []string
+	if c.SkipComments {
+		steps = append(steps, StepComments)
+	}
+	if c.SkipStatusUpdate {
+		steps = append(steps, StepStatus)
+	}
+	if c.SkipStoryPoints {
+		steps = append(steps, StepStoryPoints)
+	}
+	return steps
+}
+
+// BrowseURL はイシューをブラウザで開くURLを返します（例: https://example.com/jira/browse/PROJ-123）
+func (c *Config) BrowseURL(issueKey string) string {
+	return c.JiraBaseURL() + "/browse/" + url.PathEscape(issueKey)
+}
+
+// normalizeContextPath はコンテキストパスを先頭に "/" があり末尾に "/" がない形に揃えます（未指定の場合は空文字）
+func normalizeContextPath(path string) string {
+	path = strings.Trim(strings.TrimSpace(path), "/")
+	if path == "" {
+		return ""
+	}
+	return "/" + path
+}
+
+// JiraBaseURL はJIRA_URLにJIRA_CONTEXT_PATHを付けた、REST APIのパスの前までのURLを返します
+// JIRA_URL にすでにコンテキストパスが含まれている場合は重ねて付けません
+func (c *Config) JiraBaseURL() string {
+	if c.JiraContextPath == "" || strings.HasSuffix(c.JiraURL, c.JiraContextPath) {
+		return c.JiraURL
+	}
+	return c.JiraURL + c.JiraContextPath
+}
+
+// RequiresProductionConfirmation は CONFIRM_PRODUCTION が有効で、接続先が本番環境と判定された場合に true を返します
+// NON_PRODUCTION_URLS に含まれるURL・ホスト名は本番とみなしません
+// PRODUCTION_URL_PATTERN を指定した場合は一致するURLのみ、指定しない場合はそれ以外のURLをすべて本番とみなします
+func (c *Config) RequiresProductionConfirmation() bool {
+	if !c.ConfirmProduction {
+		return false
+	}
+
+	jiraURL := strings.TrimRight(c.JiraBaseURL(), "/")
+	host := jiraURL
+	if u, err := url.Parse(jiraURL); err == nil {
+		host = u.Hostname()
+	}
+	for _, allowed := range c.NonProductionURLs {
+		allowed = strings.TrimRight(allowed, "/")
+		if strings.EqualFold(allowed, jiraURL) || strings.EqualFold(allowed, host) {
+			return false
+		}
+	}
+
+	if c.ProductionURLPattern != "" {
+		return regexp.MustCompile(c.ProductionURLPattern).MatchString(jiraURL)
+	}
+	return true
+}
+
+// ConfirmProductionWrite は本番環境と判定された場合に、書き込んでよいか標準入力で確認します
+// skipPrompt が true（-yes フラグ）の場合は確認せずに警告のみ出力します
+// 書き込みを続けてよい場合に true を返します
+func (c *Config) ConfirmProductionWrite(skipPrompt bool) bool {
+	if !c.RequiresProductionConfirmation() {
+		return true
+	}
+
+	if skipPrompt {
+		utils.LogWarn(utils.T("confirm.production_skipped", c.JiraBaseURL()))
+		return true
+	}
+
+	if utils.Confirm(os.Stdin, utils.T("confirm.production_prompt", c.JiraBaseURL(), c.JiraProjectKey)) {
+		return true
+	}
+
+	utils.LogError(utils.T("confirm.production_aborted"))
+	return false
+}
+
+// concurrencyPerRequestRate はレート制限1件/秒あたりに有効な並列数の目安です
+const concurrencyPerRequestRate = 2
+
+// CheckConcurrency は並列数がレート制限に対して過大でないかを確認します
+// 過大な場合は警告し、AUTO_CLAMP_CONCURRENCY が有効なら並列数を抑えます
+func (c *Config) CheckConcurrency() {
+	if c.JiraRateLimit <= 0 {
+		return
+	}
+
+	limit := int(math.Ceil(c.JiraRateLimit * concurrencyPerRequestRate))
+	if c.MaxConcurrent <= limit {
+		return
+	}
+
+	utils.LogWarn(utils.T("config.concurrency_too_high", c.MaxConcurrent, c.JiraRateLimit, limit))
+
+	if c.AutoClampConcurrent {
+		utils.LogWarn(utils.T("config.concurrency_clamped", limit))
+		c.MaxConcurrent = limit
+	}
+}
+
+// SummaryPlaceholders はサマリーのテンプレートで使用できるプレースホルダーです
+var SummaryPlaceholders = []string{"{id}", "{title}"}
+
+// サマリーのテンプレートに未知のプレースホルダーが含まれていないか確認
+func validateSummaryFormat(format string) error {
+	if err := validatePlaceholders("SUMMARY_PREFIX_FORMAT", format, SummaryPlaceholders); err != nil {
+		return err
+	}
+
+	if !strings.Contains(format, "{title}") {
+		return fmt.Errorf("SUMMARY_PREFIX_FORMAT には {title} が必要です: %s", format)
+	}
+
+	return nil
+}
+
+// FooterPlaceholders は移行フッターのテンプレートで使用できるプレースホルダーです
+var FooterPlaceholders = []string{"{date}", "{version}", "{id}"}
+
+// テンプレートに使用できないプレースホルダーが含まれていないか確認
+func validatePlaceholders(key, format string, allowed []string) error {
+	rest := format
+	for {
+		start := strings.Index(rest, "{")
+		if start == -1 {
+			return nil
+		}
+		end := strings.Index(rest[start:], "}")
+		if end == -1 {
+			return fmt.Errorf("%s の '{' が閉じられていません: %s", key, format)
+		}
+
+		placeholder := rest[start : start+end+1]
+		if !slices.Contains(allowed, placeholder) {
+			return fmt.Errorf("%s に不明なプレースホルダー %s があります（使用可能: %s）",
+				key, placeholder, strings.Join(allowed, ", "))
+		}
+		rest = rest[start+end+1:]
+	}
+}
+
 // デフォルト値付きで環境変数を取得
 func getEnvWithDefault(key, defaultValue string) string {
 	value := os.Getenv(key)
@@ -66,6 +735,14 @@ func getEnvWithDefault(key, defaultValue string) string {
 	return value
 }
 
+// 未設定の場合のみデフォルト値を使う（空文字を明示的に指定できる）
+func getEnvAllowEmpty(key, defaultValue string) string {
+	if value, ok := os.LookupEnv(key); ok {
+		return value
+	}
+	return defaultValue
+}
+
 // デフォルト値付きで環境変数を整数として取得
 func getEnvAsIntWithDefault(key string, defaultValue int) int {
 	valueStr := os.Getenv(key)
@@ -80,3 +757,163 @@ func getEnvAsIntWithDefault(key string, defaultValue int) int {
 
 	return value
 }
+
+// デフォルト値付きで環境変数をカンマ区切りのリストとして取得
+func getEnvAsListWithDefault(key string, defaultValue []string) []string {
+	if value := getEnvAsList(key); len(value) > 0 {
+		return value
+	}
+	return defaultValue
+}
+
+// 環境変数をカンマ区切りのリストとして取得
+func getEnvAsList(key string) []string {
+	return SplitList(os.Getenv(key))
+}
+
+// SplitList はカンマ区切りの文字列を前後の空白を除いたリストに分割します（空の要素は除く）
+func SplitList(value string) []string {
+	var result []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			result = append(result, item)
+		}
+	}
+	return result
+}
+
+// デフォルト値付きで環境変数を "キー:値,キー:値" 形式のマップとして取得
+func getEnvAsMapWithDefault(key string, defaultValue map[string]string) map[string]string {
+	valueStr := os.Getenv(key)
+	if valueStr == "" {
+		return defaultValue
+	}
+
+	result := make(map[string]string)
+	for _, pair := range strings.Split(valueStr, ",") {
+		k, v, ok := strings.Cut(pair, ":")
+		if !ok {
+			continue
+		}
+		k = strings.TrimSpace(k)
+		v = strings.TrimSpace(v)
+		if k != "" && v != "" {
+			result[k] = v
+		}
+	}
+
+	return result
+}
+
+// デフォルト値付きで環境変数を浮動小数点数として取得
+func getEnvAsFloatWithDefault(key string, defaultValue float64) float64 {
+	valueStr := os.Getenv(key)
+	if valueStr == "" {
+		return defaultValue
+	}
+
+	value, err := strconv.ParseFloat(valueStr, 64)
+	if err != nil {
+		return defaultValue
+	}
+
+	return value
+}
+
+// デフォルト値付きで環境変数を真偽値として取得
+func getEnvAsBoolWithDefault(key string, defaultValue bool) bool {
+	valueStr := os.Getenv(key)
+	if valueStr == "" {
+		return defaultValue
+	}
+
+	value, err := strconv.ParseBool(valueStr)
+	if err != nil {
+		return defaultValue
+	}
+
+	return value
+}
+
+// 重複したカラムの結合方法
+const (
+	MergeComment = "comment" // コメント用の区切り線で結合
+	MergeComma   = "comma"   // ", " で結合
+	MergeNewline = "newline" // 改行で結合
+	MergeSpace   = "space"   // 空白で結合
+)
+
+// MergeStrategies は MERGE_COLUMNS で指定できる結合方法の一覧です
+var MergeStrategies = []string{MergeComment, MergeComma, MergeNewline, MergeSpace}
+
+// コメントの並び順
+const (
+	CommentOrderSource      = "source"       // CSVの列の順
+	CommentOrderOldestFirst = "oldest-first" // コメントの日時の古い順
+	CommentOrderNewestFirst = "newest-first" // コメントの日時の新しい順
+)
+
+// CommentOrders は COMMENT_ORDER で指定できる並び順の一覧です
+var CommentOrders = []string{CommentOrderSource, CommentOrderOldestFirst, CommentOrderNewestFirst}
+
+// 環境変数を "ヘッダー[:結合方法],..." 形式の結合カラムとして取得
+// 結合方法を省略した場合、Comment はコメント用の区切り線、それ以外はカンマで結合します
+func getEnvAsMergeColumns(key, defaultValue string) map[string]string {
+	result := make(map[string]string)
+	for _, item := range SplitList(getEnvWithDefault(key, defaultValue)) {
+		header, strategy, _ := strings.Cut(item, ":")
+		header = strings.TrimSpace(header)
+		strategy = strings.ToLower(strings.TrimSpace(strategy))
+		if header == "" {
+			continue
+		}
+		if strategy == "" {
+			strategy = MergeComma
+			if header == "Comment" {
+				strategy = MergeComment
+			}
+		}
+		result[header] = strategy
+	}
+	return result
+}
+
+// DefaultHeaderAliases はエクスポート形式によって異なるPivotal CSVのヘッダー名（別名 → 変換で使うヘッダー名）です
+var DefaultHeaderAliases = map[string]string{
+	"ID":          "Id",
+	"Story ID":    "Id",
+	"Name":        "Title",
+	"Label":       "Labels",
+	"Story Type":  "Type",
+	"State":       "Current State",
+	"Story State": "Current State",
+	"Points":      "Estimate",
+	"Owners":      "Owned By",
+	"Owner":       "Owned By",
+	"Requester":   "Requested By",
+}
+
+// 環境変数を "別名:ヘッダー,..." 形式のヘッダーの別名として取得
+// 指定した別名はデフォルト（DefaultHeaderAliases）に追加され、同じ別名はデフォルトより優先されます
+func getEnvAsHeaderAliases(key string) map[string]string {
+	result := maps.Clone(DefaultHeaderAliases)
+	maps.Copy(result, getEnvAsMapWithDefault(key, nil))
+	return result
+}
+
+// 環境変数を "目的ステータス:経由1>経由2>目的ステータス" 形式の経路マップとして取得
+func getEnvAsPathMap(key string) map[string][]string {
+	result := make(map[string][]string)
+	for status, pathStr := range getEnvAsMapWithDefault(key, nil) {
+		var path []string
+		for _, step := range strings.Split(pathStr, ">") {
+			if step = strings.TrimSpace(step); step != "" {
+				path = append(path, step)
+			}
+		}
+		if len(path) > 0 {
+			result[status] = path
+		}
+	}
+	return result
+}
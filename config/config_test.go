@@ -0,0 +1,232 @@
+package config
+
+import (
+	"encoding/base64"
+	"maps"
+	"os"
+	"strings"
+	"testing"
+
+	"pivotaltojira/utils"
+)
+
+// loadTestConfig は JIRA の接続情報と env の環境変数を設定して LoadConfig を呼び出します
+func loadTestConfig(t *testing.T, env map[string]string) (*Config, error) {
+	t.Helper()
+
+	defaults := map[string]string{
+		"JIRA_URL":         "https://jira.example.test",
+		"JIRA_EMAIL":       "tester@example.com",
+		"JIRA_API_TOKEN":   "test-api-token",
+		"JIRA_PROJECT_KEY": "TEST",
+	}
+	for key, value := range defaults {
+		t.Setenv(key, value)
+	}
+	for key, value := range env {
+		t.Setenv(key, value)
+	}
+	return LoadConfig()
+}
+
+func TestSummaryPrefixFormat(t *testing.T) {
+	tests := []struct {
+		format  string
+		wantErr string // 空の場合はエラーにならない
+	}{
+		{"[{id}] {title}", ""},
+		{"{title}", ""},
+		{"{title} (#{id})", ""},
+		{"[{id}]", "{title} が必要です"},
+		{"{name} {title}", "{name}"},
+	}
+	for _, tt := range tests {
+		cfg, err := loadTestConfig(t, map[string]string{"SUMMARY_PREFIX_FORMAT": tt.format})
+		switch {
+		case tt.wantErr == "" && err != nil:
+			t.Errorf("SUMMARY_PREFIX_FORMAT=%q でエラーになりました: %v", tt.format, err)
+		case tt.wantErr == "" && cfg.SummaryPrefixFormat != tt.format:
+			t.Errorf("SummaryPrefixFormat = %q, want %q", cfg.SummaryPrefixFormat, tt.format)
+		case tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)):
+			t.Errorf("SUMMARY_PREFIX_FORMAT=%q のエラー = %v, want %q を含むエラー", tt.format, err, tt.wantErr)
+		}
+	}
+}
+
+func TestLoadConfigRegistersSecrets(t *testing.T) {
+	if _, err := loadTestConfig(t, map[string]string{"JIRA_API_TOKEN": "registered-token"}); err != nil {
+		t.Fatal(err)
+	}
+
+	basic := base64.StdEncoding.EncodeToString([]byte("tester@example.com:registered-token"))
+	message := "token=registered-token, Authorization: Basic " + basic
+	if got, want := utils.Redact(message), "token=***, Authorization: Basic ***"; got != want {
+		t.Errorf("Redact(%q) = %q, want %q", message, got, want)
+	}
+}
+
+func TestNeedsTransition(t *testing.T) {
+	tests := []struct {
+		skip   string
+		status string
+		want   bool
+	}{
+		{"", "Backlog", false}, // デフォルトは backlog
+		{"", "backlog", false},
+		{"", "To Do", true},
+		{"", "  ", false},
+		{"To Do, New,未着手", "to do", false},
+		{"To Do, New,未着手", "NEW", false},
+		{"To Do, New,未着手", "未着手", false},
+		{"To Do, New,未着手", "Backlog", true},
+		{"To Do, New,未着手", "Done", true},
+	}
+	for _, tt := range tests {
+		env := map[string]string{"SKIP_STATUSES": tt.skip}
+		cfg, err := loadTestConfig(t, env)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := cfg.NeedsTransition(tt.status); got != tt.want {
+			t.Errorf("SKIP_STATUSES=%q の NeedsTransition(%q) = %v, want %v", tt.skip, tt.status, got, tt.want)
+		}
+	}
+}
+
+func TestLoadConfigRejectsBadJiraURL(t *testing.T) {
+	tests := []struct {
+		url     string
+		wantErr string
+	}{
+		{"jira.example.com", "http:// または https://"},
+		{"ftp://jira.example.com", "http:// または https://"},
+		{"https://", "ホスト名がありません"},
+		{"https:///rest/api/2", "ホスト名がありません"},
+		{"https://jira.example.com/?os_authType=basic", "クエリやフラグメント"},
+		{"https://jira.example.com/#top", "クエリやフラグメント"},
+		{"https://jira example.com", "JIRA_URL が不正です"},
+		{"://jira.example.com", "JIRA_URL が不正です"},
+	}
+	for _, tt := range tests {
+		_, err := loadTestConfig(t, map[string]string{"JIRA_URL": tt.url})
+		if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+			t.Errorf("JIRA_URL=%q のエラー = %v, want %q を含むエラー", tt.url, err, tt.wantErr)
+		}
+	}
+}
+
+func TestLoadConfigNormalizesJiraURL(t *testing.T) {
+	tests := []struct {
+		url  string
+		want string
+	}{
+		{"https://jira.example.com", "https://jira.example.com"},
+		{"  https://jira.example.com/  ", "https://jira.example.com"},
+		{"https://jira.example.com/rest/api/2", "https://jira.example.com"},
+		{"https://jira.example.com/rest/api/2/", "https://jira.example.com"},
+		{"http://jira.example.com:8080/jira/rest/api/latest", "http://jira.example.com:8080/jira"},
+		{"https://jira.example.com/jira/", "https://jira.example.com/jira"},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		cfg, err := loadTestConfig(t, map[string]string{"JIRA_URL": tt.url})
+		if err != nil {
+			t.Errorf("JIRA_URL=%q でエラーになりました: %v", tt.url, err)
+			continue
+		}
+		if cfg.JiraURL != tt.want {
+			t.Errorf("JIRA_URL=%q → %q, want %q", tt.url, cfg.JiraURL, tt.want)
+		}
+	}
+}
+
+func TestJiraBaseURL(t *testing.T) {
+	tests := []struct {
+		url         string
+		contextPath string
+		want        string
+	}{
+		{"https://jira.example.com", "", "https://jira.example.com"},
+		{"https://jira.example.com", "/jira", "https://jira.example.com/jira"},
+		{"https://jira.example.com/", "jira/", "https://jira.example.com/jira"},
+		// JIRA_URL にすでにコンテキストパスが含まれている場合は重ねて付けない
+		{"https://jira.example.com/jira", "/jira", "https://jira.example.com/jira"},
+		{"https://jira.example.com/jira/rest/api/2", "/jira", "https://jira.example.com/jira"},
+	}
+	for _, tt := range tests {
+		cfg, err := loadTestConfig(t, map[string]string{"JIRA_URL": tt.url, "JIRA_CONTEXT_PATH": tt.contextPath})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := cfg.JiraBaseURL(); got != tt.want {
+			t.Errorf("JIRA_URL=%q, JIRA_CONTEXT_PATH=%q の JiraBaseURL = %q, want %q", tt.url, tt.contextPath, got, tt.want)
+		}
+		if got, want := cfg.BrowseURL("TEST-1"), tt.want+"/browse/TEST-1"; got != want {
+			t.Errorf("BrowseURL = %q, want %q", got, want)
+		}
+	}
+}
+
+func TestMergeColumns(t *testing.T) {
+	cfg, err := loadTestConfig(t, map[string]string{"MERGE_COLUMNS": "Comment, Owned By:NewLine, Label:space"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{"Comment": MergeComment, "Owned By": MergeNewline, "Label": MergeSpace}
+	if !maps.Equal(cfg.MergeColumns, want) {
+		t.Errorf("MergeColumns = %v, want %v", cfg.MergeColumns, want)
+	}
+
+	if _, err := loadTestConfig(t, map[string]string{"MERGE_COLUMNS": "Owned By:tab"}); err == nil || !strings.Contains(err.Error(), "Owned By:tab") {
+		t.Errorf("不正な結合方法のエラー = %v, want Owned By:tab を含むエラー", err)
+	}
+}
+
+func TestNormalizeIssueType(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"Story", "Story"},
+		{"story", "Story"},
+		{" BUG ", "Bug"},
+		{"sub-task", "Sub-task"},
+		{"Improvement", "Improvement"},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := NormalizeIssueType(tt.name); got != tt.want {
+			t.Errorf("NormalizeIssueType(%q) = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+
+	cfg, err := loadTestConfig(t, map[string]string{"DEFAULT_ISSUE_TYPE": "epic", "SUBTASK_ISSUE_TYPE": "SUB-TASK"})
+	if err != nil {
+		t.Fatalf("LoadConfig がエラーを返しました: %v", err)
+	}
+	if cfg.DefaultIssueType != "Epic" || cfg.SubtaskIssueType != "Sub-task" {
+		t.Errorf("DefaultIssueType = %q, SubtaskIssueType = %q, want Epic, Sub-task", cfg.DefaultIssueType, cfg.SubtaskIssueType)
+	}
+}
+
+func TestSummaryJSONAllowsEmpty(t *testing.T) {
+	// 未設定の場合はデフォルトのファイルに書き出す
+	t.Setenv("SUMMARY_JSON", "")
+	os.Unsetenv("SUMMARY_JSON")
+	cfg, err := loadTestConfig(t, nil)
+	if err != nil {
+		t.Fatalf("LoadConfig がエラーを返しました: %v", err)
+	}
+	if cfg.SummaryJSON != "migration_summary.json" {
+		t.Errorf("未設定の SummaryJSON = %q, want migration_summary.json", cfg.SummaryJSON)
+	}
+
+	// 空を指定すると出力しない
+	cfg, err = loadTestConfig(t, map[string]string{"SUMMARY_JSON": ""})
+	if err != nil {
+		t.Fatalf("LoadConfig がエラーを返しました: %v", err)
+	}
+	if cfg.SummaryJSON != "" {
+		t.Errorf("SUMMARY_JSON=\"\" の SummaryJSON = %q, want 空", cfg.SummaryJSON)
+	}
+}
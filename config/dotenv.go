@@ -0,0 +1,51 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/joho/godotenv"
+
+	"pivotaltojira/utils"
+)
+
+// EnvName は読み込む環境別の .env ファイルの名前です（-env フラグで設定します）
+// 空の場合は環境変数 APP_ENV を使い、どちらも空の場合は .env のみを読み込みます
+var EnvName string
+
+// dotenvLogged は読み込んだファイルのログを1回だけ出力するためのものです
+var dotenvLogged sync.Once
+
+// loadDotEnv は .env.<名前> と .env を読み込み、読み込んだファイル名を返します
+// godotenv は設定済みの環境変数を上書きしないため、.env.<名前> の値が .env より優先されます
+// 環境を指定したのにファイルがない場合は、別の環境の認証情報で実行しないようエラーにします
+func loadDotEnv() ([]string, error) {
+	name := strings.TrimSpace(EnvName)
+	if name == "" {
+		name = strings.TrimSpace(os.Getenv("APP_ENV"))
+	}
+
+	var loaded []string
+	if name != "" {
+		file := ".env." + name
+		if err := godotenv.Load(file); err != nil {
+			return nil, fmt.Errorf("環境別の設定ファイル %s を読み込めません（-env または APP_ENV を確認してください）: %w", file, err)
+		}
+		loaded = append(loaded, file)
+	}
+
+	// 共通の設定として .env も読み込む（ない場合は環境変数のみで動作する）
+	if err := godotenv.Load(); err == nil {
+		loaded = append(loaded, ".env")
+	}
+
+	dotenvLogged.Do(func() {
+		if len(loaded) > 0 {
+			utils.LogInfo(utils.T("config.env_loaded", strings.Join(loaded, ", ")))
+		}
+	})
+
+	return loaded, nil
+}
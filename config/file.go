@@ -0,0 +1,160 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// configPrecedence は設定の優先順位の説明です（エラーメッセージで使用）
+const configPrecedence = "優先順位: コマンドラインフラグ > 環境変数 (.env を含む) > 設定ファイル"
+
+// typeStatusMappingKey は設定ファイルでイシュータイプ別のステータスマッピングを指定するキーです
+const typeStatusMappingKey = "type_status_mapping"
+
+// LoadConfigFile はYAMLの設定ファイルと環境変数から設定を読み込みます
+// 設定ファイルのキーは環境変数名を小文字にしたもの (例: jira_url) で、環境変数が設定されていればそちらを優先します
+// path が空の場合は LoadConfig と同じく環境変数のみを使用します
+func LoadConfigFile(path string) (*Config, error) {
+	if path == "" {
+		return LoadConfig()
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("設定ファイル読み込みエラー: %w", err)
+	}
+
+	var raw map[string]interface{}
+	if err := yaml.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("設定ファイル解析エラー (%s): %w（%s）", path, err, configPrecedence)
+	}
+
+	// .env の値を設定ファイルより優先させるため先に読み込む
+	if _, err := loadDotEnv(); err != nil {
+		return nil, err
+	}
+
+	var typeStatusMapping map[string]map[string]string
+	for key, value := range raw {
+		if strings.EqualFold(key, typeStatusMappingKey) {
+			if typeStatusMapping, err = toTypeStatusMapping(value); err != nil {
+				return nil, fmt.Errorf("設定ファイル解析エラー (%s): %s: %w（%s）", path, key, err, configPrecedence)
+			}
+			continue
+		}
+
+		envValue, err := toEnvValue(value)
+		if err != nil {
+			return nil, fmt.Errorf("設定ファイル解析エラー (%s): %s: %w（%s）", path, key, err, configPrecedence)
+		}
+
+		// 環境変数が設定されていれば上書きしない
+		envKey := strings.ToUpper(key)
+		if _, ok := os.LookupEnv(envKey); !ok {
+			os.Setenv(envKey, envValue)
+		}
+	}
+
+	config, err := LoadConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	// STATUS_MAPPING_FILE が指定されていればそちらを優先する
+	if typeStatusMapping != nil && os.Getenv("STATUS_MAPPING_FILE") == "" {
+		config.TypeStatusMapping = normalizeTypeStatusMapping(typeStatusMapping)
+	}
+
+	return config, nil
+}
+
+// toEnvValue は設定ファイルの値を環境変数と同じ形式の文字列に変換します
+// リストはカンマ区切り、マップは "キー:値,キー:値"、マップの値がリストの場合は "キー:値1>値2" になります
+func toEnvValue(value interface{}) (string, error) {
+	switch v := value.(type) {
+	case nil:
+		return "", nil
+	case []interface{}:
+		items := make([]string, 0, len(v))
+		for _, item := range v {
+			s, err := toScalar(item)
+			if err != nil {
+				return "", err
+			}
+			items = append(items, s)
+		}
+		return strings.Join(items, ","), nil
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+
+		pairs := make([]string, 0, len(v))
+		for _, k := range keys {
+			var s string
+			var err error
+			if path, ok := v[k].([]interface{}); ok {
+				steps := make([]string, 0, len(path))
+				for _, step := range path {
+					stepStr, err := toScalar(step)
+					if err != nil {
+						return "", err
+					}
+					steps = append(steps, stepStr)
+				}
+				s = strings.Join(steps, ">")
+			} else if s, err = toScalar(v[k]); err != nil {
+				return "", err
+			}
+			pairs = append(pairs, k+":"+s)
+		}
+		return strings.Join(pairs, ","), nil
+	default:
+		return toScalar(v)
+	}
+}
+
+// toScalar は文字列・数値・真偽値を文字列に変換します
+func toScalar(value interface{}) (string, error) {
+	switch v := value.(type) {
+	case string:
+		return v, nil
+	case int, int64, float64, bool:
+		return fmt.Sprint(v), nil
+	case nil:
+		return "", nil
+	default:
+		return "", fmt.Errorf("文字列・数値・真偽値を指定してください: %v", v)
+	}
+}
+
+// toTypeStatusMapping は type_status_mapping の値を {イシュータイプ: {Pivotalステータス: JIRAステータス}} に変換します
+func toTypeStatusMapping(value interface{}) (map[string]map[string]string, error) {
+	types, ok := value.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("イシュータイプごとのマップを指定してください")
+	}
+
+	mapping := make(map[string]map[string]string, len(types))
+	for issueType, statuses := range types {
+		statusMap, ok := statuses.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("%s: ステータスのマップを指定してください", issueType)
+		}
+		mapping[issueType] = make(map[string]string, len(statusMap))
+		for pivotalStatus, jiraStatus := range statusMap {
+			s, err := toScalar(jiraStatus)
+			if err != nil {
+				return nil, fmt.Errorf("%s.%s: %w", issueType, pivotalStatus, err)
+			}
+			mapping[issueType][pivotalStatus] = s
+		}
+	}
+	return mapping, nil
+}
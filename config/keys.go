@@ -0,0 +1,177 @@
+package config
+
+import (
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"reflect"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ConfigKey は環境変数（設定ファイルのキー）1件分の説明です
+// Config 構造体の env・desc・section タグから作成するため、設定項目を追加するとサンプル設定にも反映されます
+type ConfigKey struct {
+	Env         string // 環境変数名（設定ファイルのキーはこれを小文字にしたもの）
+	Section     string // 設定の区分
+	Description string
+	Default     string // 未設定の場合の値（環境変数と同じ形式。空の場合はデフォルトなし）
+}
+
+// YAMLKey は設定ファイルでのキーを返します
+func (k ConfigKey) YAMLKey() string {
+	return strings.ToLower(k.Env)
+}
+
+// ConfigKeys は Config 構造体の順に、環境変数で指定できるすべての設定項目を返します
+// デフォルト値は環境変数が未設定の状態で LoadConfig と同じ処理を実行して求めます
+// 求める間は環境変数を一時的に削除するため、他の goroutine で設定を読み込んでいる間は呼び出さないでください
+func ConfigKeys() []ConfigKey {
+	t := reflect.TypeOf(Config{})
+
+	var keys []ConfigKey
+	var section string
+	var fields [][]int
+	for i := range t.NumField() {
+		field := t.Field(i)
+		if s := field.Tag.Get("section"); s != "" {
+			section = s
+		}
+		env := field.Tag.Get("env")
+		if env == "" {
+			continue
+		}
+		keys = append(keys, ConfigKey{Env: env, Section: section, Description: field.Tag.Get("desc")})
+		fields = append(fields, field.Index)
+	}
+
+	defaults := reflect.ValueOf(defaultConfig(keys)).Elem()
+	for i := range keys {
+		keys[i].Default = formatEnvValue(defaults.FieldByIndex(fields[i]).Interface())
+	}
+	return keys
+}
+
+// defaultConfig は keys の環境変数（と TOOL_LANG の代わりに参照する LANG）を一時的に削除して、デフォルトの設定を作成します
+func defaultConfig(keys []ConfigKey) *Config {
+	envs := []string{"LANG"}
+	for _, k := range keys {
+		envs = append(envs, k.Env)
+	}
+
+	saved := make(map[string]string)
+	for _, key := range envs {
+		if value, ok := os.LookupEnv(key); ok {
+			saved[key] = value
+			os.Unsetenv(key)
+		}
+	}
+	defer func() {
+		for key, value := range saved {
+			os.Setenv(key, value)
+		}
+	}()
+
+	return configFromEnv()
+}
+
+// formatEnvValue は設定の値を環境変数と同じ形式の文字列に変換します
+// マッピングファイルから読み込む値など、環境変数で表せない値は空文字になります
+func formatEnvValue(value interface{}) string {
+	switch v := value.(type) {
+	case time.Duration:
+		if v == 0 {
+			return ""
+		}
+		return v.String()
+	case string:
+		return v
+	case bool, int, float64:
+		return fmt.Sprint(v)
+	case []string:
+		return strings.Join(v, ",")
+	case map[string]string:
+		pairs := make([]string, 0, len(v))
+		for _, k := range slices.Sorted(maps.Keys(v)) {
+			pairs = append(pairs, k+":"+v[k])
+		}
+		return strings.Join(pairs, ",")
+	case map[string][]string:
+		pairs := make([]string, 0, len(v))
+		for _, k := range slices.Sorted(maps.Keys(v)) {
+			pairs = append(pairs, k+":"+strings.Join(v[k], ">"))
+		}
+		return strings.Join(pairs, ",")
+	default:
+		return ""
+	}
+}
+
+// sampleHeader はサンプル設定の先頭に出力する説明です
+const sampleHeader = `# pivotaltojira の設定のサンプル（init で生成）
+# 値は未設定の場合のデフォルトです。変更する項目だけを残して使ってください
+# 優先順位: コマンドラインフラグ > 環境変数 (.env を含む) > 設定ファイル
+`
+
+// WriteEnvSample はすべての設定項目を説明のコメント付きの .env 形式で書き出します
+func WriteEnvSample(w io.Writer, keys []ConfigKey) error {
+	return writeSample(w, keys, func(k ConfigKey) string {
+		return k.Env + "=" + quoteEnvValue(k.Default)
+	})
+}
+
+// WriteYAMLSample はすべての設定項目を説明のコメント付きの設定ファイル（YAML）の形式で書き出します
+func WriteYAMLSample(w io.Writer, keys []ConfigKey) error {
+	return writeSample(w, keys, func(k ConfigKey) string {
+		return k.YAMLKey() + ": " + quoteYAMLValue(k.Default)
+	})
+}
+
+// writeSample は区分ごとに見出しを付け、各項目の説明と line の行を書き出します
+func writeSample(w io.Writer, keys []ConfigKey, line func(ConfigKey) string) error {
+	var b strings.Builder
+	b.WriteString(sampleHeader)
+
+	var section string
+	for _, k := range keys {
+		if k.Section != section {
+			section = k.Section
+			fmt.Fprintf(&b, "\n# ===== %s =====\n", section)
+		}
+		if k.Description != "" {
+			fmt.Fprintf(&b, "# %s\n", k.Description)
+		}
+		b.WriteString(line(k) + "\n")
+	}
+
+	if _, err := io.WriteString(w, b.String()); err != nil {
+		return fmt.Errorf("サンプル設定書き込みエラー: %w", err)
+	}
+	return nil
+}
+
+// quoteEnvValue は空白・#・引用符・$ を含む値を、そのまま読み込まれるよう単一引用符で囲みます
+func quoteEnvValue(value string) string {
+	if !strings.ContainsAny(value, " \t#\"'$\\") {
+		return value
+	}
+	if !strings.Contains(value, "'") {
+		return "'" + value + "'"
+	}
+	return strconv.Quote(value)
+}
+
+// yamlPlainScalar は引用符なしでも文字列として同じ値に読み込まれる数値・真偽値です
+var yamlPlainScalar = regexp.MustCompile(`^(-?[0-9]+(\.[0-9]+)?|true|false)$`)
+
+// quoteYAMLValue は数値・真偽値以外の値を二重引用符で囲みます
+func quoteYAMLValue(value string) string {
+	if yamlPlainScalar.MatchString(value) {
+		return value
+	}
+	return strconv.Quote(value)
+}
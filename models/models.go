@@ -17,6 +17,12 @@ type PivotalTask struct {
 	Comments     string
 }
 
+// Task はPivotalのストーリー内のタスク（チェックリスト）を表します
+type Task struct {
+	Title     string
+	Completed bool
+}
+
 // JiraIssue はJIRAのイシューを表します
 type JiraIssue struct {
 	ID           string // Pivotal ID (参照用)
@@ -25,7 +31,7 @@ type JiraIssue struct {
 	Description  string
 	Labels       []string
 	Type         string
-	StoryPoints  int
+	StoryPoints  float64
 	Status       string
 	CreatedDate  time.Time
 	ResolvedDate time.Time
@@ -38,3 +44,213 @@ type CSVRecord map[string]string
 
 // IssueMapping はPivotal IDとJIRAキーのマッピングを表します
 type IssueMapping map[string]string
+
+// JiraUser は認証済みのJIRAユーザーを表します
+type JiraUser struct {
+	AccountID    string `json:"accountId"`
+	Name         string `json:"name"` // JIRA Server/Data Center のユーザー名
+	DisplayName  string `json:"displayName"`
+	EmailAddress string `json:"emailAddress"`
+	Active       bool   `json:"active"`
+}
+
+// IssueTypeMeta はプロジェクトのイシュータイプと作成画面のフィールドです
+type IssueTypeMeta struct {
+	ID     string
+	Name   string
+	Fields map[string]FieldMeta // フィールドID → フィールド情報
+}
+
+// FieldMeta は作成画面のフィールドの情報です
+type FieldMeta struct {
+	Name     string
+	Type     string // schema.type (number, string, array など)
+	Required bool
+}
+
+// JiraImportFile はJIRAの外部システムインポート（JSON）のファイル全体です
+type JiraImportFile struct {
+	Projects []JiraImportProject `json:"projects"`
+}
+
+// JiraImportProject はJSONインポートのプロジェクトです
+type JiraImportProject struct {
+	Name     string              `json:"name"`
+	Key      string              `json:"key"`
+	Versions []JiraImportVersion `json:"versions,omitempty"`
+	Issues   []JiraImportIssue   `json:"issues"`
+}
+
+// JiraImportVersion はJSONインポートのバージョンです
+type JiraImportVersion struct {
+	Name     string `json:"name"`
+	Released bool   `json:"released"`
+}
+
+// JiraImportIssue はJSONインポートのイシューです
+type JiraImportIssue struct {
+	ExternalID        string                  `json:"externalId"`
+	Summary           string                  `json:"summary"`
+	Description       string                  `json:"description,omitempty"`
+	IssueType         string                  `json:"issueType"`
+	Status            string                  `json:"status"`
+	Resolution        string                  `json:"resolution,omitempty"`
+	Reporter          string                  `json:"reporter,omitempty"`
+	Assignee          string                  `json:"assignee,omitempty"`
+	Labels            []string                `json:"labels,omitempty"`
+	Created           string                  `json:"created,omitempty"`
+	Resolved          string                  `json:"resolved,omitempty"`
+	FixedVersions     []string                `json:"fixedVersions,omitempty"`
+	CustomFieldValues []JiraImportCustomField `json:"customFieldValues,omitempty"`
+	Comments          []JiraImportComment     `json:"comments,omitempty"`
+	Attachments       []JiraImportAttachment  `json:"attachments,omitempty"`
+}
+
+// JiraImportCustomField はJSONインポートのカスタムフィールドの値です
+type JiraImportCustomField struct {
+	FieldName string `json:"fieldName"`
+	FieldType string `json:"fieldType"`
+	Value     string `json:"value"`
+}
+
+// JiraImportComment はJSONインポートのコメントです
+type JiraImportComment struct {
+	Body    string `json:"body"`
+	Author  string `json:"author,omitempty"`
+	Created string `json:"created,omitempty"`
+}
+
+// JiraImportAttachment はJSONインポートの添付ファイルです（URIはJIRAサーバーから参照できる必要があります）
+type JiraImportAttachment struct {
+	Name    string `json:"name"`
+	URI     string `json:"uri"`
+	Created string `json:"created,omitempty"`
+}
+
+// MigrationSummary は移行処理の実行結果の集計です
+type MigrationSummary struct {
+	StartedAt            time.Time          `json:"started_at"`
+	FinishedAt           time.Time          `json:"finished_at"`
+	Success              bool               `json:"success"`
+	Error                string             `json:"error,omitempty"`
+	RowsRead             int                `json:"rows_read"`
+	IssuesCreated        int                `json:"issues_created"`
+	IssuesUpdated        int                `json:"issues_updated,omitempty"` // -update (UPDATE_EXISTING) で既存イシューを更新した数
+	IssuesFailed         int                `json:"issues_failed"`
+	IssuesReused         int                `json:"issues_reused,omitempty"` // DEDUP_ON_CREATE で作成せずに既存イシューを使った数
+	SkippedSteps         []string           `json:"skipped_steps,omitempty"` // SKIP_COMMENTS などで省略した項目 (comments/status/story_points)
+	AttachmentsUploaded  int                `json:"attachments_uploaded"`
+	AttachmentsFailed    int                `json:"attachments_failed"`
+	AttachmentsSkipped   int                `json:"attachments_skipped,omitempty"` // SKIP_EXISTING_ATTACHMENTS で添付済みのためスキップした数
+	SubtasksCreated      int                `json:"subtasks_created"`
+	SubtasksFailed       int                `json:"subtasks_failed"`
+	StatusUnchanged      []StatusUnchanged  `json:"status_unchanged,omitempty"`
+	EmptyTitles          []string           `json:"empty_titles,omitempty"` // タイトルが空だった行のPivotal ID
+	TransitionCallsSaved int64              `json:"transition_calls_saved"`
+	TunedConcurrency     int                `json:"tuned_concurrency,omitempty"` // AUTO_TUNE_CONCURRENCY で調整した最終的な並列数
+	PhaseSeconds         map[string]float64 `json:"phase_seconds"`
+	PhaseHTTPSeconds     map[string]float64 `json:"phase_http_seconds"` // 各フェーズでJIRAへのリクエストにかかった時間の合計（並列分を含む）
+	TotalSeconds         float64            `json:"total_seconds"`
+	HTTPSeconds          float64            `json:"http_seconds"`            // JIRAへのリクエストにかかった時間の合計（並列分を含む）
+	Retries              int64              `json:"retries"`                 // レート制限などで再試行したリクエストの回数
+	TerminatedBy         string             `json:"terminated_by,omitempty"` // 途中で止めた理由 (deadline: MIGRATION_DEADLINE / retry_budget: RETRY_BUDGET)
+	Config               SummaryConfig      `json:"config"`
+}
+
+// SummaryConfig は集計に記録する設定です（APIトークンなどの秘密情報は含めません）
+type SummaryConfig struct {
+	JiraURL           string `json:"jira_url"`
+	JiraEmail         string `json:"jira_email"`
+	JiraProjectKey    string `json:"jira_project_key"`
+	PivotalCSV        string `json:"pivotal_csv"`
+	JiraCSV           string `json:"jira_csv"`
+	AttachmentsFolder string `json:"attachments_folder"`
+	MaxConcurrent     int    `json:"max_concurrent"`
+}
+
+// StatusUnchanged は目的のステータスに遷移できなかったイシューを表します
+type StatusUnchanged struct {
+	PivotalID    string `json:"pivotal_id"`
+	IssueKey     string `json:"issue_key"`
+	TargetStatus string `json:"target_status"`
+	Reason       string `json:"reason"`
+}
+
+// ConversionCoverage はCSV変換でマッピングできなかった値と、その値を含む行数を表します
+type ConversionCoverage struct {
+	Rows           int            `json:"rows"`
+	UnmappedStates map[string]int `json:"unmapped_states"`
+	UnmappedTypes  map[string]int `json:"unmapped_types"`
+	UnmappedUsers  map[string]int `json:"unmapped_users"`
+	Unestimated    int            `json:"unestimated"` // 見積もりがない（空・未見積もり・数値以外）ためストーリーポイントを設定しない行数
+}
+
+// Complete はすべての値がマッピングできた場合に true を返します
+func (c ConversionCoverage) Complete() bool {
+	return len(c.UnmappedStates) == 0 && len(c.UnmappedTypes) == 0 && len(c.UnmappedUsers) == 0
+}
+
+// IssueDiff は既存のJIRAイシューとCSVの内容の差分を表します
+type IssueDiff struct {
+	PivotalID string
+	IssueKey  string
+	Fields    []FieldDiff
+}
+
+// FieldDiff はフィールド単位の差分を表します
+type FieldDiff struct {
+	Field    string
+	Current  string
+	Expected string
+}
+
+// ImportResult はイシューインポートの結果を表します
+type ImportResult struct {
+	Rows      []RowResult `json:"rows"`
+	Succeeded int         `json:"succeeded"`
+	Failed    int         `json:"failed"`
+}
+
+// RowResult はCSVの1行ごとのインポート結果を表します
+type RowResult struct {
+	Row       int    `json:"row"` // 1始まりの行番号（ヘッダーを除く）
+	PivotalID string `json:"pivotal_id"`
+	IssueKey  string `json:"issue_key,omitempty"`
+	URL       string `json:"url,omitempty"` // イシューの表示URL ({JIRA_URL}/browse/{キー})
+	Updated   bool   `json:"updated"`       // 既存イシューを更新した場合は true
+	// Reused は DEDUP_ON_CREATE でPivotal IDのラベルが付いた既存イシューが見つかり、作成しなかった場合に true になります
+	Reused bool   `json:"reused,omitempty"`
+	Error  string `json:"error,omitempty"`
+	// EmptyTitle は元のタイトルが空で "No Title" として作成した場合に true になります
+	EmptyTitle bool `json:"empty_title,omitempty"`
+	// FieldErrors はイシュー作成後に反映できなかった項目です（フィールドIDまたは status/comment → エラー）
+	FieldErrors map[string]string `json:"field_errors,omitempty"`
+}
+
+// AttachmentResult は添付ファイルアップロードの結果を表します
+type AttachmentResult struct {
+	Files    []AttachmentFileResult `json:"files"`
+	Uploaded int                    `json:"uploaded"`
+	Failed   int                    `json:"failed"`
+	Skipped  int                    `json:"skipped,omitempty"` // 添付済みのためスキップしたファイル数
+	// SkippedPivotalIDs は対応するJIRAイシューが見つからなかったフォルダです
+	SkippedPivotalIDs []string `json:"skipped_pivotal_ids,omitempty"`
+}
+
+// AttachmentFileResult はファイルごとのアップロード結果を表します
+type AttachmentFileResult struct {
+	PivotalID string `json:"pivotal_id"`
+	IssueKey  string `json:"issue_key"`
+	Path      string `json:"path"`
+	// UploadedName は無害化や重複回避で名前を変えてアップロードした場合のファイル名です
+	UploadedName string `json:"uploaded_name,omitempty"`
+	Error        string `json:"error,omitempty"`
+	// Skipped は同じ名前の添付ファイルがイシューにあったためアップロードしなかった場合に true です
+	Skipped bool `json:"skipped,omitempty"`
+}
+
+// JiraAttachment はイシューに添付済みのファイルを表します
+type JiraAttachment struct {
+	Filename string
+	Size     int64
+}
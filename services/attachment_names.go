@@ -0,0 +1,158 @@
+package services
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"unicode"
+
+	"pivotaltojira/models"
+	"pivotaltojira/utils"
+)
+
+// collectAttachmentFiles は各フォルダの <Pivotal ID>/ 直下のファイルを集め、Pivotal ID → ファイルパスを返します
+// 同じPivotal IDのフォルダが複数のフォルダにある場合は、フォルダの順にすべてのファイルをまとめます
+// 戻り値の ids は最初に見つかった順のPivotal IDです
+func collectAttachmentFiles(folders []string) (map[string][]string, []string, error) {
+	files := make(map[string][]string)
+	var ids []string
+
+	for _, folder := range folders {
+		entries, err := os.ReadDir(folder)
+		if err != nil {
+			return nil, nil, fmt.Errorf("フォルダ読み取りエラー: %w", err)
+		}
+
+		for _, entry := range entries {
+			if !entry.IsDir() {
+				continue // ファイルはスキップ
+			}
+
+			pivotalID := entry.Name()
+			issueFolder := filepath.Join(folder, pivotalID)
+			issueFiles, err := os.ReadDir(issueFolder)
+			if err != nil {
+				utils.LogError(utils.T("attachments.read_folder_error", issueFolder, err))
+				continue
+			}
+
+			if _, ok := files[pivotalID]; !ok {
+				ids = append(ids, pivotalID)
+				files[pivotalID] = nil
+			}
+			for _, file := range issueFiles {
+				if !file.IsDir() { // サブフォルダはスキップ
+					files[pivotalID] = append(files[pivotalID], filepath.Join(issueFolder, file.Name()))
+				}
+			}
+		}
+	}
+
+	return files, ids, nil
+}
+
+// attachmentBatches は同じイシューのファイルを、合計サイズが maxBytes 以下になるようにまとめたバッチ（filePaths の添字）に分けます
+// maxBytes を超えるファイルやサイズを取得できないファイルは単独のバッチにします（maxBytes が0以下の場合はすべて1ファイルずつ）
+func attachmentBatches(filePaths []string, maxBytes int64) [][]int {
+	var batches [][]int
+	var current []int
+	var currentBytes int64
+
+	for i, filePath := range filePaths {
+		info, err := os.Stat(filePath)
+		if maxBytes <= 0 || err != nil || info.Size() > maxBytes {
+			batches = append(batches, []int{i})
+			continue
+		}
+		if len(current) > 0 && currentBytes+info.Size() > maxBytes {
+			batches = append(batches, current)
+			current, currentBytes = nil, 0
+		}
+		current = append(current, i)
+		currentBytes += info.Size()
+	}
+	if len(current) > 0 {
+		batches = append(batches, current)
+	}
+
+	return batches
+}
+
+// attachedFiles はアップロードするファイルのうち、イシューに同じ名前の添付ファイルがあるものを返します（添字 → true）
+// matchSize が true の場合はファイルサイズも一致するものだけを添付済みとみなします
+func attachedFiles(existing []models.JiraAttachment, filePaths, uploadNames []string, matchSize bool) map[int]bool {
+	attached := make(map[int]bool)
+	for i, name := range uploadNames {
+		var size int64 = -1
+		if matchSize {
+			info, err := os.Stat(filePaths[i])
+			if err != nil {
+				continue
+			}
+			size = info.Size()
+		}
+		if slices.ContainsFunc(existing, func(a models.JiraAttachment) bool {
+			return a.Filename == name && (!matchSize || a.Size == size)
+		}) {
+			attached[i] = true
+		}
+	}
+	return attached
+}
+
+// defaultAttachmentName は無害化した結果が空になったファイルに使う名前です
+const defaultAttachmentName = "attachment"
+
+// sanitizeFileName はJIRAで扱えない、またはダウンロード時に問題になる文字を "_" に置き換えたファイル名を返します
+// 日本語などのUnicode文字はそのまま残し、制御文字とパス区切りなどの記号のみ置き換えます
+func sanitizeFileName(name string) string {
+	sanitized := strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) || strings.ContainsRune(`\/:*?"<>|`, r) {
+			return '_'
+		}
+		return r
+	}, name)
+
+	// 前後の空白と末尾のピリオドは環境によって取り除かれるため、あらかじめ除く
+	sanitized = strings.TrimLeft(strings.TrimRight(sanitized, " ."), " ")
+	if sanitized == "" {
+		return defaultAttachmentName
+	}
+	return sanitized
+}
+
+// uploadFileNames は同じイシューに添付するファイル名を無害化し、fileNames と同じ順序でアップロードするファイル名を返します
+// 無害化の結果が重複する場合（大文字小文字の違いのみや、複数のフォルダにある同名のファイルを含む）は、
+// 拡張子の前に "_1", "_2" ... を付けて区別します
+func uploadFileNames(fileNames []string) []string {
+	result := make([]string, len(fileNames))
+	used := make(map[string]bool, len(fileNames))
+
+	// 変更不要なファイル名を先に確保し、元の名前のまま添付できるものは名前を変えない
+	for i, name := range fileNames {
+		if sanitizeFileName(name) == name && !used[strings.ToLower(name)] {
+			result[i] = name
+			used[strings.ToLower(name)] = true
+		}
+	}
+
+	for i, name := range fileNames {
+		if result[i] != "" {
+			continue
+		}
+
+		candidate := sanitizeFileName(name)
+		ext := filepath.Ext(candidate)
+		base := strings.TrimSuffix(candidate, ext)
+		for n := 1; used[strings.ToLower(candidate)]; n++ {
+			candidate = fmt.Sprintf("%s_%d%s", base, n, ext)
+		}
+
+		result[i] = candidate
+		used[strings.ToLower(candidate)] = true
+	}
+
+	return result
+}
@@ -0,0 +1,142 @@
+package services
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// writeAttachment は dir/<Pivotal ID>/name に添付ファイルを作成し、そのパスを返します
+func writeAttachment(t *testing.T, dir, pivotalID, name string) string {
+	t.Helper()
+	path := filepath.Join(dir, pivotalID, name)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(name), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestCollectAttachmentFilesMergesFolders(t *testing.T) {
+	first, second := t.TempDir(), t.TempDir()
+	a := writeAttachment(t, first, "100", "design.png")
+	b := writeAttachment(t, first, "200", "log.txt")
+	c := writeAttachment(t, second, "100", "design.png") // 別のフォルダにある同名のファイル
+	d := writeAttachment(t, second, "100", "notes.md")
+	e := writeAttachment(t, second, "300", "spec.pdf")
+	// フォルダ直下のファイルとサブフォルダは対象外
+	writeAttachment(t, second, "", "readme.txt")
+	writeAttachment(t, second, filepath.Join("100", "nested"), "skip.txt")
+
+	files, ids, err := collectAttachmentFiles([]string{first, second})
+	if err != nil {
+		t.Fatalf("collectAttachmentFiles がエラーを返しました: %v", err)
+	}
+
+	if want := []string{"100", "200", "300"}; !slices.Equal(ids, want) {
+		t.Errorf("ids = %q, want %q", ids, want)
+	}
+	want := map[string][]string{
+		"100": {a, c, d},
+		"200": {b},
+		"300": {e},
+	}
+	for id, paths := range want {
+		if !slices.Equal(files[id], paths) {
+			t.Errorf("files[%s] = %q, want %q", id, files[id], paths)
+		}
+	}
+	if len(files) != len(want) {
+		t.Errorf("files = %q, want %d 件", files, len(want))
+	}
+
+	// 同じPivotal IDのファイルはまとめて名前を付けるため、同名のファイルも区別してアップロードする
+	names := make([]string, len(files["100"]))
+	for i, path := range files["100"] {
+		names[i] = filepath.Base(path)
+	}
+	if got, want := uploadFileNames(names), []string{"design.png", "design_1.png", "notes.md"}; !slices.Equal(got, want) {
+		t.Errorf("uploadFileNames = %q, want %q", got, want)
+	}
+}
+
+func TestCollectAttachmentFilesMissingFolder(t *testing.T) {
+	if _, _, err := collectAttachmentFiles([]string{filepath.Join(t.TempDir(), "missing")}); err == nil {
+		t.Error("存在しないフォルダでエラーになりません")
+	}
+}
+
+func TestSanitizeFileName(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"report.pdf", "report.pdf"},
+		{"設計書（最新版）.xlsx", "設計書（最新版）.xlsx"},
+		{"a/b\\c:d*e?f\"g<h>i|j.txt", "a_b_c_d_e_f_g_h_i_j.txt"},
+		{"改行\nあり.txt", "改行_あり.txt"},
+		{"  notes.txt. ", "notes.txt"},
+		{"...", defaultAttachmentName},
+		{"", defaultAttachmentName},
+	}
+	for _, tt := range tests {
+		if got := sanitizeFileName(tt.name); got != tt.want {
+			t.Errorf("sanitizeFileName(%q) = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestUploadFileNames(t *testing.T) {
+	tests := []struct {
+		name      string
+		fileNames []string
+		want      []string
+	}{
+		{
+			name:      "重複なし",
+			fileNames: []string{"a.png", "b.png"},
+			want:      []string{"a.png", "b.png"},
+		},
+		{
+			name:      "大文字小文字の違いのみ",
+			fileNames: []string{"Screenshot.PNG", "screenshot.png", "SCREENSHOT.png"},
+			want:      []string{"Screenshot.PNG", "screenshot_1.png", "SCREENSHOT_2.png"},
+		},
+		{
+			name:      "無害化すると同じ名前",
+			fileNames: []string{"a:b.txt", "a?b.txt", "a_b.txt"},
+			want:      []string{"a_b_1.txt", "a_b_2.txt", "a_b.txt"},
+		},
+		{
+			name:      "日本語のファイル名",
+			fileNames: []string{"仕様書.pdf", "仕様書.pdf", "仕様書/改訂.pdf"},
+			want:      []string{"仕様書.pdf", "仕様書_1.pdf", "仕様書_改訂.pdf"},
+		},
+		{
+			name:      "付与した番号と元の名前の重複",
+			fileNames: []string{"log.txt", "log.txt", "log_1.txt"},
+			want:      []string{"log.txt", "log_2.txt", "log_1.txt"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := uploadFileNames(tt.fileNames)
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("uploadFileNames(%q) = %q, want %q", tt.fileNames, got, tt.want)
+			}
+
+			// JIRAでは大文字小文字の違いのみの名前も区別できないため、小文字にしても重複しない
+			seen := make(map[string]bool)
+			for _, name := range got {
+				if seen[strings.ToLower(name)] {
+					t.Errorf("uploadFileNames(%q) の %q が重複しています", tt.fileNames, name)
+				}
+				seen[strings.ToLower(name)] = true
+			}
+		})
+	}
+}
@@ -0,0 +1,103 @@
+package services
+
+import (
+	"cmp"
+	"maps"
+	"slices"
+	"strings"
+
+	"pivotaltojira/api"
+	"pivotaltojira/config"
+	"pivotaltojira/models"
+	"pivotaltojira/utils"
+)
+
+// ValidateConversion は変換済みのレコードを確認し、マッピングできなかったステータス・イシュータイプ・ユーザーを集計してログに出力します
+// APIを呼び出す前にマッピング（STATUS_MAPPING_FILE など）を見直すために使います
+func (p *CSVProcessor) ValidateConversion(records []models.CSVRecord) models.ConversionCoverage {
+	coverage := newConversionCoverage()
+	for _, record := range records {
+		tallyConversion(p.config, &coverage, record)
+	}
+
+	reportConversionCoverage(coverage)
+	return coverage
+}
+
+// newConversionCoverage は空の集計を作成します
+func newConversionCoverage() models.ConversionCoverage {
+	return models.ConversionCoverage{
+		UnmappedStates: make(map[string]int),
+		UnmappedTypes:  make(map[string]int),
+		UnmappedUsers:  make(map[string]int),
+	}
+}
+
+// tallyConversion は変換済みの1行について、マッピングできなかった値を集計に加えます
+func tallyConversion(cfg *config.Config, coverage *models.ConversionCoverage, record models.CSVRecord) {
+	coverage.Rows++
+
+	// ステータスが空の場合は遷移しないため、マッピング漏れとは扱わない
+	if state := strings.TrimSpace(record["Pivotal State"]); state != "" && record["JIRA Status"] == "" {
+		coverage.UnmappedStates[strings.ToLower(state)]++
+	}
+
+	if pivotalType := strings.TrimSpace(record["Type"]); pivotalType != "" {
+		if !isMappedType(cfg, pivotalType) {
+			coverage.UnmappedTypes[pivotalType]++
+		}
+	}
+
+	// 見積もりのない行は0ではなく未設定のまま作成する
+	if record["Story Points"] == "" {
+		coverage.Unestimated++
+	}
+
+	// 同じ行で担当者・報告者・レビュアーが同じ場合も1行として数える
+	// 担当者は MERGE_COLUMNS で複数を結合している場合があるため、1人ずつ確認する
+	users := make(map[string]bool)
+	people := append(parseUsers(record["Assignee"]), record["Reporter"])
+	for _, user := range append(people, parseUsers(record["Reviewers"])...) {
+		if user = strings.TrimSpace(user); user == "" {
+			continue
+		}
+		if _, ok := api.MapUser(user); !ok {
+			users[user] = true
+		}
+	}
+	for user := range users {
+		coverage.UnmappedUsers[user]++
+	}
+}
+
+// reportConversionCoverage はマッピングできなかった値をログに出力します
+func reportConversionCoverage(coverage models.ConversionCoverage) {
+	logCoverage("coverage.states", coverage.UnmappedStates)
+	logCoverage("coverage.types", coverage.UnmappedTypes)
+	logCoverage("coverage.users", coverage.UnmappedUsers)
+	if coverage.Unestimated > 0 {
+		utils.LogInfo(utils.T("coverage.unestimated", coverage.Unestimated))
+	}
+
+	if coverage.Complete() {
+		utils.LogInfo(utils.T("coverage.complete", coverage.Rows))
+	}
+}
+
+// logCoverage はマッピングできなかった値を行数の多い順に1行で出力します
+func logCoverage(key string, counts map[string]int) {
+	if len(counts) == 0 {
+		return
+	}
+
+	values := slices.SortedFunc(maps.Keys(counts), func(a, b string) int {
+		return cmp.Or(cmp.Compare(counts[b], counts[a]), strings.Compare(a, b))
+	})
+
+	parts := make([]string, len(values))
+	for i, value := range values {
+		parts[i] = utils.T("coverage.rows", value, counts[value])
+	}
+
+	utils.LogWarn(utils.T(key, len(values), strings.Join(parts, ", ")))
+}
@@ -0,0 +1,43 @@
+package services
+
+import (
+	"testing"
+
+	"pivotaltojira/models"
+)
+
+func TestTallyConversionUnestimated(t *testing.T) {
+	cfg := newTestConfig(t, "https://jira.example.test", nil)
+	p := NewCSVProcessor(cfg)
+
+	tests := []struct {
+		estimate        string
+		wantUnestimated bool
+	}{
+		{"-1", true},
+		{"", true},
+		{"?", true},
+		{"0", false},
+		{"2", false},
+	}
+	coverage := newConversionCoverage()
+	want := 0
+	for _, tt := range tests {
+		record := p.convertRecord(models.CSVRecord{"Id": "100", "Title": "見積もり", "Type": "feature", "Estimate": tt.estimate})
+		if got := record["Story Points"]; (got == "") != tt.wantUnestimated {
+			t.Errorf("Estimate %q の Story Points = %q", tt.estimate, got)
+		}
+
+		before := coverage.Unestimated
+		tallyConversion(cfg, &coverage, record)
+		if counted := coverage.Unestimated > before; counted != tt.wantUnestimated {
+			t.Errorf("Estimate %q を未見積もりとして数えたか = %v, want %v", tt.estimate, counted, tt.wantUnestimated)
+		}
+		if tt.wantUnestimated {
+			want++
+		}
+	}
+	if coverage.Unestimated != want || coverage.Rows != len(tests) {
+		t.Errorf("未見積もり = %d / %d 行, want %d / %d 行", coverage.Unestimated, coverage.Rows, want, len(tests))
+	}
+}
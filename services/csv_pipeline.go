@@ -0,0 +1,262 @@
+package services
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"pivotaltojira/models"
+	"pivotaltojira/utils"
+)
+
+// pipelineRecord は行番号（0始まり、ヘッダーを除く）付きのレコードです
+type pipelineRecord struct {
+	index  int
+	record models.CSVRecord
+}
+
+// ConvertPivotalCSVStream はPivotal CSVを1行ずつ読み込みながらJIRA形式に変換し、format の形式で JIRA_CSV のパスに書き出します
+// 読み込み・変換・書き込みを並行して行うため、大きなファイルでも全行をメモリに保持しません。出力の行順は入力と同じです
+// 書き込みは一時ファイルに行い、すべて成功した場合のみ JIRA_CSV を置き換えます
+// 戻り値はマッピングできなかった値の集計です（ValidateConversion と同じ内容）
+func (p *CSVProcessor) ConvertPivotalCSVStream(format string) (models.ConversionCoverage, error) {
+	utils.LogInfo("Pivotal CSVファイル '%s' を読み込みながら変換します", p.config.PivotalCSV)
+
+	coverage := newConversionCoverage()
+
+	input, err := os.Open(p.config.PivotalCSV)
+	if err != nil {
+		return coverage, fmt.Errorf("CSVオープンエラー: %w", err)
+	}
+	defer input.Close()
+
+	reader := csv.NewReader(input)
+	reader.FieldsPerRecord = -1 // フィールド数の不一致を許可
+	headerRow, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return coverage, fmt.Errorf("CSVデータが不足しています")
+	}
+	if err != nil {
+		return coverage, fmt.Errorf("CSV読み込みエラー: %w", err)
+	}
+	parser := p.newPivotalRowParser(headerRow)
+
+	writer, err := p.newJiraRecordWriter(format)
+	if err != nil {
+		return coverage, err
+	}
+	defer writer.abort()
+
+	workers := max(p.config.MaxConcurrent, 1)
+	rows := make(chan pipelineRecord, workers*2)
+	converted := make(chan pipelineRecord, workers*2)
+	done := make(chan struct{})
+	defer close(done)
+
+	// 読み込み: 1行ずつ解析して変換に渡す
+	var readErr error
+	go func() {
+		defer close(rows)
+		for index := 0; ; index++ {
+			fields, err := reader.Read()
+			if errors.Is(err, io.EOF) {
+				return
+			}
+			if err != nil {
+				readErr = fmt.Errorf("CSV読み込みエラー: %w", err)
+				return
+			}
+			record, err := parser.parse(fields, index+2)
+			if err != nil {
+				readErr = err
+				return
+			}
+			select {
+			case rows <- pipelineRecord{index: index, record: record}:
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	// 変換: MAX_CONCURRENT 個のワーカーで並列に変換する
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for row := range rows {
+				select {
+				case converted <- pipelineRecord{index: row.index, record: p.convertRecord(row.record)}:
+				case <-done:
+					return
+				}
+			}
+		}()
+	}
+	go func() {
+		wg.Wait()
+		close(converted)
+	}()
+
+	// 書き込み: 変換が終わった行を元の行順に並べ直して書き出す
+	pending := make(map[int]models.CSVRecord)
+	next := 0
+	for row := range converted {
+		pending[row.index] = row.record
+		for record, ok := pending[next]; ok; record, ok = pending[next] {
+			delete(pending, next)
+			if err := writer.write(record); err != nil {
+				return coverage, err
+			}
+			tallyConversion(p.config, &coverage, record)
+			next++
+
+			// 進捗を表示（大量データの場合）
+			if next%100 == 0 {
+				utils.LogInfo("処理中... %d 行完了", next)
+			}
+		}
+	}
+
+	// converted が閉じられた時点で読み込みは終わっている
+	if readErr != nil {
+		return coverage, readErr
+	}
+	if next == 0 {
+		return coverage, fmt.Errorf("CSVデータが不足しています")
+	}
+	parser.warnUnorderedComments()
+
+	if err := writer.commit(); err != nil {
+		return coverage, err
+	}
+
+	utils.LogInfo("変換完了: %d 行を '%s' に書き込みました", next, p.config.JiraCSV)
+	reportConversionCoverage(coverage)
+	return coverage, nil
+}
+
+// jiraRecordWriter は変換済みのレコードを1行ずつ一時ファイルに書き出し、commit で JIRA_CSV に置き換えます
+type jiraRecordWriter struct {
+	path    string
+	file    *os.File
+	format  string
+	csv     *csv.Writer
+	headers []string
+	count   int
+}
+
+// newJiraRecordWriter は JIRA_CSV と同じフォルダに一時ファイルを作成し、CSVの場合はヘッダーを書き込みます
+func (p *CSVProcessor) newJiraRecordWriter(format string) (*jiraRecordWriter, error) {
+	switch format {
+	case OutputFormatCSV, "":
+		format = OutputFormatCSV
+	case OutputFormatJSON, OutputFormatNDJSON:
+	default:
+		return nil, fmt.Errorf("出力形式が不正です: %s", format)
+	}
+
+	file, err := os.CreateTemp(filepath.Dir(p.config.JiraCSV), "."+filepath.Base(p.config.JiraCSV)+".*.tmp")
+	if err != nil {
+		return nil, fmt.Errorf("出力ファイル作成エラー: %w", err)
+	}
+	w := &jiraRecordWriter{path: p.config.JiraCSV, file: file, format: format}
+
+	switch format {
+	case OutputFormatCSV:
+		// 変換後のレコードはすべて同じカラムを持つため、空の行を変換してカラムを決める
+		w.headers = p.jiraHeaders([]models.CSVRecord{p.convertRecord(models.CSVRecord{})})
+		w.csv = csv.NewWriter(file)
+		if err := w.csv.Write(w.headers); err != nil {
+			w.abort()
+			return nil, fmt.Errorf("ヘッダー書き込みエラー: %w", err)
+		}
+	case OutputFormatJSON:
+		if _, err := io.WriteString(file, "["); err != nil {
+			w.abort()
+			return nil, fmt.Errorf("JSON書き込みエラー: %w", err)
+		}
+	}
+
+	return w, nil
+}
+
+// write は1件のレコードを書き出します
+func (w *jiraRecordWriter) write(record models.CSVRecord) error {
+	defer func() { w.count++ }()
+
+	switch w.format {
+	case OutputFormatCSV:
+		row := make([]string, len(w.headers))
+		for i, header := range w.headers {
+			row[i] = record[header]
+		}
+		if err := w.csv.Write(row); err != nil {
+			return fmt.Errorf("行書き込みエラー: %w", err)
+		}
+		return nil
+	case OutputFormatNDJSON:
+		if err := json.NewEncoder(w.file).Encode(record); err != nil {
+			return fmt.Errorf("行書き込みエラー: %w", err)
+		}
+		return nil
+	}
+
+	// WriteJiraJSON の json.MarshalIndent と同じ形式で配列の要素を書き出す
+	data, err := json.MarshalIndent(record, "  ", "  ")
+	if err != nil {
+		return fmt.Errorf("JSONエンコードエラー: %w", err)
+	}
+	separator := ",\n  "
+	if w.count == 0 {
+		separator = "\n  "
+	}
+	if _, err := w.file.Write(append([]byte(separator), data...)); err != nil {
+		return fmt.Errorf("JSON書き込みエラー: %w", err)
+	}
+	return nil
+}
+
+// commit は書き込みを完了し、一時ファイルを JIRA_CSV に置き換えます
+func (w *jiraRecordWriter) commit() error {
+	switch w.format {
+	case OutputFormatCSV:
+		w.csv.Flush()
+		if err := w.csv.Error(); err != nil {
+			return fmt.Errorf("CSV書き込み完了エラー: %w", err)
+		}
+	case OutputFormatJSON:
+		if _, err := io.WriteString(w.file, "\n]\n"); err != nil {
+			return fmt.Errorf("JSON書き込みエラー: %w", err)
+		}
+	}
+
+	// 一時ファイルは所有者のみ読み書きできるため、os.Create と同じ権限に揃える
+	if err := w.file.Chmod(0o644); err != nil {
+		return fmt.Errorf("出力ファイル権限設定エラー: %w", err)
+	}
+	if err := w.file.Close(); err != nil {
+		return fmt.Errorf("出力ファイル書き込み完了エラー: %w", err)
+	}
+	if err := os.Rename(w.file.Name(), w.path); err != nil {
+		return fmt.Errorf("出力ファイル置き換えエラー: %w", err)
+	}
+	w.file = nil
+	return nil
+}
+
+// abort は commit していない一時ファイルを削除します（commit 後は何もしません）
+func (w *jiraRecordWriter) abort() {
+	if w.file == nil {
+		return
+	}
+	w.file.Close()
+	os.Remove(w.file.Name())
+	w.file = nil
+}
@@ -2,10 +2,19 @@ package services
 
 import (
 	"encoding/csv"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io/fs"
+	"math"
 	"os"
+	"regexp"
+	"slices"
+	"sort"
 	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"pivotaltojira/config"
@@ -46,66 +55,378 @@ func (p *CSVProcessor) ReadPivotalCSV() ([]models.CSVRecord, error) {
 		return nil, fmt.Errorf("CSVデータが不足しています")
 	}
 
-	headers := records[0]
+	parser := p.newPivotalRowParser(records[0])
 	result := make([]models.CSVRecord, 0, len(records)-1)
 
+	for i, record := range records[1:] {
+		rowData, err := parser.parse(record, i+2)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, rowData)
+	}
+
+	parser.warnUnorderedComments()
+
+	utils.LogInfo("Pivotal CSVを読み込みました: %d 行", len(result))
+	return result, nil
+}
+
+// pivotalRowParser はPivotal CSVの1行をヘッダー名 → 値のレコードに変換します
+// 重複したヘッダーの結合（MERGE_COLUMNS）やタスクの結合もここで行います
+type pivotalRowParser struct {
+	p                 *CSVProcessor
+	headers           []string
+	headerIndices     map[string][]int // ヘッダー名 → 列のインデックス（重複したヘッダーは複数）
+	unorderedComments int              // コメントの日時を解釈できず並べ替えなかった行数
+}
+
+// newPivotalRowParser はヘッダー行を正規化し、想定外のヘッダー名（表記ゆれ）を警告してパーサーを作成します
+func (p *CSVProcessor) newPivotalRowParser(rawHeaders []string) *pivotalRowParser {
+	headers := p.normalizeHeaders(rawHeaders)
+
+	// 想定外のヘッダー名（表記ゆれ）を警告
+	p.ValidatePivotalHeaders(headers)
+
 	// ヘッダーの重複をチェックし、インデックスを記録
 	headerIndices := make(map[string][]int)
 	for i, header := range headers {
 		headerIndices[header] = append(headerIndices[header], i)
 	}
 
-	for i, record := range records[1:] {
-		// フィールド数のチェック
-		if len(record) > len(headers) {
-			return nil, fmt.Errorf("行 %d: フィールド数がヘッダー数より多いです（ヘッダー: %d, 行: %d）", i+2, len(headers), len(record))
-		} else if len(record) < len(headers) {
-			utils.LogWarn("行 %d: フィールド数が不一致（ヘッダー: %d, 行: %d）- 不足分は空にします", i+2, len(headers), len(record))
-			// 不足分を埋める
-			newRecord := make([]string, len(headers))
-			copy(newRecord, record)
-			record = newRecord
+	return &pivotalRowParser{p: p, headers: headers, headerIndices: headerIndices}
+}
+
+// parse は lineNo 行目（ヘッダーを1行目とする）のフィールドをレコードに変換します
+func (r *pivotalRowParser) parse(record []string, lineNo int) (models.CSVRecord, error) {
+	p, headers, headerIndices := r.p, r.headers, r.headerIndices
+
+	// フィールド数のチェック
+	if len(record) > len(headers) {
+		return nil, fmt.Errorf("行 %d: フィールド数がヘッダー数より多いです（ヘッダー: %d, 行: %d）", lineNo, len(headers), len(record))
+	} else if len(record) < len(headers) {
+		utils.LogWarn("行 %d: フィールド数が不一致（ヘッダー: %d, 行: %d）- 不足分は空にします", lineNo, len(headers), len(record))
+		// 不足分を埋める
+		newRecord := make([]string, len(headers))
+		copy(newRecord, record)
+		record = newRecord
+	}
+
+	rowData := make(models.CSVRecord)
+
+	// 通常のフィールド処理 (結合するカラムとタスク以外)
+	for header, indices := range headerIndices {
+		_, mergeable := p.config.MergeColumns[header]
+		if !mergeable && header != "Task" && header != "Task Status" {
+			// 他のカラムは最初のインデックスのみ使用
+			if len(indices) > 0 && indices[0] < len(record) {
+				rowData[header] = record[indices[0]]
+			} else {
+				rowData[header] = ""
+			}
 		}
+	}
 
-		rowData := make(models.CSVRecord)
+	// 複数の列に分かれるフィールドの結合（MERGE_COLUMNS）
+	for header, strategy := range p.config.MergeColumns {
+		indices, ok := headerIndices[header]
+		if !ok {
+			continue
+		}
+		var values []string
+		for _, idx := range indices {
+			if value := strings.TrimSpace(record[idx]); value != "" {
+				values = append(values, record[idx])
+			}
+		}
+		if header == "Comment" {
+			var ordered bool
+			if values, ordered = p.orderComments(values); !ordered {
+				r.unorderedComments++
+			}
+		}
+		rowData[header] = strings.Join(values, mergeSeparator(strategy))
+	}
 
-		// 通常のフィールド処理 (Comment以外)
-		for header, indices := range headerIndices {
-			if header != "Comment" {
-				// 他のカラムは最初のインデックスのみ使用
-				if len(indices) > 0 && indices[0] < len(record) {
-					rowData[header] = record[indices[0]]
-				} else {
-					rowData[header] = ""
-				}
+	// Taskフィールドの特別処理（Task Status と順に対応付けて1行1タスクに結合）
+	if taskIndices, ok := headerIndices["Task"]; ok {
+		statusIndices := headerIndices["Task Status"]
+		var tasks []string
+		for n, idx := range taskIndices {
+			task := strings.TrimSpace(record[idx])
+			if task == "" {
+				continue
 			}
+			completed := n < len(statusIndices) && strings.EqualFold(strings.TrimSpace(record[statusIndices[n]]), "completed")
+			tasks = append(tasks, formatTask(task, completed))
 		}
+		rowData["Tasks"] = strings.Join(tasks, "\n")
+	}
 
-		// Commentフィールドの特別処理（結合）
-		if commentIndices, ok := headerIndices["Comment"]; ok && len(commentIndices) > 0 {
-			var comments []string
-			for _, idx := range commentIndices {
-				if idx < len(record) && record[idx] != "" {
-					comments = append(comments, record[idx])
-				}
+	return rowData, nil
+}
+
+// warnUnorderedComments はコメントの日時を解釈できなかった行があれば、まとめて1回警告します
+func (r *pivotalRowParser) warnUnorderedComments() {
+	if r.unorderedComments > 0 {
+		utils.LogWarn("コメントの日時を解釈できなかった %d 行は、COMMENT_ORDER にかかわらずCSVの列の順でコメントを結合しました", r.unorderedComments)
+	}
+}
+
+// commentTimestampPattern はPivotalのコメント末尾の "(作成者 - 日時)" に一致します
+var commentTimestampPattern = regexp.MustCompile(`\(([^()]*) - ([^()]+)\)\s*$`)
+
+// commentTimeFormats はコメント末尾の日時として解釈する形式です
+var commentTimeFormats = []string{
+	"Jan 2, 2006",
+	"January 2, 2006",
+	"Jan 2, 2006 3:04 PM",
+	"2006-01-02",
+	"2006-01-02 15:04:05",
+	time.RFC3339,
+}
+
+// parseCommentTime はコメント末尾の日時を解釈します
+func parseCommentTime(comment string) (time.Time, bool) {
+	match := commentTimestampPattern.FindStringSubmatch(comment)
+	if match == nil {
+		return time.Time{}, false
+	}
+	for _, format := range commentTimeFormats {
+		if t, err := time.Parse(format, strings.TrimSpace(match[2])); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// orderComments はコメントを COMMENT_ORDER の順に並べ替えます
+// 日時が同じコメントは元の順序を保ちます。1つでも日時を解釈できないコメントがある場合は並べ替えずに false を返します
+func (p *CSVProcessor) orderComments(comments []string) ([]string, bool) {
+	if p.config.CommentOrder == config.CommentOrderSource || len(comments) < 2 {
+		return comments, true
+	}
+
+	times := make([]time.Time, len(comments))
+	indices := make([]int, len(comments))
+	for i, comment := range comments {
+		t, ok := parseCommentTime(comment)
+		if !ok {
+			return comments, false
+		}
+		times[i] = t
+		indices[i] = i
+	}
+
+	newestFirst := p.config.CommentOrder == config.CommentOrderNewestFirst
+	slices.SortStableFunc(indices, func(a, b int) int {
+		if newestFirst {
+			return times[b].Compare(times[a])
+		}
+		return times[a].Compare(times[b])
+	})
+
+	ordered := make([]string, len(comments))
+	for i, idx := range indices {
+		ordered[i] = comments[idx]
+	}
+	return ordered, true
+}
+
+// タスクの完了・未完了を表す接頭辞です（"Tasks" カラムは1行1タスク）
+const (
+	taskDonePrefix = "[x]"
+	taskTodoPrefix = "[ ]"
+)
+
+// formatTask はタスクを "Tasks" カラムの1行の形式にします
+func formatTask(task string, completed bool) string {
+	// 改行を含むタスクは1行にまとめる
+	task = strings.Join(strings.Fields(task), " ")
+	if completed {
+		return taskDonePrefix + " " + task
+	}
+	return taskTodoPrefix + " " + task
+}
+
+// parseTasks は "Tasks" カラムをタスク名と完了状態の一覧に分解します
+func parseTasks(tasks string) []models.Task {
+	var result []models.Task
+	for _, line := range strings.Split(tasks, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		task := models.Task{Title: line}
+		switch {
+		case strings.HasPrefix(line, taskDonePrefix):
+			task.Title = strings.TrimSpace(strings.TrimPrefix(line, taskDonePrefix))
+			task.Completed = true
+		case strings.HasPrefix(line, taskTodoPrefix):
+			task.Title = strings.TrimSpace(strings.TrimPrefix(line, taskTodoPrefix))
+		}
+		if task.Title != "" {
+			result = append(result, task)
+		}
+	}
+	return result
+}
+
+// commentSeparator は複数のコメントを1つのカラムに結合する際の区切りです
+const commentSeparator = "\n\n===========================\n\n"
+
+// mergeSeparator は MERGE_COLUMNS の結合方法に対応する区切りを返します
+func mergeSeparator(strategy string) string {
+	switch strategy {
+	case config.MergeComment:
+		return commentSeparator
+	case config.MergeNewline:
+		return "\n"
+	case config.MergeSpace:
+		return " "
+	default:
+		return ", "
+	}
+}
+
+// normalizeHeaders はエクスポート形式による別名のヘッダー（HEADER_ALIASES）を変換で使うヘッダー名に置き換えます
+// 本来のヘッダーが既にある場合は置き換えず、どの別名を使ったかをログに出力します
+func (p *CSVProcessor) normalizeHeaders(headers []string) []string {
+	present := make(map[string]bool, len(headers))
+	for _, header := range headers {
+		present[header] = true
+	}
+
+	normalized := slices.Clone(headers)
+	logged := make(map[string]bool)
+	for i, header := range headers {
+		canonical := ""
+		for alias, name := range p.config.HeaderAliases {
+			if strings.EqualFold(strings.TrimSpace(header), alias) {
+				canonical = name
+				break
 			}
+		}
+		if canonical == "" || canonical == header || present[canonical] {
+			continue
+		}
+		normalized[i] = canonical
+		if !logged[header] {
+			logged[header] = true
+			utils.LogInfo("ヘッダー '%s' を '%s' として読み込みます", header, canonical)
+		}
+	}
 
-			// コメントを区切り線で結合
-			if len(comments) > 0 {
-				rowData["Comment"] = strings.Join(comments, "\n\n===========================\n\n")
-			} else {
-				rowData["Comment"] = ""
+	return normalized
+}
+
+// ExpectedPivotalHeaders は変換で使用するPivotal CSVのヘッダーです
+var ExpectedPivotalHeaders = []string{
+	"Id", "Title", "Description", "Labels", "Type", "Current State", "Estimate",
+	"Created at", "Accepted at", "Owned By", "Requested By", "Comment",
+}
+
+// ValidatePivotalHeaders は想定しているヘッダーが存在するかを確認します
+// 見つからないヘッダーごとに最も近い実際のヘッダー名を候補として警告し、見つからないヘッダーの一覧を返します
+func (p *CSVProcessor) ValidatePivotalHeaders(headers []string) []string {
+	actual := make(map[string]bool, len(headers))
+	for _, header := range headers {
+		actual[header] = true
+	}
+
+	var missing []string
+	for _, expected := range ExpectedPivotalHeaders {
+		if actual[expected] {
+			continue
+		}
+		missing = append(missing, expected)
+
+		if suggestion := closestHeader(expected, headers); suggestion != "" {
+			utils.LogWarn("ヘッダー '%s' が見つかりません。'%s' のことですか？", expected, suggestion)
+		} else {
+			utils.LogWarn("ヘッダー '%s' が見つかりません。この列は空として扱います", expected)
+		}
+	}
+
+	return missing
+}
+
+// closestHeader は期待するヘッダーに最も近い実際のヘッダーを返します（近いものがなければ空文字）
+func closestHeader(expected string, headers []string) string {
+	best := ""
+	bestDistance := max(len([]rune(expected))/2, 2) + 1
+
+	for _, header := range headers {
+		if slices.Contains(ExpectedPivotalHeaders, header) {
+			continue // 既に正しく使われているヘッダーは候補にしない
+		}
+
+		d := levenshtein(strings.ToLower(expected), strings.ToLower(header))
+		if d < bestDistance {
+			best = header
+			bestDistance = d
+		}
+	}
+
+	return best
+}
+
+// levenshtein は２つの文字列の編集距離を返します
+func levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
 			}
+			curr[j] = min(min(prev[j]+1, curr[j-1]+1), prev[j-1]+cost)
 		}
+		prev, curr = curr, prev
+	}
 
-		result = append(result, rowData)
+	return prev[len(rb)]
+}
+
+// parseStoryPoints はPivotalの見積もり（Estimate）をストーリーポイントとして解釈します
+// 空・未見積もり（"unestimated"、-1、"?"）・数値以外・負の値の場合は false を返します
+func parseStoryPoints(value string) (float64, bool) {
+	value = strings.TrimSpace(value)
+	if value == "" || isUnestimated(value) {
+		return 0, false
 	}
+	points, err := strconv.ParseFloat(value, 64)
+	if err != nil || points < 0 || math.IsNaN(points) || math.IsInf(points, 0) {
+		return 0, false
+	}
+	return points, true
+}
 
-	utils.LogInfo("Pivotal CSVを読み込みました: %d 行", len(result))
-	return result, nil
+// unestimatedMarkers はPivotalのエクスポートで未見積もりを表す値です（大文字小文字を区別しない）
+var unestimatedMarkers = []string{"unestimated", "-1", "?"}
+
+// isUnestimated はPivotalで未見積もりを表す値かを返します
+func isUnestimated(value string) bool {
+	return slices.ContainsFunc(unestimatedMarkers, func(marker string) bool {
+		return strings.EqualFold(value, marker)
+	})
+}
+
+// formatStoryPoints はストーリーポイントを余分な0のない文字列にします（1.5 → "1.5", 2 → "2"）
+func formatStoryPoints(points float64) string {
+	return strconv.FormatFloat(points, 'f', -1, 64)
 }
 
 // ProcessPivotalToJiraCSV はPivotalデータをJIRA用に変換します
+// 各行の変換は独立しているため MaxConcurrent 個のワーカーで並列に処理し、元の行順を維持します
 func (p *CSVProcessor) ProcessPivotalToJiraCSV(records []models.CSVRecord) ([]models.CSVRecord, error) {
 	utils.LogInfo("PivotalデータをJIRA形式に変換しています...")
 
@@ -113,89 +434,166 @@ func (p *CSVProcessor) ProcessPivotalToJiraCSV(records []models.CSVRecord) ([]mo
 		return nil, fmt.Errorf("処理するデータがありません")
 	}
 
-	result := make([]models.CSVRecord, 0, len(records))
+	// 元の行順で結果を格納するためインデックス付きで書き込む
+	result := make([]models.CSVRecord, len(records))
 
-	// PivotalからJIRAへの変換処理
-	for i, record := range records {
-		jiraRecord := make(models.CSVRecord)
+	workers := p.config.MaxConcurrent
+	if workers <= 0 {
+		workers = 1
+	}
 
-		// 基本フィールドをマッピング
-		jiraRecord["JIRA Issue ID"] = record["Id"]
-		jiraRecord["Title"] = record["Title"]
-		jiraRecord["Description"] = record["Description"]
-		jiraRecord["Labels"] = record["Labels"]
-		jiraRecord["Type"] = record["Type"]
+	indices := make(chan int)
+	var wg sync.WaitGroup
+	var processed int64
 
-		// ステータスマッピング
-		pivotalStatus := strings.ToLower(record["Current State"])
-		jiraRecord["JIRA Status"] = config.StatusMapping[pivotalStatus]
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indices {
+				result[i] = p.convertRecord(records[i])
 
-		// ストーリーポイント変換
-		storyPoints := 0
-		if estimate, ok := record["Estimate"]; ok && estimate != "" {
-			storyPoints, _ = strconv.Atoi(estimate)
-		}
-		jiraRecord["Story Points"] = strconv.Itoa(storyPoints)
+				// 進捗を表示（大量データの場合）
+				if n := atomic.AddInt64(&processed, 1); n%100 == 0 {
+					utils.LogInfo("処理中... %d/%d 行完了", n, len(records))
+				}
+			}
+		}()
+	}
 
-		// 日付フォーマット変換
-		jiraRecord["Created Date"] = p.convertDateFormat(record["Created at"])
-		jiraRecord["Resolved Date"] = p.convertDateFormat(record["Accepted at"])
+	for i := range records {
+		indices <- i
+	}
+	close(indices)
+	wg.Wait()
 
-		// 担当者
-		jiraRecord["Assignee"] = record["Owned By"]
+	utils.LogInfo("変換完了: %d 行を処理しました", len(result))
+	return result, nil
+}
 
-		// 報告者
-		jiraRecord["Reporter"] = record["Requested By"]
+// convertRecord はPivotalの1行をJIRA用の1行に変換します
+func (p *CSVProcessor) convertRecord(record models.CSVRecord) models.CSVRecord {
+	jiraRecord := make(models.CSVRecord)
+
+	// 基本フィールドをマッピング
+	jiraRecord["JIRA Issue ID"] = record["Id"]
+	jiraRecord["Title"] = record["Title"]
+	jiraRecord["Description"] = record["Description"]
+	jiraRecord["Labels"] = joinNonEmpty(", ", record["Labels"], record["Label"])
+	jiraRecord["Type"] = record["Type"]
+
+	// ステータスマッピング（TYPE_MAPPING_FILE で種別に指定したステータス、イシュータイプ別の設定の順に優先）
+	pivotalStatus := strings.ToLower(record["Current State"])
+	jiraRecord["JIRA Status"] = p.config.MapStatus(resolveIssueType(p.config, record["Type"]), pivotalStatus)
+	if rule, ok := p.config.LookupIssueType(record["Type"]); ok && rule.Status != "" {
+		jiraRecord["JIRA Status"] = rule.Status
+	}
+	// 絞り込みやマッピングの確認用に元のステータスも残す
+	jiraRecord["Pivotal State"] = record["Current State"]
+
+	// ストーリーポイント変換（ポイント制のプロジェクトの 0.5 などの小数も保持し、見積もりがない場合は空）
+	jiraRecord["Story Points"] = ""
+	if storyPoints, ok := parseStoryPoints(record["Estimate"]); ok {
+		jiraRecord["Story Points"] = formatStoryPoints(storyPoints)
+	} else if estimate := strings.TrimSpace(record["Estimate"]); estimate != "" && !isUnestimated(estimate) {
+		utils.LogWarn("ストーリーポイント変換エラー: '%s'（Pivotal ID: %s）", estimate, record["Id"])
+	}
 
-		// コメント
-		jiraRecord["Comment"] = record["Comment"]
+	// 日付フォーマット変換
+	jiraRecord["Created Date"] = p.convertDateFormat(record["Created at"])
+	jiraRecord["Resolved Date"] = p.convertDateFormat(record["Accepted at"])
 
+	// CUSTOM_DATE_FIELD_MAP のカラムは同じ名前のカラムにJIRAの日時の形式で出力する（変換できない値は空）
+	for column := range p.config.CustomDateFieldMap {
+		jiraRecord[column] = p.convertDateFormat(record[column])
+	}
 
-		// JIRA Issue Keyは後で更新
-		jiraRecord["JIRA Issue Key"] = ""
+	// 担当者
+	jiraRecord["Assignee"] = record["Owned By"]
 
-		result = append(result, jiraRecord)
+	// 報告者
+	jiraRecord["Reporter"] = record["Requested By"]
 
-		// 進捗を表示（大量データの場合）
-		if i > 0 && i%100 == 0 {
-			utils.LogInfo("処理中... %d/%d 行完了", i, len(records))
-		}
-	}
+	// レビュアー（カンマ区切りで複数）
+	jiraRecord["Reviewers"] = record["Reviewers"]
 
-	utils.LogInfo("変換完了: %d 行を処理しました", len(result))
-	return result, nil
+	// コメント
+	jiraRecord["Comment"] = record["Comment"]
+
+	// タスク（サブタスクとして作成）
+	jiraRecord["Tasks"] = record["Tasks"]
+
+	// JIRA Issue Keyは後で更新
+	jiraRecord["JIRA Issue Key"] = ""
+
+	return jiraRecord
 }
 
-// ReadCSV は汎用CSVリーダーです
-func (p *CSVProcessor) ReadCSV(filePath string) ([]models.CSVRecord, error) {
-    file, err := os.Open(filePath)
-    if err != nil {
-        return nil, fmt.Errorf("CSVオープンエラー: %w", err)
-    }
-    defer file.Close()
+// CSVTable はヘッダーと行をそのまま保持したCSVです
+// インポート中は1回読み込んだ内容を保持し、JIRAキーの更新もこの内容から書き出します
+type CSVTable struct {
+	Headers []string
+	Rows    [][]string
+}
 
-    reader := csv.NewReader(file)
-    records, err := reader.ReadAll()
-    if err != nil {
-        return nil, fmt.Errorf("CSV読み込みエラー: %w", err)
-    }
+// ReadCSVTable はCSVファイルをヘッダーと行のまま読み込みます
+func (p *CSVProcessor) ReadCSVTable(filePath string) (*CSVTable, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("CSVオープンエラー: %w", err)
+	}
+	defer file.Close()
 
-    if len(records) < 2 {
-        return nil, fmt.Errorf("CSVデータが不足しています")
-    }
+	reader := csv.NewReader(file)
+	reader.FieldsPerRecord = -1 // Errorカラムの有無で列数が異なる行を許可
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("CSV読み込みエラー: %w", err)
+	}
 
-    headers := records[0]
-    result := make([]models.CSVRecord, 0, len(records)-1)
+	if len(records) < 2 {
+		return nil, fmt.Errorf("CSVデータが不足しています")
+	}
 
-    for _, record := range records[1:] {
-        rowData := make(models.CSVRecord)
-        for j := 0; j < min(len(headers), len(record)); j++ {
-            rowData[headers[j]] = record[j]
-        }
-        result = append(result, rowData)
-    }
+	return &CSVTable{Headers: records[0], Rows: records[1:]}, nil
+}
 
-    return result, nil
+// Records は各行をヘッダー名→値のマップに変換します
+func (t *CSVTable) Records() []models.CSVRecord {
+	result := make([]models.CSVRecord, 0, len(t.Rows))
+	for _, row := range t.Rows {
+		rowData := make(models.CSVRecord)
+		for j := 0; j < min(len(t.Headers), len(row)); j++ {
+			rowData[t.Headers[j]] = row[j]
+		}
+		result = append(result, rowData)
+	}
+	return result
+}
+
+// normalizeRows は各行の列数をヘッダーに揃えます
+// 不足する列は空文字で埋め、ヘッダーより多い列（名前のない列）は警告して取り除きます
+func (t *CSVTable) normalizeRows() {
+	for i, row := range t.Rows {
+		switch {
+		case len(row) < len(t.Headers):
+			padded := make([]string, len(t.Headers))
+			copy(padded, row)
+			t.Rows[i] = padded
+		case len(row) > len(t.Headers):
+			utils.LogWarn("行 %d: フィールド数がヘッダー数より多いため、超過した %d 列を取り除きます", i+2, len(row)-len(t.Headers))
+			t.Rows[i] = row[:len(t.Headers)]
+		}
+	}
+}
+
+// ReadCSV は汎用CSVリーダーです
+func (p *CSVProcessor) ReadCSV(filePath string) ([]models.CSVRecord, error) {
+	table, err := p.ReadCSVTable(filePath)
+	if err != nil {
+		return nil, err
+	}
+	return table.Records(), nil
 }
 
 // min は２つの整数の小さい方を返します
@@ -220,12 +618,8 @@ func (p *CSVProcessor) WriteJiraCSV(records []models.CSVRecord) error {
 	}
 	defer file.Close()
 
-	// 出力するフィールドと順序を定義
-	headers := []string{
-		"JIRA Issue ID", "Title", "Description", "Labels", "Type",
-		"JIRA Status", "Story Points", "Created Date", "Resolved Date",
-		"Assignee", "Reporter", "Comment", "JIRA Issue Key",
-	}
+	// 出力するフィールドと順序を決定
+	headers := p.jiraHeaders(records)
 
 	writer := csv.NewWriter(file)
 	if err := writer.Write(headers); err != nil {
@@ -251,196 +645,355 @@ func (p *CSVProcessor) WriteJiraCSV(records []models.CSVRecord) error {
 	return nil
 }
 
-// LoadIssueMapping はCSVからPivotal ID → JIRA Key のマッピングを読み込みます
-func (p *CSVProcessor) LoadIssueMapping() (models.IssueMapping, error) {
-	utils.LogInfo("イシューマッピングを読み込んでいます...")
+// AppendJiraCSV は既存のJIRA CSVの末尾に行を追記し、追記した行数を返します
+// 複数のPivotalのエクスポートを1つのJIRA CSVにまとめる場合に使い、既存のCSVと同じ JIRA Issue ID の行（と追記する行どうしの重複）は追記しません
+// 既存のCSVのヘッダーに追記する行のカラムがない場合はエラーを返し、ファイルがない場合は WriteJiraCSV と同じく新しく作成します
+func (p *CSVProcessor) AppendJiraCSV(records []models.CSVRecord) (int, error) {
+	if _, err := os.Stat(p.config.JiraCSV); errors.Is(err, fs.ErrNotExist) {
+		if err := p.WriteJiraCSV(records); err != nil {
+			return 0, err
+		}
+		return len(records), nil
+	}
 
-	file, err := os.Open(p.config.JiraCSV)
+	utils.LogInfo(utils.T("convert.append_existing", p.config.JiraCSV))
+
+	headers, existingIDs, err := readJiraCSVIDs(p.config.JiraCSV)
 	if err != nil {
-		return nil, fmt.Errorf("マッピングCSVオープンエラー: %w", err)
+		return 0, err
+	}
+
+	// 既存のヘッダーの順で書き込むため、追記する行のカラムがすべて必要
+	var missing []string
+	for _, header := range p.jiraHeaders(records) {
+		if !slices.Contains(headers, header) {
+			missing = append(missing, header)
+		}
+	}
+	if len(missing) > 0 {
+		return 0, errors.New(utils.T("convert.append_incompatible", p.config.JiraCSV, strings.Join(missing, ", ")))
+	}
+
+	var rows [][]string
+	skipped := 0
+	for _, record := range records {
+		id := record["JIRA Issue ID"]
+		if id != "" && existingIDs[id] {
+			skipped++
+			continue
+		}
+		existingIDs[id] = id != ""
+
+		row := make([]string, len(headers))
+		for i, header := range headers {
+			row[i] = record[header]
+		}
+		rows = append(rows, row)
+	}
+	if skipped > 0 {
+		utils.LogInfo(utils.T("convert.append_duplicates", skipped))
+	}
+	if len(rows) == 0 {
+		utils.LogInfo(utils.T("convert.append_nothing"))
+		return 0, nil
+	}
+
+	if err := appendCSVRows(p.config.JiraCSV, rows); err != nil {
+		return 0, err
+	}
+
+	utils.LogInfo(utils.T("convert.append_done", len(rows)))
+	return len(rows), nil
+}
+
+// readJiraCSVIDs はJIRA CSVのヘッダーと、記載済みの JIRA Issue ID を読み込みます
+func readJiraCSVIDs(path string) ([]string, map[string]bool, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, nil, fmt.Errorf("CSVオープンエラー: %w", err)
 	}
 	defer file.Close()
 
 	reader := csv.NewReader(file)
+	reader.FieldsPerRecord = -1 // Errorカラムの有無で列数が異なる行を許可
 	records, err := reader.ReadAll()
 	if err != nil {
-		return nil, fmt.Errorf("マッピングCSV読み込みエラー: %w", err)
+		return nil, nil, fmt.Errorf("CSV読み込みエラー: %w", err)
 	}
-
-	if len(records) < 2 {
-		return nil, fmt.Errorf("マッピングデータが不足しています")
+	if len(records) == 0 {
+		return nil, nil, errors.New(utils.T("convert.append_no_header", path))
 	}
 
 	headers := records[0]
-	var idIndex, keyIndex int = -1, -1
+	idIndex := slices.Index(headers, "JIRA Issue ID")
+	if idIndex < 0 {
+		return nil, nil, errors.New(utils.T("convert.append_no_id", path))
+	}
 
-	for i, header := range headers {
-		if header == "JIRA Issue ID" {
-			idIndex = i
-		} else if header == "JIRA Issue Key" {
-			keyIndex = i
+	ids := make(map[string]bool, len(records)-1)
+	for _, row := range records[1:] {
+		if idIndex < len(row) && row[idIndex] != "" {
+			ids[row[idIndex]] = true
 		}
 	}
+	return headers, ids, nil
+}
 
-	if idIndex == -1 || keyIndex == -1 {
-		return nil, fmt.Errorf("マッピングに必要なカラムが見つかりません")
+// appendCSVRows はCSVファイルの末尾に行を追記します（末尾に改行がない場合は補います）
+func appendCSVRows(path string, rows [][]string) error {
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_APPEND, 0)
+	if err != nil {
+		return fmt.Errorf("CSVオープンエラー: %w", err)
 	}
+	defer file.Close()
 
-	mapping := make(models.IssueMapping)
-	for _, record := range records[1:] {
-		if len(record) <= max(idIndex, keyIndex) {
-			continue
+	if info, err := file.Stat(); err == nil && info.Size() > 0 {
+		last := make([]byte, 1)
+		if _, err := file.ReadAt(last, info.Size()-1); err == nil && last[0] != '\n' {
+			if _, err := file.WriteString("\n"); err != nil {
+				return fmt.Errorf("行書き込みエラー: %w", err)
+			}
 		}
+	}
 
-		pivotalID := record[idIndex]
-		jiraKey := record[keyIndex]
-		if pivotalID != "" && jiraKey != "" && jiraKey != "ERROR" {
-			mapping[pivotalID] = jiraKey
-		}
+	writer := csv.NewWriter(file)
+	if err := writer.WriteAll(rows); err != nil {
+		return fmt.Errorf("CSV書き込み完了エラー: %w", err)
 	}
 
-	utils.LogInfo("イシューマッピングをロードしました: %d 件", len(mapping))
-	return mapping, nil
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("CSV書き込み完了エラー: %w", err)
+	}
+	return nil
 }
 
-// UpdateJiraKeys はCSVファイルのJIRAキーを更新します
-func (p *CSVProcessor) UpdateJiraKeys(mapping models.IssueMapping) error {
-	utils.LogInfo("JIRAキーをCSVファイルに更新しています...")
+// 変換結果の出力形式
+const (
+	OutputFormatCSV    = "csv"    // JIRA CSV（issue_import の入力）
+	OutputFormatJSON   = "json"   // レコードのJSON配列
+	OutputFormatNDJSON = "ndjson" // 1行1レコードのJSON
+)
 
-	// CSVを読み込む
-	file, err := os.Open(p.config.JiraCSV)
-	if err != nil {
-		return fmt.Errorf("CSVオープンエラー: %w", err)
+// OutputFormats は変換結果に指定できる出力形式の一覧です
+var OutputFormats = []string{OutputFormatCSV, OutputFormatJSON, OutputFormatNDJSON}
+
+// WriteJiraRecords は変換結果を指定した形式で JIRA_CSV のパスに書き出します
+func (p *CSVProcessor) WriteJiraRecords(records []models.CSVRecord, format string) error {
+	switch format {
+	case OutputFormatCSV, "":
+		return p.WriteJiraCSV(records)
+	case OutputFormatJSON, OutputFormatNDJSON:
+		return p.WriteJiraJSON(records, format == OutputFormatNDJSON)
+	default:
+		return fmt.Errorf("出力形式が不正です: %s（%s のいずれかを指定してください）", format, strings.Join(OutputFormats, "/"))
 	}
+}
 
-	reader := csv.NewReader(file)
-	records, err := reader.ReadAll()
-	file.Close() // 早めに閉じる
+// WriteJiraJSON は変換結果をJSON配列、ndjson が true の場合は1行1レコードのJSONとして書き出します
+// キーはJIRA CSVのカラム名です
+func (p *CSVProcessor) WriteJiraJSON(records []models.CSVRecord, ndjson bool) error {
+	utils.LogInfo("JSONファイル '%s' を作成します", p.config.JiraCSV)
 
+	if len(records) == 0 {
+		return fmt.Errorf("書き込むデータがありません")
+	}
+
+	file, err := os.Create(p.config.JiraCSV)
 	if err != nil {
-		return fmt.Errorf("CSV読み込みエラー: %w", err)
+		return fmt.Errorf("JSONファイル作成エラー: %w", err)
 	}
+	defer file.Close()
 
-	if len(records) < 2 {
-		return fmt.Errorf("更新するデータが不足しています")
+	if ndjson {
+		encoder := json.NewEncoder(file)
+		for _, record := range records {
+			if err := encoder.Encode(record); err != nil {
+				return fmt.Errorf("行書き込みエラー: %w", err)
+			}
+		}
+	} else {
+		data, err := json.MarshalIndent(records, "", "  ")
+		if err != nil {
+			return fmt.Errorf("JSONエンコードエラー: %w", err)
+		}
+		if _, err := file.Write(append(data, '\n')); err != nil {
+			return fmt.Errorf("JSON書き込みエラー: %w", err)
+		}
 	}
 
-	// ヘッダーとカラムインデックスを取得
-	headers := records[0]
-	var idIndex, keyIndex int = -1, -1
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("JSON書き込み完了エラー: %w", err)
+	}
 
-	for i, header := range headers {
-		if header == "JIRA Issue ID" {
-			idIndex = i
-		} else if header == "JIRA Issue Key" {
-			keyIndex = i
+	utils.LogInfo("JSON書き込み完了: %d 行", len(records))
+	return nil
+}
+
+// DefaultJiraHeaders はJIRA CSVに出力する既定のカラムと順序です
+var DefaultJiraHeaders = []string{
+	"JIRA Issue ID", "Title", "Description", "Labels", "Type",
+	"JIRA Status", "Pivotal State", "Story Points", "Created Date", "Resolved Date",
+	"Assignee", "Reporter", "Reviewers", "Comment", "Tasks", "JIRA Issue Key",
+}
+
+// jiraHeaders は出力するカラムの一覧を返します
+// JIRA_CSV_HEADERS（未設定時は既定の順序）のカラムを先頭に、レコードにのみ存在するカラムを名前順で後ろに追加します
+func (p *CSVProcessor) jiraHeaders(records []models.CSVRecord) []string {
+	ordered := p.config.JiraCSVHeaders
+	if len(ordered) == 0 {
+		ordered = DefaultJiraHeaders
+	}
+
+	headers := slices.Clone(ordered)
+	seen := make(map[string]bool, len(headers))
+	for _, header := range headers {
+		seen[header] = true
+	}
+
+	var extra []string
+	for _, record := range records {
+		for key := range record {
+			if !seen[key] {
+				seen[key] = true
+				extra = append(extra, key)
+			}
 		}
 	}
+	sort.Strings(extra)
+
+	return append(headers, extra...)
+}
+
+// LoadIssueMapping はCSVからPivotal ID → JIRA Key のマッピングを読み込みます
+func (p *CSVProcessor) LoadIssueMapping() (models.IssueMapping, error) {
+	utils.LogInfo("イシューマッピングを読み込んでいます...")
+
+	table, err := p.ReadCSVTable(p.config.JiraCSV)
+	if err != nil {
+		return nil, fmt.Errorf("マッピングCSV読み込みエラー: %w", err)
+	}
+
+	mapping, err := table.IssueMapping()
+	if err != nil {
+		return nil, err
+	}
 
+	utils.LogInfo("イシューマッピングをロードしました: %d 件", len(mapping))
+	return mapping, nil
+}
+
+// IssueMapping はPivotal ID → JIRA Key のマッピングを返します（作成に失敗した ERROR の行は含めません）
+func (t *CSVTable) IssueMapping() (models.IssueMapping, error) {
+	idIndex := slices.Index(t.Headers, "JIRA Issue ID")
+	keyIndex := slices.Index(t.Headers, "JIRA Issue Key")
 	if idIndex == -1 || keyIndex == -1 {
-		return fmt.Errorf("必要なカラムが見つかりません")
+		return nil, fmt.Errorf("マッピングに必要なカラムが見つかりません")
 	}
 
-	// マッピングを適用
-	updated := 0
-	for i, record := range records[1:] {
-		if len(record) <= max(idIndex, keyIndex) {
+	mapping := make(models.IssueMapping)
+	for _, row := range t.Rows {
+		if len(row) <= max(idIndex, keyIndex) {
 			continue
 		}
 
-		pivotalID := record[idIndex]
-		if jiraKey, ok := mapping[pivotalID]; ok {
-			records[i+1][keyIndex] = jiraKey
-			updated++
+		pivotalID := row[idIndex]
+		jiraKey := row[keyIndex]
+		if pivotalID != "" && jiraKey != "" && jiraKey != "ERROR" {
+			mapping[pivotalID] = jiraKey
 		}
 	}
+	return mapping, nil
+}
 
-	// 更新したCSVを書き込む
-	outFile, err := os.Create(p.config.JiraCSV)
+// UpdateJiraKeys はCSVファイルのJIRAキーを更新します
+// Errorカラムが既に存在する場合はそのまま保持します
+func (p *CSVProcessor) UpdateJiraKeys(mapping models.IssueMapping) error {
+	utils.LogInfo("JIRAキーをCSVファイルに更新しています...")
+
+	table, err := p.ReadCSVTable(p.config.JiraCSV)
 	if err != nil {
-		return fmt.Errorf("CSVファイル作成エラー: %w", err)
+		return err
 	}
-	defer outFile.Close()
-
-	writer := csv.NewWriter(outFile)
-	if err := writer.WriteAll(records); err != nil {
-		return fmt.Errorf("CSV書き込みエラー: %w", err)
+	updated, total, err := p.updateJiraKeys(table, mapping, nil)
+	if err != nil {
+		return err
 	}
 
-	utils.LogInfo("JIRAキーの更新完了: %d/%d 件を更新しました", updated, len(records)-1)
+	utils.LogInfo("JIRAキーの更新完了: %d/%d 件を更新しました", updated, total)
 	return nil
 }
 
 // UpdateJiraKeysWithErrorFlags はCSVファイルのJIRAキーとエラーフラグを更新します
 func (p *CSVProcessor) UpdateJiraKeysWithErrorFlags(mapping models.IssueMapping, errorFlags map[string]bool) error {
-	utils.LogInfo("JIRAキーとエラーフラグをCSVファイルに更新しています...")
-
-	// CSVを読み込む
-	file, err := os.Open(p.config.JiraCSV)
+	table, err := p.ReadCSVTable(p.config.JiraCSV)
 	if err != nil {
-		return fmt.Errorf("CSVオープンエラー: %w", err)
+		return err
 	}
+	return p.UpdateTableJiraKeys(table, mapping, errorFlags)
+}
 
-	reader := csv.NewReader(file)
-	records, err := reader.ReadAll()
-	file.Close() // 早めに閉じる
+// UpdateTableJiraKeys は読み込み済みのCSVのJIRAキーとエラーフラグを更新し、JIRA_CSV に書き出します
+// ファイルを読み直さないため、インポート中に保持している内容をそのまま使えます
+func (p *CSVProcessor) UpdateTableJiraKeys(table *CSVTable, mapping models.IssueMapping, errorFlags map[string]bool) error {
+	utils.LogInfo("JIRAキーとエラーフラグをCSVファイルに更新しています...")
 
-	if err != nil {
-		return fmt.Errorf("CSV読み込みエラー: %w", err)
+	if errorFlags == nil {
+		errorFlags = make(map[string]bool)
 	}
 
-	if len(records) < 2 {
-		return fmt.Errorf("更新するデータが不足しています")
+	updated, total, err := p.updateJiraKeys(table, mapping, errorFlags)
+	if err != nil {
+		return err
 	}
 
-	// ヘッダーの確認と拡張
-	headers := records[0]
-	var idIndex, keyIndex, errorIndex int = -1, -1, -1
+	utils.LogInfo("JIRAキーとエラーフラグの更新完了: %d/%d 件を更新しました", updated, total)
+	return nil
+}
 
-	for i, header := range headers {
-		if header == "JIRA Issue ID" {
-			idIndex = i
-		} else if header == "JIRA Issue Key" {
-			keyIndex = i
-		} else if header == "Error" {
-			errorIndex = i
-		}
-	}
+// updateJiraKeys はCSVのJIRAキーを更新してファイルに書き出し、更新件数と全件数を返します
+// errorFlags が nil の場合はErrorカラムを変更せず、存在すれば保持します
+// errorFlags が指定された場合はErrorカラムがなければ追加してフラグを書き込みます
+func (p *CSVProcessor) updateJiraKeys(table *CSVTable, mapping models.IssueMapping, errorFlags map[string]bool) (int, int, error) {
+	// カラムインデックスを取得
+	idIndex := slices.Index(table.Headers, "JIRA Issue ID")
+	keyIndex := slices.Index(table.Headers, "JIRA Issue Key")
+	errorIndex := slices.Index(table.Headers, "Error")
 
 	if idIndex == -1 || keyIndex == -1 {
-		return fmt.Errorf("必要なカラムが見つかりません")
+		return 0, 0, fmt.Errorf("必要なカラムが見つかりません")
 	}
 
-	// Errorカラムがなければ追加
-	if errorIndex == -1 {
-		headers = append(headers, "Error")
-		errorIndex = len(headers) - 1
+	// 列数の異なる行があっても、キーとフラグが必ず対応するカラムに入るよう列数をヘッダーに揃える
+	// Errorカラムを追加する前に揃えるため、名前のない超過列がErrorカラムとして扱われることはない
+	table.normalizeRows()
 
-		// 各行にも空のエラーフィールドを追加
-		for i := range records[1:] {
-			records[i+1] = append(records[i+1], "")
-		}
+	// エラーフラグを書き込む場合のみErrorカラムを追加
+	if errorFlags != nil && errorIndex == -1 {
+		table.Headers = append(table.Headers, "Error")
+		errorIndex = len(table.Headers) - 1
+		table.normalizeRows()
 	}
 
 	// マッピングを適用
 	updated := 0
-	for i, record := range records[1:] {
-		if len(record) <= max(idIndex, keyIndex) {
+	for _, row := range table.Rows {
+		pivotalID := row[idIndex]
+		if pivotalID == "" {
 			continue
 		}
 
-		pivotalID := record[idIndex]
-
 		// JIRAキーの更新
 		if jiraKey, ok := mapping[pivotalID]; ok {
-			records[i+1][keyIndex] = jiraKey
+			row[keyIndex] = jiraKey
 			updated++
 
 			// エラーフラグの更新
-			if errorFlag, ok := errorFlags[pivotalID]; ok && errorFlag {
-				records[i+1][errorIndex] = "1" // エラーあり
-			} else {
-				records[i+1][errorIndex] = "0" // エラーなし
+			if errorFlags != nil {
+				if errorFlags[pivotalID] {
+					row[errorIndex] = "1" // エラーあり
+				} else {
+					row[errorIndex] = "0" // エラーなし
+				}
 			}
 		}
 	}
@@ -448,19 +1001,62 @@ func (p *CSVProcessor) UpdateJiraKeysWithErrorFlags(mapping models.IssueMapping,
 	// 更新したCSVを書き込む
 	outFile, err := os.Create(p.config.JiraCSV)
 	if err != nil {
-		return fmt.Errorf("CSVファイル作成エラー: %w", err)
+		return 0, 0, fmt.Errorf("CSVファイル作成エラー: %w", err)
 	}
 	defer outFile.Close()
 
 	writer := csv.NewWriter(outFile)
-	if err := writer.WriteAll(records); err != nil {
-		return fmt.Errorf("CSV書き込みエラー: %w", err)
+	if err := writer.Write(table.Headers); err != nil {
+		return 0, 0, fmt.Errorf("CSV書き込みエラー: %w", err)
+	}
+	if err := writer.WriteAll(table.Rows); err != nil {
+		return 0, 0, fmt.Errorf("CSV書き込みエラー: %w", err)
+	}
+
+	return updated, len(table.Rows), nil
+}
+
+// WriteMappingJSON はPivotal ID → JIRA Key のマッピングをJSONファイルに書き出します
+// 作成に失敗したイシュー（ERROR）は含めません
+func (p *CSVProcessor) WriteMappingJSON(mapping models.IssueMapping) error {
+	if p.config.MappingJSON == "" {
+		return nil
+	}
+
+	output := make(map[string]string, len(mapping))
+	for pivotalID, jiraKey := range mapping {
+		if pivotalID != "" && jiraKey != "" && jiraKey != "ERROR" {
+			output[pivotalID] = jiraKey
+		}
 	}
 
-	utils.LogInfo("JIRAキーとエラーフラグの更新完了: %d/%d 件を更新しました", updated, len(records)-1)
+	data, err := json.MarshalIndent(output, "", "  ")
+	if err != nil {
+		return fmt.Errorf("JSONエンコードエラー: %w", err)
+	}
+
+	if err := os.WriteFile(p.config.MappingJSON, data, 0644); err != nil {
+		return fmt.Errorf("マッピングJSON書き込みエラー: %w", err)
+	}
+
+	utils.LogInfo("イシューマッピングをJSONに書き出しました: %s (%d 件)", p.config.MappingJSON, len(output))
 	return nil
 }
 
+// joinNonEmpty は空でない値だけを区切りで結合します
+func joinNonEmpty(separator string, values ...string) string {
+	var nonEmpty []string
+	for _, value := range values {
+		if strings.TrimSpace(value) != "" {
+			nonEmpty = append(nonEmpty, value)
+		}
+	}
+	return strings.Join(nonEmpty, separator)
+}
+
+// jiraDateTimeFormat はJIRA CSVの日時の形式です
+const jiraDateTimeFormat = "2006-01-02T15:04:05.000-0700"
+
 // 日付文字列を変換
 func (p *CSVProcessor) convertDateFormat(dateStr string) string {
 	if dateStr == "" {
@@ -477,7 +1073,7 @@ func (p *CSVProcessor) convertDateFormat(dateStr string) string {
 	for _, format := range formats {
 		t, err := time.Parse(format, dateStr)
 		if err == nil {
-			return t.Format("2006-01-02T15:04:05.000+0000")
+			return t.Format(jiraDateTimeFormat)
 		}
 	}
 
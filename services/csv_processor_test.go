@@ -0,0 +1,301 @@
+package services
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"pivotaltojira/models"
+	"pivotaltojira/utils"
+)
+
+// writeRawCSV は内容をそのままCSVファイルに書き込みます（列数の異なる行のテスト用）
+func writeRawCSV(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestUpdateJiraKeysWithErrorFlagsRaggedRows(t *testing.T) {
+	cfg := newTestConfig(t, "https://jira.example.test", nil)
+	p := NewCSVProcessor(cfg)
+
+	// 2行目はヘッダーより短く、3行目はヘッダーより長い
+	writeRawCSV(t, cfg.JiraCSV, "JIRA Issue ID,Title,JIRA Issue Key,Labels\n"+
+		"100,短い行\n"+
+		"200,長い行,,frontend,extra1,extra2\n"+
+		"300,通常の行,,backend\n")
+
+	mapping := models.IssueMapping{"100": "TEST-1", "200": "TEST-2", "300": "ERROR"}
+	errorFlags := map[string]bool{"300": true}
+	if err := p.UpdateJiraKeysWithErrorFlags(mapping, errorFlags); err != nil {
+		t.Fatalf("UpdateJiraKeysWithErrorFlags がエラーを返しました: %v", err)
+	}
+
+	table, err := p.ReadCSVTable(cfg.JiraCSV)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantHeaders := []string{"JIRA Issue ID", "Title", "JIRA Issue Key", "Labels", "Error"}
+	if !slices.Equal(table.Headers, wantHeaders) {
+		t.Fatalf("ヘッダー = %q, want %q", table.Headers, wantHeaders)
+	}
+
+	want := [][]string{
+		{"100", "短い行", "TEST-1", "", "0"},
+		{"200", "長い行", "TEST-2", "frontend", "0"},
+		{"300", "通常の行", "ERROR", "backend", "1"},
+	}
+	for i, row := range table.Rows {
+		if !slices.Equal(row, want[i]) {
+			t.Errorf("行 %d = %q, want %q", i+2, row, want[i])
+		}
+	}
+}
+
+func TestConvertRecordStoryPoints(t *testing.T) {
+	p := NewCSVProcessor(newTestConfig(t, "https://jira.example.test", nil))
+
+	tests := []struct {
+		estimate string
+		want     string
+	}{
+		{"0.5", "0.5"},
+		{"1.50", "1.5"},
+		{"3", "3"},
+		{"", ""},
+		{"  ", ""},
+	}
+	for _, tt := range tests {
+		record := models.CSVRecord{"Id": "100", "Title": "見積もり", "Type": "feature", "Estimate": tt.estimate}
+		if got := p.convertRecord(record)["Story Points"]; got != tt.want {
+			t.Errorf("Estimate %q の Story Points = %q, want %q", tt.estimate, got, tt.want)
+		}
+	}
+}
+
+func TestParseStoryPoints(t *testing.T) {
+	tests := []struct {
+		value  string
+		want   float64
+		wantOK bool
+	}{
+		{"0.5", 0.5, true},
+		{"3", 3, true},
+		{" 8 ", 8, true},
+		{"0", 0, true},
+		{"", 0, false},
+		{"-1", 0, false},
+		{"?", 0, false},
+		{"Unestimated", 0, false},
+		{"-2", 0, false},
+		{"abc", 0, false},
+		{"NaN", 0, false},
+	}
+	for _, tt := range tests {
+		got, ok := parseStoryPoints(tt.value)
+		if got != tt.want || ok != tt.wantOK {
+			t.Errorf("parseStoryPoints(%q) = %v, %v, want %v, %v", tt.value, got, ok, tt.want, tt.wantOK)
+		}
+	}
+}
+
+func TestUpdateJiraKeysKeepsErrorColumn(t *testing.T) {
+	cfg := newTestConfig(t, "https://jira.example.test", nil)
+	p := NewCSVProcessor(cfg)
+	writeRawCSV(t, cfg.JiraCSV, "JIRA Issue ID,Title,JIRA Issue Key\n"+
+		"100,成功する行,\n"+
+		"200,失敗する行,\n")
+
+	// 1回目: エラーフラグ付きで更新してErrorカラムを追加する
+	if err := p.UpdateJiraKeysWithErrorFlags(models.IssueMapping{"100": "TEST-1", "200": "ERROR"}, map[string]bool{"200": true}); err != nil {
+		t.Fatalf("UpdateJiraKeysWithErrorFlags がエラーを返しました: %v", err)
+	}
+
+	// 2回目: エラーフラグなし（nil）で更新してもErrorカラムと値は残る
+	if err := p.UpdateJiraKeys(models.IssueMapping{"200": "TEST-2"}); err != nil {
+		t.Fatalf("UpdateJiraKeys がエラーを返しました: %v", err)
+	}
+
+	table, err := p.ReadCSVTable(cfg.JiraCSV)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"JIRA Issue ID", "Title", "JIRA Issue Key", "Error"}; !slices.Equal(table.Headers, want) {
+		t.Fatalf("ヘッダー = %q, want %q", table.Headers, want)
+	}
+	want := [][]string{
+		{"100", "成功する行", "TEST-1", "0"},
+		{"200", "失敗する行", "TEST-2", "1"},
+	}
+	for i, row := range table.Rows {
+		if !slices.Equal(row, want[i]) {
+			t.Errorf("行 %d = %q, want %q", i+2, row, want[i])
+		}
+	}
+}
+
+// readPivotalFixture は testdata のPivotal CSVを読み込み、JIRA用のレコードに変換します
+func readPivotalFixture(t *testing.T, name string, env map[string]string) []models.CSVRecord {
+	t.Helper()
+
+	env = maps.Clone(env)
+	if env == nil {
+		env = make(map[string]string)
+	}
+	env["PIVOTAL_CSV"] = filepath.Join("testdata", name)
+	p := NewCSVProcessor(newTestConfig(t, "https://jira.example.test", env))
+
+	records, err := p.ReadPivotalCSV()
+	if err != nil {
+		t.Fatalf("ReadPivotalCSV がエラーを返しました: %v", err)
+	}
+	converted, err := p.ProcessPivotalToJiraCSV(records)
+	if err != nil {
+		t.Fatalf("ProcessPivotalToJiraCSV がエラーを返しました: %v", err)
+	}
+	return converted
+}
+
+func TestReadPivotalCSVMergesLabelColumns(t *testing.T) {
+	records := readPivotalFixture(t, "pivotal_multi_label.csv", nil)
+
+	tests := []struct {
+		labels   string
+		assignee string
+	}{
+		{"frontend, auth, needs review", "alice, bob"},
+		{"backend", "dave"},
+		{"", ""},
+	}
+	if len(records) != len(tests) {
+		t.Fatalf("レコード数 = %d, want %d", len(records), len(tests))
+	}
+	for i, tt := range tests {
+		if got := records[i]["Labels"]; got != tt.labels {
+			t.Errorf("行 %d の Labels = %q, want %q", i+2, got, tt.labels)
+		}
+		if got := records[i]["Assignee"]; got != tt.assignee {
+			t.Errorf("行 %d の Assignee = %q, want %q", i+2, got, tt.assignee)
+		}
+	}
+
+	// 結合したラベルはそれぞれJIRAのラベルになる
+	if got, want := parseLabels(records[0]["Labels"]), []string{"frontend", "auth", "needs_review"}; !slices.Equal(got, want) {
+		t.Errorf("parseLabels = %q, want %q", got, want)
+	}
+}
+
+func TestReadPivotalCSVHeaderAliases(t *testing.T) {
+	current := readPivotalFixture(t, "pivotal_export_current.csv", nil)
+
+	// 別名のヘッダーを使った形式は、どの別名を使ったかをログに出力する
+	var logs bytes.Buffer
+	saved := utils.InfoLogger
+	utils.InfoLogger = log.New(&logs, "", 0)
+	t.Cleanup(func() { utils.InfoLogger = saved })
+	legacy := readPivotalFixture(t, "pivotal_export_legacy.csv", nil)
+	utils.InfoLogger = saved
+
+	for _, alias := range []string{"'Story ID' を 'Id'", "'Name' を 'Title'", "'Label' を 'Labels'",
+		"'Story Type' を 'Type'", "'Points' を 'Estimate'", "'State' を 'Current State'"} {
+		if !strings.Contains(logs.String(), alias) {
+			t.Errorf("ログに %q がありません:\n%s", alias, logs.String())
+		}
+	}
+
+	if len(current) != 3 || len(legacy) != len(current) {
+		t.Fatalf("レコード数 = %d, %d, want 3", len(current), len(legacy))
+	}
+	for i := range current {
+		if !maps.Equal(legacy[i], current[i]) {
+			t.Errorf("行 %d の変換結果が異なります:\n別名のヘッダー: %v\n本来のヘッダー: %v", i+2, legacy[i], current[i])
+		}
+	}
+
+	want := models.CSVRecord{
+		"JIRA Issue ID": "100",
+		"Title":         "ログイン画面",
+		"Labels":        "frontend, auth",
+		"Type":          "feature",
+		"JIRA Status":   "進行中",
+		"Story Points":  "2",
+	}
+	for field, value := range want {
+		if got := legacy[0][field]; got != value {
+			t.Errorf("%s = %q, want %q", field, got, value)
+		}
+	}
+}
+
+func TestMergeColumnsStrategies(t *testing.T) {
+	headers := []string{"Id", "Owned By", "Owned By", "Owned By", "Comment", "Comment"}
+	row := []string{"100", "alice", "", "bob", "最初のコメント", "次のコメント"}
+
+	tests := []struct {
+		mergeColumns string
+		wantOwners   string
+		wantComment  string
+	}{
+		{"Comment,Owned By", "alice, bob", "最初のコメント" + commentSeparator + "次のコメント"},
+		{"Comment,Owned By:comma", "alice, bob", "最初のコメント" + commentSeparator + "次のコメント"},
+		{"Owned By:newline,Comment:space", "alice\nbob", "最初のコメント 次のコメント"},
+		{"Owned By:space,Comment:newline", "alice bob", "最初のコメント\n次のコメント"},
+		{"Owned By:comment", "alice" + commentSeparator + "bob", "最初のコメント"}, // Comment は最初の列のみ
+		{"Comment", "alice", "最初のコメント" + commentSeparator + "次のコメント"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.mergeColumns, func(t *testing.T) {
+			p := NewCSVProcessor(newTestConfig(t, "https://jira.example.test", map[string]string{
+				"MERGE_COLUMNS": tt.mergeColumns,
+				"COMMENT_ORDER": "source",
+			}))
+			record, err := p.newPivotalRowParser(headers).parse(row, 2)
+			if err != nil {
+				t.Fatalf("parse がエラーを返しました: %v", err)
+			}
+			if got := record["Owned By"]; got != tt.wantOwners {
+				t.Errorf("Owned By = %q, want %q", got, tt.wantOwners)
+			}
+			if got := record["Comment"]; got != tt.wantComment {
+				t.Errorf("Comment = %q, want %q", got, tt.wantComment)
+			}
+		})
+	}
+}
+
+// BenchmarkProcessPivotalToJiraCSV は1000行のPivotal CSVの変換にかかる時間を計測します
+func BenchmarkProcessPivotalToJiraCSV(b *testing.B) {
+	utils.SetLogLevel("error")
+	b.Cleanup(func() { utils.SetLogLevel("info") })
+
+	cfg := newTestConfig(b, "https://jira.example.test", nil)
+	var content strings.Builder
+	content.WriteString("Id,Title,Type,Current State,Estimate,Labels,Owned By,Requested By,Created at,Comment\n")
+	for i := range 1000 {
+		fmt.Fprintf(&content, "%d,ストーリー%d,feature,started,3,\"frontend, auth\",alice,carol,\"Jan 2, 2024\",\"コメント (carol - Jan 3, 2024)\"\n", 1000+i, i)
+	}
+	if err := os.WriteFile(cfg.PivotalCSV, []byte(content.String()), 0644); err != nil {
+		b.Fatal(err)
+	}
+
+	p := NewCSVProcessor(cfg)
+	records, err := p.ReadPivotalCSV()
+	if err != nil {
+		b.Fatalf("ReadPivotalCSV がエラーを返しました: %v", err)
+	}
+
+	b.ResetTimer()
+	for range b.N {
+		if _, err := p.ProcessPivotalToJiraCSV(records); err != nil {
+			b.Fatalf("ProcessPivotalToJiraCSV がエラーを返しました: %v", err)
+		}
+	}
+}
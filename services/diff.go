@@ -0,0 +1,160 @@
+package services
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"pivotaltojira/api"
+	"pivotaltojira/models"
+	"pivotaltojira/utils"
+)
+
+// DiffIssues は既存のJIRAイシューとCSVの内容を比較し、再インポートした場合の差分を報告します
+// JIRAへの書き込みは一切行いません
+func (m *MigrationService) DiffIssues() ([]models.IssueDiff, error) {
+	startTime := time.Now()
+	defer utils.TrackTime(startTime, "差分確認")
+
+	records, err := m.csvProc.ReadCSV(m.config.JiraCSV)
+	if err != nil {
+		return nil, fmt.Errorf("JIRA CSV読み込みエラー: %w", err)
+	}
+
+	utils.LogInfo("既存イシューとの差分を確認します: %d 件", len(records))
+
+	diffs := make([]*models.IssueDiff, len(records))
+	semaphore := make(chan struct{}, m.config.MaxConcurrent)
+	var wg sync.WaitGroup
+
+	for i, record := range records {
+		wg.Add(1)
+		semaphore <- struct{}{}
+
+		go func(idx int, rec models.CSVRecord) {
+			defer wg.Done()
+			defer func() { <-semaphore }()
+
+			diff, err := m.diffRecord(rec)
+			if err != nil {
+				utils.LogWarn("行 %d の差分確認に失敗: %v", idx+1, err)
+				return
+			}
+			diffs[idx] = diff
+		}(i, record)
+	}
+
+	wg.Wait()
+	close(semaphore)
+
+	// 元の行順で結果を出力
+	var result []models.IssueDiff
+	notFound := 0
+	for _, diff := range diffs {
+		if diff == nil {
+			continue
+		}
+		if diff.IssueKey == "" {
+			notFound++
+			utils.LogInfo("Pivotal ID %s: 既存イシューなし（新規作成されます）", diff.PivotalID)
+			continue
+		}
+		if len(diff.Fields) == 0 {
+			continue
+		}
+
+		result = append(result, *diff)
+		utils.LogInfo("%s (Pivotal ID: %s): %d 項目が変更されます", diff.IssueKey, diff.PivotalID, len(diff.Fields))
+		for _, f := range diff.Fields {
+			utils.LogInfo("  %s: %q → %q", f.Field, f.Current, f.Expected)
+		}
+	}
+
+	utils.LogInfo("差分確認が完了しました: 変更あり=%d, 新規=%d, 全体=%d", len(result), notFound, len(records))
+	return result, nil
+}
+
+// diffRecord は1つのレコードと既存イシューを比較します
+func (m *MigrationService) diffRecord(record models.CSVRecord) (*models.IssueDiff, error) {
+	pivotalID := record["JIRA Issue ID"]
+	diff := &models.IssueDiff{PivotalID: pivotalID}
+
+	existing, err := m.findExistingIssue(record)
+	if err != nil {
+		return nil, err
+	}
+	if existing == nil {
+		return diff, nil
+	}
+	diff.IssueKey = existing.Key
+
+	// 作成時と同じく、長すぎるサマリーは切り詰めて元のタイトルを説明文の先頭に残した値と比較する
+	title, _ := issueTitle(record)
+	expectedSummary, expectedDescription, _ := api.NormalizeSummary(buildSummary(m.config.SummaryPrefixFormat, pivotalID, title), m.buildDescription(record))
+	if existing.Title != expectedSummary {
+		diff.Fields = append(diff.Fields, models.FieldDiff{Field: "summary", Current: existing.Title, Expected: expectedSummary})
+	}
+
+	expectedDescription = strings.TrimSpace(expectedDescription)
+	if strings.TrimSpace(m.stripMigrationFooter(existing.Description)) != expectedDescription {
+		diff.Fields = append(diff.Fields, models.FieldDiff{Field: "description", Current: existing.Description, Expected: expectedDescription})
+	}
+
+	if status := targetStatus(m.config, record); m.config.NeedsTransition(status) && !strings.EqualFold(existing.Status, status) {
+		diff.Fields = append(diff.Fields, models.FieldDiff{Field: "status", Current: existing.Status, Expected: status})
+	}
+
+	// 検索用ラベルは比較対象から除く
+	idLabel := m.pivotalIDLabel(pivotalID)
+	currentLabels := slices.DeleteFunc(slices.Clone(existing.Labels), func(l string) bool {
+		return l == idLabel
+	})
+	expectedLabels := m.withGlobalLabels(parseLabels(record["Labels"]))
+	slices.Sort(currentLabels)
+	slices.Sort(expectedLabels)
+	if !slices.Equal(currentLabels, expectedLabels) {
+		diff.Fields = append(diff.Fields, models.FieldDiff{
+			Field:    "labels",
+			Current:  strings.Join(currentLabels, ","),
+			Expected: strings.Join(expectedLabels, ","),
+		})
+	}
+
+	return diff, nil
+}
+
+// findExistingIssue はCSVのJIRAキー、なければPivotal IDのラベル（PIVOTAL_ID_LABEL_PREFIX）で既存イシューを探します
+func (m *MigrationService) findExistingIssue(record models.CSVRecord) (*models.JiraIssue, error) {
+	if issueKey := record["JIRA Issue Key"]; issueKey != "" && issueKey != "ERROR" {
+		issue, err := m.jiraClient.GetIssue(issueKey)
+		// 削除済みのイシューは未作成として扱う
+		if api.StatusCode(err) == http.StatusNotFound {
+			return nil, nil
+		}
+		return issue, err
+	}
+
+	// ラベルが無効な場合は検索できない
+	idLabel := m.pivotalIDLabel(record["JIRA Issue ID"])
+	if idLabel == "" {
+		return nil, nil
+	}
+
+	jql := fmt.Sprintf(`project = "%s" AND labels = "%s"`, m.config.JiraProjectKey, idLabel)
+	issues, err := m.jiraClient.SearchIssues(jql, 2)
+	if err != nil {
+		return nil, err
+	}
+
+	switch len(issues) {
+	case 0:
+		return nil, nil
+	case 1:
+		return issues[0], nil
+	default:
+		return nil, fmt.Errorf("Pivotal ID %s に対応するイシューが複数あります", record["JIRA Issue ID"])
+	}
+}
@@ -0,0 +1,44 @@
+package services
+
+import (
+	"strings"
+	"testing"
+
+	"pivotaltojira/models"
+)
+
+func TestDiffLongSummaryMatchesImportedIssue(t *testing.T) {
+	fake := newFakeJira()
+	m := newTestService(t, fake, map[string]string{"UPDATE_EXISTING": "true"})
+	record := jiraRecord("100", strings.Repeat("長いタイトル", 50), "feature", "")
+	record["Description"] = "説明"
+	writeJiraCSV(t, m, []models.CSVRecord{record})
+
+	assertNoDiff := func(when string) {
+		t.Helper()
+		record["JIRA Issue Key"] = "TEST-1"
+		diff, err := m.diffRecord(record)
+		if err != nil {
+			t.Fatalf("%s: diffRecord がエラーを返しました: %v", when, err)
+		}
+		if diff.IssueKey != "TEST-1" || len(diff.Fields) != 0 {
+			t.Errorf("%s: 差分 = %+v, want TEST-1 の差分なし", when, diff)
+		}
+	}
+
+	// 作成時に切り詰めたサマリーと、説明文の先頭の元のタイトルを差分として扱わない
+	if _, err := m.ImportIssuesWithResult(); err != nil {
+		t.Fatalf("ImportIssuesWithResult がエラーを返しました: %v", err)
+	}
+	assertNoDiff("作成後")
+
+	// 既存イシューの更新でも作成時と同じサマリーと説明文になる
+	m = restartService(m)
+	if _, err := m.ImportIssuesWithResult(); err != nil {
+		t.Fatalf("ImportIssuesWithResult がエラーを返しました: %v", err)
+	}
+	if n := fake.countRequests("PUT /issue/TEST-1"); n != 1 {
+		t.Fatalf("更新のリクエスト数 = %d, want 1", n)
+	}
+	assertNoDiff("更新後")
+}
@@ -0,0 +1,112 @@
+package services_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"pivotaltojira/api"
+	"pivotaltojira/config"
+	"pivotaltojira/models"
+	"pivotaltojira/services"
+	"pivotaltojira/utils"
+)
+
+// printingReporter は LogProgressReporter を埋め込み、イシューのイベントだけを出力する ProgressReporter です
+type printingReporter struct {
+	services.LogProgressReporter
+	mu sync.Mutex
+}
+
+func (r *printingReporter) OnIssueCreated(row models.RowResult) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	fmt.Printf("作成: Pivotal ID %s → %s\n", row.PivotalID, row.IssueKey)
+}
+
+func (r *printingReporter) OnIssueFailed(row models.RowResult, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	fmt.Printf("失敗: Pivotal ID %s\n", row.PivotalID)
+}
+
+// 独自のGoプログラムからイシューのインポートを実行し、行ごとの結果を受け取る例です
+// JIRAの代わりに、イシューの作成だけに応答するテスト用のサーバーに接続します
+func Example_embedded() {
+	var mu sync.Mutex
+	created := 0
+	jira := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch req.URL.Path {
+		case "/rest/api/2/project/TEST":
+			json.NewEncoder(w).Encode(map[string]string{"key": "TEST"})
+		case "/rest/api/2/issue":
+			mu.Lock()
+			created++
+			key := fmt.Sprintf("TEST-%d", created)
+			mu.Unlock()
+			w.WriteHeader(http.StatusCreated)
+			json.NewEncoder(w).Encode(map[string]string{"key": key})
+		default:
+			http.NotFound(w, req)
+		}
+	}))
+	defer jira.Close()
+
+	dir, _ := os.MkdirTemp("", "example")
+	defer os.RemoveAll(dir)
+
+	// 設定は環境変数から読み込んだ後、必要な項目だけ上書きする
+	for key, value := range map[string]string{
+		"JIRA_URL":         jira.URL,
+		"JIRA_EMAIL":       "tester@example.com",
+		"JIRA_API_TOKEN":   "test-api-token",
+		"JIRA_PROJECT_KEY": "TEST",
+	} {
+		os.Setenv(key, value)
+		defer os.Unsetenv(key)
+	}
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	cfg.JiraCSV = filepath.Join(dir, "jira.csv")
+	cfg.MappingJSON = filepath.Join(dir, "id_mapping.json")
+	cfg.ImportJournal = ""
+	cfg.MaxConcurrent = 1
+
+	// ログの代わりに戻り値と ProgressReporter で結果を受け取る
+	utils.SetLogLevel("error")
+	defer utils.SetLogLevel("info")
+
+	csvProc := services.NewCSVProcessor(cfg)
+	csvProc.WriteJiraCSV([]models.CSVRecord{
+		{"JIRA Issue ID": "100", "Title": "ログイン画面", "Type": "feature", "JIRA Status": "Backlog"},
+		{"JIRA Issue ID": "200", "Title": "パスワードの再設定", "Type": "bug", "JIRA Status": "Backlog"},
+	})
+
+	migration := services.NewMigrationService(cfg, api.NewJiraClient(cfg), csvProc)
+	migration.SetProgressReporter(&printingReporter{})
+
+	result, err := migration.ImportIssuesWithResult()
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	fmt.Printf("成功 %d 件, 失敗 %d 件\n", result.Succeeded, result.Failed)
+	for _, row := range result.Rows {
+		fmt.Printf("行 %d: %s\n", row.Row, row.IssueKey)
+	}
+
+	// Output:
+	// 作成: Pivotal ID 100 → TEST-1
+	// 作成: Pivotal ID 200 → TEST-2
+	// 成功 2 件, 失敗 0 件
+	// 行 1: TEST-1
+	// 行 2: TEST-2
+}
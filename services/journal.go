@@ -0,0 +1,56 @@
+package services
+
+import (
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+// importJournal はインポート中に作成したイシューを1件ずつ追記するジャーナルです
+// CSVへの書き込み前にプロセスが終了しても、作成済みのイシューを後から特定できるようにします
+// 形式: <RFC3339形式の作成日時>\t<Pivotal ID>\t<JIRAキー>
+type importJournal struct {
+	file  *os.File
+	mutex sync.Mutex
+}
+
+// openImportJournal はジャーナルファイルを追記モードで開きます（パスが空の場合は nil を返します）
+func openImportJournal(path string) (*importJournal, error) {
+	if path == "" {
+		return nil, nil
+	}
+
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("ジャーナルファイルオープンエラー: %w", err)
+	}
+	return &importJournal{file: file}, nil
+}
+
+// Record は作成したイシューを追記し、クラッシュしても失われないよう即座にディスクへ書き出します
+func (j *importJournal) Record(pivotalID, issueKey string) error {
+	if j == nil {
+		return nil
+	}
+
+	j.mutex.Lock()
+	defer j.mutex.Unlock()
+
+	line := fmt.Sprintf("%s\t%s\t%s\n", time.Now().Format(time.RFC3339), pivotalID, issueKey)
+	if _, err := j.file.WriteString(line); err != nil {
+		return fmt.Errorf("ジャーナル書き込みエラー: %w", err)
+	}
+	if err := j.file.Sync(); err != nil {
+		return fmt.Errorf("ジャーナル書き込みエラー: %w", err)
+	}
+	return nil
+}
+
+// Close はジャーナルファイルを閉じます
+func (j *importJournal) Close() error {
+	if j == nil {
+		return nil
+	}
+	return j.file.Close()
+}
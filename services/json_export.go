@@ -0,0 +1,284 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"pivotaltojira/api"
+	"pivotaltojira/config"
+	"pivotaltojira/models"
+	"pivotaltojira/utils"
+)
+
+// storyPointFieldType はJSONインポートでストーリーポイントに使うカスタムフィールドの型です
+const storyPointFieldType = "com.atlassian.jira.plugin.system.customfieldtypes:float"
+
+// JSONExporter はJIRA CSVをJIRAの外部システムインポート（JSON）形式に変換します
+// REST APIは使用しないため、JIRAの接続情報は不要です
+type JSONExporter struct {
+	config  *config.Config
+	csvProc *CSVProcessor
+}
+
+// NewJSONExporter は新しいJSONエクスポーターを作成します
+func NewJSONExporter(cfg *config.Config, csvProc *CSVProcessor) *JSONExporter {
+	return &JSONExporter{
+		config:  cfg,
+		csvProc: csvProc,
+	}
+}
+
+// Export はJIRA CSVを読み込み、JIRA_JSON にJSONインポート用のファイルを書き出します
+func (e *JSONExporter) Export() (*models.JiraImportFile, error) {
+	records, err := e.csvProc.ReadCSV(e.config.JiraCSV)
+	if err != nil {
+		return nil, fmt.Errorf("JIRA CSV読み込みエラー: %w", err)
+	}
+
+	importFile, err := e.Build(records)
+	if err != nil {
+		return nil, err
+	}
+	if err := ValidateImportFile(importFile); err != nil {
+		return nil, err
+	}
+
+	data, err := json.MarshalIndent(importFile, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("JSONエンコードエラー: %w", err)
+	}
+	if err := os.WriteFile(e.config.JiraJSON, data, 0644); err != nil {
+		return nil, fmt.Errorf("JSONファイル書き込みエラー: %w", err)
+	}
+
+	return importFile, nil
+}
+
+// Build はJIRA CSVのレコードからJSONインポート用のデータを作成します
+// リリースの行は RELEASES_AS_VERSIONS が有効な場合、イシューではなくバージョンとして出力します
+func (e *JSONExporter) Build(records []models.CSVRecord) (*models.JiraImportFile, error) {
+	if e.config.JiraProjectKey == "" {
+		return nil, fmt.Errorf("JIRA_PROJECT_KEY が設定されていません")
+	}
+
+	project := models.JiraImportProject{
+		Name:   e.config.JiraProjectKey,
+		Key:    e.config.JiraProjectKey,
+		Issues: make([]models.JiraImportIssue, 0, len(records)),
+	}
+
+	var pending []int
+	for _, record := range records {
+		if e.config.ReleasesAsVersions && IsRelease(record) {
+			name := strings.TrimSpace(record["Title"])
+			if name == "" {
+				pending = nil
+				continue
+			}
+			project.Versions = append(project.Versions, models.JiraImportVersion{
+				Name:     name,
+				Released: record["Resolved Date"] != "",
+			})
+			// 直前までのストーリーをこのバージョンに含める
+			for _, i := range pending {
+				if len(project.Issues[i].FixedVersions) == 0 {
+					project.Issues[i].FixedVersions = []string{name}
+				}
+			}
+			pending = nil
+			continue
+		}
+
+		issue, err := e.buildIssue(record)
+		if err != nil {
+			return nil, err
+		}
+		pending = append(pending, len(project.Issues))
+		project.Issues = append(project.Issues, issue)
+	}
+
+	return &models.JiraImportFile{Projects: []models.JiraImportProject{project}}, nil
+}
+
+// buildIssue は1つのレコードをJSONインポートのイシューに変換します
+func (e *JSONExporter) buildIssue(record models.CSVRecord) (models.JiraImportIssue, error) {
+	pivotalID := record["JIRA Issue ID"]
+
+	title, _ := issueTitle(record)
+
+	status := targetStatus(e.config, record)
+	if status == "" {
+		status = "Backlog"
+	}
+
+	// 担当者・報告者は USER_MAPPING で変換し、マッピングにない場合は説明文に追記する（REST APIでの作成と同じ）
+	description := record["Description"]
+	assignee, description := exportUser(firstOwner(record["Assignee"]), e.config.AssigneeNotePrefix, description)
+	if owners := parseUsers(record["Assignee"]); len(owners) > 1 {
+		description = strings.TrimSpace(description + "\n\n" + utils.T("import.note_other_owners", strings.Join(owners[1:], ", ")))
+	}
+	reporter, description := exportUser(record["Reporter"], e.config.ReporterNotePrefix, description)
+
+	issue := models.JiraImportIssue{
+		ExternalID:  pivotalID,
+		Summary:     buildSummary(e.config.SummaryPrefixFormat, pivotalID, title),
+		Description: description,
+		IssueType:   resolveIssueType(e.config, record["Type"]),
+		Status:      status,
+		Resolution:  e.config.ResolutionFor(status),
+		Reporter:    reporter,
+		Assignee:    assignee,
+		Labels:      withPivotalIDLabel(e.config.PivotalIDLabelPrefix, withGlobalLabels(e.config.GlobalLabels, parseLabels(record["Labels"])), pivotalID),
+		Created:     record["Created Date"],
+		Resolved:    record["Resolved Date"],
+	}
+
+	if version := record["Fix Version"]; version != "" {
+		issue.FixedVersions = []string{version}
+	}
+
+	if sp, ok := parseStoryPoints(record["Story Points"]); ok && sp > 0 {
+		issue.CustomFieldValues = append(issue.CustomFieldValues, models.JiraImportCustomField{
+			FieldName: e.config.JSONStoryPointFieldName,
+			FieldType: storyPointFieldType,
+			Value:     formatStoryPoints(sp),
+		})
+	}
+
+	// 結合されたコメントは元のコメントごとに分ける
+	if comment := record["Comment"]; comment != "" {
+		for _, body := range strings.Split(comment, commentSeparator) {
+			if body = strings.TrimSpace(body); body != "" {
+				issue.Comments = append(issue.Comments, models.JiraImportComment{Body: body})
+			}
+		}
+	}
+
+	attachments, err := e.attachments(pivotalID)
+	if err != nil {
+		return issue, err
+	}
+	issue.Attachments = attachments
+
+	return issue, nil
+}
+
+// exportUser はPivotalのユーザーを USER_MAPPING でJIRAのユーザーに変換します
+// マッピングにない場合は空を返し、notePrefix に続けてユーザー名を説明文の末尾に追記します
+func exportUser(user, notePrefix, description string) (string, string) {
+	if user == "" {
+		return "", description
+	}
+	if mapped, ok := api.MapUser(user); ok {
+		return mapped, description
+	}
+	return "", strings.TrimSpace(description + "\n\n" + notePrefix + " " + user)
+}
+
+// attachments は ATTACHMENTS_FOLDER（複数指定時はそれぞれ）の <Pivotal ID>/ 内のファイルを添付ファイルの参照として返します
+func (e *JSONExporter) attachments(pivotalID string) ([]models.JiraImportAttachment, error) {
+	if pivotalID == "" {
+		return nil, nil
+	}
+
+	folders, err := e.config.AttachmentFolders()
+	if err != nil {
+		return nil, err
+	}
+
+	var attachments []models.JiraImportAttachment
+	for _, folder := range folders {
+		entries, err := os.ReadDir(filepath.Join(folder, pivotalID))
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("フォルダ読み取りエラー: %w", err)
+		}
+
+		for _, entry := range entries {
+			if entry.IsDir() {
+				continue
+			}
+			uri, err := e.attachmentURI(pivotalID, entry.Name(), filepath.Join(folder, pivotalID, entry.Name()))
+			if err != nil {
+				return nil, err
+			}
+			attachments = append(attachments, models.JiraImportAttachment{Name: entry.Name(), URI: uri})
+		}
+	}
+	return attachments, nil
+}
+
+// attachmentURI は添付ファイルのURIを返します
+// JSON_ATTACHMENT_BASE_URL が設定されている場合は <基点>/<Pivotal ID>/<ファイル名>、なければ file:// の絶対パスです
+func (e *JSONExporter) attachmentURI(pivotalID, name, filePath string) (string, error) {
+	if base := e.config.JSONAttachmentBaseURL; base != "" {
+		u, err := url.Parse(base)
+		if err != nil {
+			return "", fmt.Errorf("JSON_ATTACHMENT_BASE_URL が不正です: %w", err)
+		}
+		u.Path = path.Join(u.Path, pivotalID, name)
+		return u.String(), nil
+	}
+
+	absPath, err := filepath.Abs(filePath)
+	if err != nil {
+		return "", fmt.Errorf("添付ファイルのパス解決エラー: %w", err)
+	}
+	return (&url.URL{Scheme: "file", Path: filepath.ToSlash(absPath)}).String(), nil
+}
+
+// ValidateImportFile はJIRAのJSONインポーターが要求する項目がそろっているかを確認します
+// プロジェクトの name/key、イシューの externalId/summary/issueType/status、添付ファイルの name/uri は必須です
+func ValidateImportFile(importFile *models.JiraImportFile) error {
+	if len(importFile.Projects) == 0 {
+		return fmt.Errorf("プロジェクトがありません")
+	}
+
+	var problems []string
+	for _, project := range importFile.Projects {
+		if project.Name == "" || project.Key == "" {
+			problems = append(problems, "プロジェクトの name または key が空です")
+		}
+
+		seen := make(map[string]bool, len(project.Issues))
+		for i, issue := range project.Issues {
+			where := fmt.Sprintf("%s のイシュー %d (externalId: %s)", project.Key, i+1, issue.ExternalID)
+			if issue.ExternalID == "" {
+				problems = append(problems, where+": externalId が空です")
+			} else if seen[issue.ExternalID] {
+				problems = append(problems, where+": externalId が重複しています")
+			}
+			seen[issue.ExternalID] = true
+
+			if issue.Summary == "" {
+				problems = append(problems, where+": summary が空です")
+			}
+			if issue.IssueType == "" {
+				problems = append(problems, where+": issueType が空です")
+			}
+			if issue.Status == "" {
+				problems = append(problems, where+": status が空です")
+			}
+			for _, attachment := range issue.Attachments {
+				if attachment.Name == "" || attachment.URI == "" {
+					problems = append(problems, where+": 添付ファイルの name または uri が空です")
+				}
+			}
+		}
+	}
+
+	if len(problems) > 0 {
+		for _, problem := range problems {
+			utils.LogError("JSON検証エラー: %s", problem)
+		}
+		return fmt.Errorf("JSONインポートファイルの検証に失敗しました: %d 件の問題", len(problems))
+	}
+	return nil
+}
@@ -0,0 +1,42 @@
+package services
+
+import (
+	"testing"
+)
+
+func TestJSONExportMapsUsers(t *testing.T) {
+	cfg := newTestConfig(t, "https://jira.example.test", nil)
+	e := NewJSONExporter(cfg, NewCSVProcessor(cfg))
+
+	tests := []struct {
+		name            string
+		assignee        string
+		reporter        string
+		wantAssignee    string
+		wantReporter    string
+		wantDescription string
+	}{
+		{"マッピングにあるユーザー", "pivotal_user1", "pivotal_user1", "jira_user1", "jira_user1", "説明"},
+		{"マッピングにないユーザー", "carol", "dave", "", "", "説明\n\n担当者: carol\n\n報告者: dave"},
+		{"複数の担当者", "pivotal_user1, bob", "", "jira_user1", "", "説明\n\n他の担当者: bob"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			record := jiraRecord("100", "ユーザー", "feature", "")
+			record["Description"] = "説明"
+			record["Assignee"] = tt.assignee
+			record["Reporter"] = tt.reporter
+
+			issue, err := e.buildIssue(record)
+			if err != nil {
+				t.Fatalf("buildIssue がエラーを返しました: %v", err)
+			}
+			if issue.Assignee != tt.wantAssignee || issue.Reporter != tt.wantReporter {
+				t.Errorf("担当者・報告者 = %q, %q, want %q, %q", issue.Assignee, issue.Reporter, tt.wantAssignee, tt.wantReporter)
+			}
+			if issue.Description != tt.wantDescription {
+				t.Errorf("Description = %q, want %q", issue.Description, tt.wantDescription)
+			}
+		})
+	}
+}
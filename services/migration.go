@@ -1,11 +1,17 @@
 package services
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
+	"maps"
+	"net/http"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"pivotaltojira/api"
@@ -19,6 +25,19 @@ type MigrationService struct {
 	config     *config.Config
 	jiraClient *api.JiraClient
 	csvProc    *CSVProcessor
+
+	summary      *models.MigrationSummary
+	summaryMutex sync.Mutex
+
+	journal *importJournal // インポート中のみ有効（IMPORT_JOURNAL が空の場合は nil）
+
+	dateFieldTypes map[string]string // 元の日時を保存するフィールドID → date/datetime
+
+	issueMapping models.IssueMapping // インポートで書き出したPivotal ID → JIRA Key（未インポートの場合は nil）
+
+	progress ProgressReporter
+
+	authMutex sync.Mutex // インポート中に認証を確認し直す処理を1つずつ行う
 }
 
 // NewMigrationService は新しい移行サービスを作成します
@@ -27,7 +46,25 @@ func NewMigrationService(cfg *config.Config, jiraClient *api.JiraClient, csvProc
 		config:     cfg,
 		jiraClient: jiraClient,
 		csvProc:    csvProc,
+		summary: &models.MigrationSummary{
+			PhaseSeconds:     make(map[string]float64),
+			PhaseHTTPSeconds: make(map[string]float64),
+		},
+		progress: LogProgressReporter{},
+	}
+}
+
+// SetProgressReporter は進捗を受け取る ProgressReporter を設定します（nil の場合は LogProgressReporter）
+func (m *MigrationService) SetProgressReporter(reporter ProgressReporter) {
+	if reporter == nil {
+		reporter = LogProgressReporter{}
 	}
+	m.progress = reporter
+}
+
+// Summary はこれまでの処理結果の集計を返します
+func (m *MigrationService) Summary() *models.MigrationSummary {
+	return m.summary
 }
 
 // ConvertCSV はPivotalのCSVをJIRA形式に変換します
@@ -38,36 +75,117 @@ func (m *MigrationService) ConvertCSV() error {
 		return fmt.Errorf("Pivotal CSV読み込みエラー: %w", err)
 	}
 
+	m.summary.RowsRead = len(records)
+
 	// JIRA形式に変換
 	jiraRecords, err := m.csvProc.ProcessPivotalToJiraCSV(records)
 	if err != nil {
 		return fmt.Errorf("CSV変換エラー: %w", err)
 	}
 
+	// マッピングできなかった値を報告
+	m.csvProc.ValidateConversion(jiraRecords)
+
 	// JIRA CSVとして保存
 	if err := m.csvProc.WriteJiraCSV(jiraRecords); err != nil {
 		return fmt.Errorf("JIRA CSV書き込みエラー: %w", err)
 	}
 
-	utils.LogInfo("CSVの変換が完了しました")
+	utils.LogInfo(utils.T("convert.done"))
 	return nil
 }
 
 // ImportIssues はJIRAにイシューをインポートします
 func (m *MigrationService) ImportIssues() error {
+	_, err := m.ImportIssuesWithResult()
+	return err
+}
+
+// ImportIssuesWithResult はJIRAにイシューをインポートし、行ごとの結果を返します
+// 行の処理後にCSVの更新などで失敗した場合は、それまでの結果とエラーの両方を返します
+func (m *MigrationService) ImportIssuesWithResult() (*models.ImportResult, error) {
 	startTime := time.Now()
 	defer utils.TrackTime(startTime, "イシューインポート")
 
-	// JIRA CSVを読み込む
-	records, err := m.csvProc.ReadCSV(m.config.JiraCSV)
+	// JIRA CSVを読み込む（読み込んだ内容はキーの書き戻しにも使う）
+	table, err := m.csvProc.ReadCSVTable(m.config.JiraCSV)
+	if err != nil {
+		return nil, fmt.Errorf("JIRA CSV読み込みエラー: %w", err)
+	}
+	records := table.Records()
+
+	// プロジェクトキーの事前確認
+	if err := m.jiraClient.VerifyProject(); err != nil {
+		return nil, fmt.Errorf("プロジェクト確認エラー: %w", err)
+	}
+
+	// 省略する項目を記録（段階的な移行で後から反映する場合）
+	if steps := m.config.SkippedSteps(); len(steps) > 0 {
+		m.summary.SkippedSteps = steps
+		utils.LogInfo(utils.T("import.skipping_steps", strings.Join(steps, ", ")))
+	}
+
+	// ストーリーポイントが失われないよう、フィールドを事前に確認
+	if !m.config.SkipStoryPoints && hasStoryPoints(records) {
+		if err := m.verifyStoryPointField(); err != nil {
+			return nil, err
+		}
+	}
+
+	// Pivotal IDを保存するフィールドが文字列型か事前に確認
+	if m.config.PivotalIDField != "" {
+		if err := m.verifyPivotalIDField(); err != nil {
+			return nil, err
+		}
+	}
+
+	// 元の作成日時・受け入れ日時を保存するフィールドの型を確認
+	if err := m.verifyDateFields(); err != nil {
+		return nil, err
+	}
+
+	// -start/-limit で指定された範囲のみ処理する（行番号は元のCSVの行番号のまま）
+	start, end := importRange(len(records), m.config.ImportStartRow, m.config.ImportLimit)
+	if end-start < len(records) {
+		utils.LogInfo(utils.T("import.range", start+1, end, len(records)))
+	}
+
+	// -filter-state/-filter-label に一致しない行はスキップする
+	if len(m.config.FilterStates) > 0 && len(records) > 0 {
+		if _, ok := records[0]["Pivotal State"]; !ok {
+			return nil, fmt.Errorf("JIRA CSVに Pivotal State カラムがありません。csv_convert で変換し直してください")
+		}
+	}
+	rowIndexes, releases, filtered := m.selectRows(records, start, end) // 元のCSVでの行インデックス
+	targets := make([]models.CSVRecord, len(rowIndexes))
+	for n, i := range rowIndexes {
+		targets[n] = records[i]
+	}
+	if filtered > 0 {
+		utils.LogInfo(utils.T("import.filtered", filtered))
+	}
+
+	if releases > 0 {
+		utils.LogInfo(utils.T("import.release_rows", releases))
+		m.createReleaseVersions(records[start:end])
+	}
+
+	// 作成したイシューをCSVより先にジャーナルへ記録する
+	journal, err := openImportJournal(m.config.ImportJournal)
 	if err != nil {
-		return fmt.Errorf("JIRA CSV読み込みエラー: %w", err)
+		return nil, err
 	}
+	m.journal = journal
+	defer func() {
+		m.journal = nil
+		journal.Close()
+	}()
 
-	utils.LogInfo("イシューのインポートを開始します: %d 件", len(records))
+	utils.LogInfo(utils.T("import.start_count", len(targets)))
 
 	// 結果を格納するマップ
 	resultMapping := make(models.IssueMapping)
+	result := &models.ImportResult{Rows: make([]models.RowResult, len(targets))}
 	var resultMutex sync.Mutex
 
 	// エラーフラグを格納するマップ
@@ -80,161 +198,1063 @@ func (m *MigrationService) ImportIssues() error {
 	// 待機グループ
 	var wg sync.WaitGroup
 
-	// エラー数カウンター
-	errorCount := 0
+	// エラー数カウンター（MAX_ERRORS を超えたら残りの行は処理しない）
+	var errorCount atomic.Int64
+	var aborted atomic.Bool
+	var authExpired atomic.Bool // 認証を確認し直しても失敗した（トークンの期限切れなど）
+	dispatched := make([]bool, len(targets))
+	dispatchedCount := 0
+
+	// ORDERED_LOG が有効な場合は行ごとのログを処理を始めた順（フェーズが1つの場合は元の行順）で出力する
+	flusher := utils.NewOrderedFlusher()
+
+	// フェーズごとに各レコードを処理（EPICS_FIRST の場合はエピックの作成がすべて終わってから他の行を処理する）
+	phases := m.importPhases(targets)
+dispatch:
+	for n, phase := range phases {
+		if len(phases) > 1 {
+			utils.LogInfo(utils.T("import.phase_start", n+1, len(phases), len(phase)))
+		}
 
-	// 各レコードを処理
-	for i, record := range records {
-		wg.Add(1)
+		for _, i := range phase {
+			// セマフォに空構造体を送信（空きスロットを一つ使用）
+			semaphore <- struct{}{}
 
-		// セマフォに空構造体を送信（空きスロットを一つ使用）
-		semaphore <- struct{}{}
+			// MIGRATION_DEADLINE / RETRY_BUDGET で止まった場合も残りの行は処理しない
+			if m.jiraClient.Stopped() != nil {
+				aborted.Store(true)
+			}
+			if aborted.Load() {
+				<-semaphore
+				break dispatch
+			}
+			wg.Add(1)
+			dispatched[i] = true
+			dispatchedCount++
 
-		go func(idx int, rec models.CSVRecord) {
-			defer wg.Done()
-			defer func() { <-semaphore }() // 処理完了時にセマフォからスロットを解放
+			go func(pos, idx, seq int, rec models.CSVRecord) {
+				defer wg.Done()
+				defer func() { <-semaphore }() // 処理完了時にセマフォからスロットを解放
 
-			// エラーフラグをチェック（前回の実行で失敗したかどうか）
-			if errorFlag, ok := rec["Error"]; ok && errorFlag == "1" {
-				utils.LogInfo("行 %d: 前回失敗したレコードを再処理します", idx+1)
-			}
+				rowLog := utils.NewRowLogger(m.config.OrderedLog)
+				defer flusher.Done(seq, rowLog)
 
-			// イシュー作成
-			issueKey, err := m.processRecord(rec)
+				// タイトルが空の行は "No Title" で作成し、元データを直せるよう警告する
+				_, noTitle := issueTitle(rec)
+				if noTitle {
+					rowLog.Warn(utils.T("import.empty_title", idx+1, rec["JIRA Issue ID"]))
+				}
 
-			resultMutex.Lock()
-			defer resultMutex.Unlock()
+				// エラーフラグをチェック（前回の実行で失敗したかどうか）
+				if errorFlag, ok := rec["Error"]; ok && errorFlag == "1" {
+					rowLog.Info(utils.T("import.retry_row", idx+1))
+				}
 
-			pivotalID := rec["JIRA Issue ID"]
-			if err != nil {
-				utils.LogError("行 %d の処理に失敗: %v", idx+1, err)
+				// 既存イシューの更新またはイシュー作成
+				var issueKey string
+				var fieldErrors map[string]string
+				var reused bool
+				var err error
+				existingKey := rec["JIRA Issue Key"]
+				updated := m.config.UpdateExisting && existingKey != "" && existingKey != "ERROR"
+				if updated {
+					issueKey, fieldErrors, err = m.updateRecord(rec, existingKey, rowLog)
+				} else if rec["Error"] == "1" && existingKey != "" && existingKey != "ERROR" {
+					// 作成済みで後続の処理に失敗した行は、作成し直さずに残りの処理だけを行う
+					issueKey = existingKey
+					fieldErrors, err = m.resumeRecord(rec, existingKey, rowLog)
+				} else {
+					issueKey, reused, fieldErrors, err = m.processRecord(rec, rowLog)
+				}
 
-				errorMutex.Lock()
-				errorCount++
-				errorFlags[pivotalID] = true
-				errorMutex.Unlock()
+				// 実行中にトークンの期限が切れると以降の行がすべて401で失敗するため、認証を確認し直して中断する
+				if api.StatusCode(err) == http.StatusUnauthorized && m.recheckAuth(idx+1, &authExpired) {
+					aborted.Store(true)
+				}
 
-				resultMapping[pivotalID] = "ERROR"
-			} else {
-				utils.LogInfo("行 %d の処理が完了: %s", idx+1, issueKey)
-				resultMapping[pivotalID] = issueKey
+				resultMutex.Lock()
+				defer resultMutex.Unlock()
 
-				errorMutex.Lock()
-				errorFlags[pivotalID] = false
-				errorMutex.Unlock()
-			}
-		}(i, record)
+				pivotalID := rec["JIRA Issue ID"]
+				row := &result.Rows[pos]
+				*row = models.RowResult{Row: idx + 1, PivotalID: pivotalID, IssueKey: issueKey, Updated: updated, Reused: reused, EmptyTitle: noTitle}
+				if len(fieldErrors) > 0 {
+					row.FieldErrors = fieldErrors
+				}
+				if err != nil {
+					row.Error = err.Error()
+					result.Failed++
+					rowLog.Error(utils.T("import.row_failed", idx+1, err))
+					m.progress.OnIssueFailed(*row, err)
+
+					if n := errorCount.Add(1); m.config.MaxErrors > 0 && n > int64(m.config.MaxErrors) {
+						aborted.Store(true)
+					}
+
+					errorMutex.Lock()
+					errorFlags[pivotalID] = true
+					errorMutex.Unlock()
+
+					// 作成済みのイシューはキーを残し、再実行時に作成し直さないようにする
+					if issueKey != "" {
+						resultMapping[pivotalID] = issueKey
+					} else {
+						resultMapping[pivotalID] = "ERROR"
+					}
+				} else {
+					row.URL = m.config.BrowseURL(issueKey)
+					rowLog.Info(utils.T("import.row_done", idx+1, issueKey, row.URL))
+					m.progress.OnIssueCreated(*row)
+					result.Succeeded++
+					resultMapping[pivotalID] = issueKey
+
+					errorMutex.Lock()
+					errorFlags[pivotalID] = false
+					errorMutex.Unlock()
+				}
+			}(i, rowIndexes[i], dispatchedCount-1, targets[i])
+		}
+
+		// 次のフェーズを始める前に、このフェーズのすべての行の完了を待つ
+		wg.Wait()
 	}
 
 	// すべてのgoroutineの完了を待つ
 	wg.Wait()
 	close(semaphore)
 
+	// 中断した場合は処理しなかった行を結果から除く（処理済みの行の結果は書き出す）
+	if aborted.Load() {
+		rows := result.Rows[:0]
+		for pos, row := range result.Rows {
+			if dispatched[pos] {
+				rows = append(rows, row)
+			}
+		}
+		result.Rows = rows
+		if authExpired.Load() {
+			utils.LogError(utils.T("import.aborted_auth", len(targets)-dispatchedCount))
+		} else if stopErr := m.jiraClient.Stopped(); stopErr != nil {
+			utils.LogError(utils.T("import.stopped", stopErr, len(targets)-dispatchedCount))
+		} else {
+			utils.LogError(utils.T("import.aborted", m.config.MaxErrors, len(targets)-dispatchedCount))
+		}
+	}
+
+	// Pivotalのタスクを新規作成した親イシューのサブタスクとして作成する
+	createdParents := make(models.IssueMapping)
+	for _, row := range result.Rows {
+		if row.Error == "" && !row.Updated && !row.Reused {
+			createdParents[row.PivotalID] = row.IssueKey
+		}
+	}
+	m.createSubtasks(targets, createdParents)
+
 	// 結果をCSVに書き込む
-	if err := m.csvProc.UpdateJiraKeysWithErrorFlags(resultMapping, errorFlags); err != nil {
-		return fmt.Errorf("JIRA キー更新エラー: %w", err)
+	if err := m.csvProc.UpdateTableJiraKeys(table, resultMapping, errorFlags); err != nil {
+		return result, fmt.Errorf("JIRA キー更新エラー: %w", err)
+	}
+
+	// 添付ファイルのアップロードでCSVを読み直さないようマッピングを保持する
+	if mapping, err := table.IssueMapping(); err == nil {
+		m.issueMapping = mapping
+	}
+
+	// 外部ツール向けにマッピングをJSONでも書き出す
+	if err := m.csvProc.WriteMappingJSON(resultMapping); err != nil {
+		utils.LogWarn(utils.T("import.mapping_write_failed", err))
+	}
+
+	// Pivotalのバックログ順をJIRAのランクに反映
+	if m.config.PreserveRank {
+		m.rankIssues(targets, resultMapping)
+	}
+
+	if m.summary.RowsRead == 0 {
+		m.summary.RowsRead = len(records)
+	}
+	for _, row := range result.Rows {
+		if row.EmptyTitle {
+			m.summary.EmptyTitles = append(m.summary.EmptyTitles, row.PivotalID)
+		}
+	}
+	if n := len(m.summary.EmptyTitles); n > 0 {
+		utils.LogWarn(utils.T("import.empty_titles", n, strings.Join(m.summary.EmptyTitles, ", ")))
+	}
+
+	// 同じPivotal IDの行が複数あってもそれぞれ数えるよう、マッピングではなく行ごとの結果から集計する
+	created, updated, reused, failed := countRows(result.Rows)
+	m.summary.IssuesCreated += created
+	m.summary.IssuesUpdated += updated
+	m.summary.IssuesReused += reused
+	m.summary.IssuesFailed += failed
+	m.summary.TransitionCallsSaved = m.jiraClient.TransitionCallsSaved()
+	m.summary.TunedConcurrency = m.jiraClient.TunedConcurrency()
+
+	utils.LogInfo(utils.T("import.done", created+updated+reused, created, updated, reused, failed))
+	utils.LogInfo(utils.T("import.transition_calls_saved", m.summary.TransitionCallsSaved))
+	if m.summary.TunedConcurrency > 0 {
+		utils.LogInfo(utils.T("import.tuned_concurrency", m.summary.TunedConcurrency, m.config.MaxConcurrent))
+	}
+	if n := len(m.summary.StatusUnchanged); n > 0 {
+		utils.LogWarn(utils.T("import.status_unchanged", n))
+		for _, u := range m.summary.StatusUnchanged {
+			utils.LogWarn("  %s (Pivotal ID: %s) → '%s': %s", u.IssueKey, u.PivotalID, u.TargetStatus, u.Reason)
+		}
+	}
+	if authExpired.Load() {
+		return result, fmt.Errorf("JIRAの認証に失敗したため中断しました（APIトークンの有効期限が切れた可能性があります）")
+	}
+	if stopErr := m.jiraClient.Stopped(); stopErr != nil {
+		return result, stopErr
+	}
+	if aborted.Load() {
+		return result, fmt.Errorf("エラーが MAX_ERRORS (%d) を超えたため中断しました", m.config.MaxErrors)
+	}
+	return result, nil
+}
+
+// countRows は行ごとの結果から、作成・更新・既存イシューの使用・失敗の件数を数えます
+func countRows(rows []models.RowResult) (created, updated, reused, failed int) {
+	for _, row := range rows {
+		switch {
+		case row.Error != "":
+			failed++
+		case row.Updated:
+			updated++
+		case row.Reused:
+			reused++
+		default:
+			created++
+		}
+	}
+	return created, updated, reused, failed
+}
+
+// recheckAuth は行の処理で401が返された場合に認証を確認し直し、認証情報が無効になっていれば true を返します
+// 並列に処理している行で同時に401が返されても確認は1つずつ行い、無効と分かった後は確認しません
+// CONTINUE_ON_AUTH_WARNING の場合は警告のみ出力し、常に false を返します
+func (m *MigrationService) recheckAuth(row int, expired *atomic.Bool) bool {
+	if m.config.ContinueOnAuthWarning {
+		utils.LogWarn(utils.T("import.auth_warning", row))
+		return false
+	}
+
+	m.authMutex.Lock()
+	defer m.authMutex.Unlock()
+	if expired.Load() {
+		return true
+	}
+
+	utils.LogWarn(utils.T("import.auth_recheck", row))
+	err := m.jiraClient.CheckAuth()
+	if api.ClassifyAuthError(err) != api.AuthFailureCredentials {
+		// 認証が通る、または一時的なエラーの場合は行ごとの問題として続行する
+		return false
+	}
+
+	utils.LogError(utils.T("import.auth_expired", err))
+	expired.Store(true)
+	return true
+}
+
+// IsRelease はPivotalのリリース（マイルストーン）の行かどうかを判定します
+func IsRelease(record models.CSVRecord) bool {
+	return strings.EqualFold(record["Type"], "release")
+}
+
+// createReleaseVersions はリリースの行をJIRAのバージョンとして作成し、
+// その直前までのストーリーに "Fix Version" を設定します（同名のバージョンがあれば再利用）
+func (m *MigrationService) createReleaseVersions(records []models.CSVRecord) {
+	versions, err := m.jiraClient.ListVersions()
+	if err != nil {
+		utils.LogWarn(utils.T("import.list_versions_failed", err))
+		versions = make(map[string]string)
 	}
 
-	utils.LogInfo("イシューのインポートが完了しました: 成功=%d, 失敗=%d", len(resultMapping)-errorCount, errorCount)
+	var pending []models.CSVRecord
+	for _, rec := range records {
+		if !IsRelease(rec) {
+			pending = append(pending, rec)
+			continue
+		}
+
+		name := strings.TrimSpace(rec["Title"])
+		if _, ok := versions[name]; !ok && name != "" {
+			id, err := m.jiraClient.CreateVersion(name)
+			if err != nil {
+				utils.LogError(utils.T("import.create_version_failed", name, err))
+				pending = nil
+				continue
+			}
+			versions[name] = id
+			utils.LogInfo(utils.T("import.version_created", name))
+		}
+
+		for _, story := range pending {
+			if story["Fix Version"] == "" {
+				story["Fix Version"] = name
+			}
+		}
+		pending = nil
+	}
+}
+
+// matchesFilter はレコードが FILTER_STATES / FILTER_LABELS の条件に一致するかを判定します
+// 両方指定された場合は両方に一致する必要があり、それぞれいずれかの値に一致すれば一致とみなします
+func (m *MigrationService) matchesFilter(record models.CSVRecord) bool {
+	if len(m.config.FilterStates) > 0 && !containsFold(m.config.FilterStates, record["Pivotal State"]) {
+		return false
+	}
+	if len(m.config.FilterLabels) > 0 && !slices.ContainsFunc(parseLabels(record["Labels"]), func(label string) bool {
+		return containsFold(m.config.FilterLabels, label)
+	}) {
+		return false
+	}
+	return true
+}
+
+// selectRows は records[start:end] のうちイシューとしてインポートする行のインデックスを返します
+// RELEASES_AS_VERSIONS の場合のリリースの行と、フィルター条件に一致しない行はそれぞれ別に数えます
+func (m *MigrationService) selectRows(records []models.CSVRecord, start, end int) (rowIndexes []int, releases, filtered int) {
+	for i := start; i < end; i++ {
+		// リリースの行はイシューではなくバージョンとして作成する
+		if m.config.ReleasesAsVersions && IsRelease(records[i]) {
+			releases++
+			continue
+		}
+		if !m.matchesFilter(records[i]) {
+			utils.LogDebug("行 %d: フィルター条件に一致しないためスキップします", i+1)
+			filtered++
+			continue
+		}
+		rowIndexes = append(rowIndexes, i)
+	}
+	return rowIndexes, releases, filtered
+}
+
+// containsFold は大文字小文字を区別せずに値が含まれるかを判定します
+func containsFold(values []string, value string) bool {
+	return slices.ContainsFunc(values, func(v string) bool { return strings.EqualFold(v, value) })
+}
+
+// importRange は -start（1始まり）と -limit からインポート対象の範囲 [start, end) を求めます
+func importRange(total, startRow, limit int) (int, int) {
+	start := 0
+	if startRow > 1 {
+		start = min(startRow-1, total)
+	}
+	end := total
+	if limit > 0 {
+		end = min(start+limit, total)
+	}
+	return start, end
+}
+
+// hasStoryPoints はストーリーポイントが設定された行があるかを返します
+func hasStoryPoints(records []models.CSVRecord) bool {
+	return slices.ContainsFunc(records, func(rec models.CSVRecord) bool {
+		sp, ok := parseStoryPoints(rec["Story Points"])
+		return ok && sp > 0
+	})
+}
+
+// verifyStoryPointField は JIRA_STORY_POINT_FIELD が作成画面にある数値フィールドかを確認します
+// 作成画面の情報を取得できない場合は警告のみで続行します
+func (m *MigrationService) verifyStoryPointField() error {
+	issueTypes, err := m.jiraClient.GetCreateMeta()
+	if err != nil {
+		utils.LogWarn(utils.T("import.verify_sp_failed", err))
+		return nil
+	}
+	if err := api.CheckStoryPointField(issueTypes, m.config.StoryPointField); err != nil {
+		return fmt.Errorf("%w（JIRA_STORY_POINT_FIELD を確認してください）", err)
+	}
+	return nil
+}
+
+// verifyPivotalIDField は PIVOTAL_ID_FIELD が作成画面にある文字列フィールドかを確認します
+// 作成画面の情報を取得できない場合は警告のみ出力して続行します
+func (m *MigrationService) verifyPivotalIDField() error {
+	issueTypes, err := m.jiraClient.GetCreateMeta()
+	if err != nil {
+		utils.LogWarn(utils.T("import.verify_id_failed", err))
+		return nil
+	}
+	if err := api.CheckPivotalIDField(issueTypes, m.config.PivotalIDField); err != nil {
+		return fmt.Errorf("%w（PIVOTAL_ID_FIELD を確認してください）", err)
+	}
+	return nil
+}
+
+// verifyDateFields は ORIGINAL_CREATED_FIELD / ORIGINAL_RESOLVED_FIELD / CUSTOM_DATE_FIELD_MAP のフィールドが
+// 作成画面にある日付フィールドかを確認し、型を記録します
+// 作成画面の情報を取得できない場合は警告のみ出力し、datetime として扱います
+func (m *MigrationService) verifyDateFields() error {
+	m.dateFieldTypes = make(map[string]string)
+
+	var fieldIDs []string
+	for _, id := range []string{m.config.OriginalCreatedField, m.config.OriginalResolvedField} {
+		if id != "" {
+			fieldIDs = append(fieldIDs, id)
+		}
+	}
+	for _, column := range slices.Sorted(maps.Keys(m.config.CustomDateFieldMap)) {
+		if id := m.config.CustomDateFieldMap[column]; !slices.Contains(fieldIDs, id) {
+			fieldIDs = append(fieldIDs, id)
+		}
+	}
+	if len(fieldIDs) == 0 {
+		return nil
+	}
+
+	issueTypes, err := m.jiraClient.GetCreateMeta()
+	if err != nil {
+		utils.LogWarn(utils.T("import.verify_dates_failed", err))
+		return nil
+	}
+	for _, id := range fieldIDs {
+		fieldType, err := api.CheckDateField(issueTypes, id)
+		if err != nil {
+			return fmt.Errorf("%w（ORIGINAL_CREATED_FIELD / ORIGINAL_RESOLVED_FIELD / CUSTOM_DATE_FIELD_MAP を確認してください）", err)
+		}
+		m.dateFieldTypes[id] = fieldType
+	}
 	return nil
 }
 
+// rankIssues は作成したイシューを元のCSVの行順でランク付けします
+func (m *MigrationService) rankIssues(records []models.CSVRecord, resultMapping models.IssueMapping) {
+	issueKeys := make([]string, 0, len(records))
+	for _, rec := range records {
+		if issueKey := resultMapping[rec["JIRA Issue ID"]]; issueKey != "" && issueKey != "ERROR" {
+			issueKeys = append(issueKeys, issueKey)
+		}
+	}
+
+	utils.LogInfo(utils.T("import.rank_start", len(issueKeys)))
+	if err := m.jiraClient.RankIssues(issueKeys); err != nil {
+		utils.LogWarn(utils.T("import.rank_failed", err))
+		return
+	}
+	utils.LogInfo(utils.T("import.rank_done"))
+}
+
 // processRecord は1つのレコードを処理しJIRAイシューを作成します
-func (m *MigrationService) processRecord(record models.CSVRecord) (string, error) {
+// 作成後の更新で失敗した項目は、フィールドIDまたは status/comment をキーとして返します
+// reused は DEDUP_ON_CREATE で既存のイシューを見つけ、作成しなかった場合に true になります
+func (m *MigrationService) processRecord(record models.CSVRecord, rowLog *utils.RowLogger) (string, bool, map[string]string, error) {
+	pivotalId := record["JIRA Issue ID"]
+
+	// 1. 作成画面で設定できるフィールドは作成時にまとめて設定する
+	req := m.newIssueRequest(record)
+	issueType := req.issueType
+
+	// DEDUP_ON_CREATE が有効な場合は、Pivotal IDのラベルが付いた既存イシューを作成前に探す
+	dedupLabel := ""
+	if m.config.DedupOnCreate {
+		dedupLabel = m.pivotalIDLabel(pivotalId)
+	}
+
+	// イシュー作成（作成画面にないフィールドは作成後の更新に回す）
+	issueKey, deferred, created, err := m.createIssue(req, dedupLabel, rowLog)
+	if api.IsSubtaskWithoutParent(err) {
+		return "", false, nil, fmt.Errorf("イシュー作成エラー: Pivotalの種別 '%s' がサブタスクのイシュータイプ '%s' に対応付けられています: %w",
+			record["Type"], issueType, err)
+	}
+	if err != nil {
+		return "", false, nil, fmt.Errorf("イシュー作成エラー: %w", err)
+	}
+
+	// 既存のイシューはステータスやコメントも反映済みのため、そのまま使う
+	if !created {
+		rowLog.Info(utils.T("import.issue_reused", issueKey, pivotalId))
+		return issueKey, true, nil, nil
+	}
+
+	// 以降の処理やCSVへの書き込みの前に、作成したことをジャーナルに残す
+	if err := m.journal.Record(pivotalId, issueKey); err != nil {
+		rowLog.Warn(utils.T("import.journal_failed", issueKey, err))
+	}
+
+	// 作成後にしか設定できない項目は個別に反映し、失敗した項目を記録する
+	fieldErrors := make(map[string]string)
+	if len(deferred) > 0 {
+		if err := m.jiraClient.UpdateIssue(issueKey, deferred); err != nil {
+			rowLog.Warn(utils.T("import.deferred_fields_failed", issueKey, err))
+			for id := range deferred {
+				fieldErrors[id] = err.Error()
+			}
+		}
+	}
+
+	// 作成済みのため、失敗した場合もキーを返してCSVに残す
+	err = m.completeIssue(record, issueKey, issueType, "", fieldErrors, rowLog)
+	return issueKey, false, fieldErrors, err
+}
+
+// resumeRecord は前回の実行でイシューを作成した後、STRICT_STATUS でステータスの更新に失敗した行について、
+// 作成済みのイシューに残りの処理（ステータスの遷移とコメントの追加）だけを行います
+func (m *MigrationService) resumeRecord(record models.CSVRecord, issueKey string, rowLog *utils.RowLogger) (map[string]string, error) {
+	rowLog.Info(utils.T("import.resume_row", issueKey))
+
+	// 前回の遷移の途中で止まっている場合もあるため、現在のステータスから遷移する
+	current, err := m.jiraClient.GetIssue(issueKey)
+	if err != nil {
+		return nil, fmt.Errorf("既存イシュー取得エラー: %w", err)
+	}
+
+	fieldErrors := make(map[string]string)
+	err = m.completeIssue(record, issueKey, resolveIssueType(m.config, record["Type"]), current.Status, fieldErrors, rowLog)
+	return fieldErrors, err
+}
+
+// completeIssue は作成したイシューに、作成APIでは設定できない項目（ステータスとコメント）を反映します
+// currentStatus は現在のステータスで、作成直後の場合は空文字です
+// 失敗した項目は fieldErrors に記録し、STRICT_STATUS でステータスの更新に失敗した場合のみエラーを返します
+func (m *MigrationService) completeIssue(record models.CSVRecord, issueKey, issueType, currentStatus string, fieldErrors map[string]string, rowLog *utils.RowLogger) error {
+	// 2. ステータスの更新（JIRAの作成APIではステータスを指定できないため遷移で反映）
+	if status := targetStatus(m.config, record); !m.config.SkipStatusUpdate && m.config.NeedsTransition(status) {
+		if err := m.jiraClient.UpdateStatusFrom(issueKey, issueType, currentStatus, status); err != nil {
+			m.recordStatusUnchanged(record["JIRA Issue ID"], issueKey, status, err)
+			if m.config.StrictStatus {
+				return fmt.Errorf("ステータス更新エラー (%s は作成済み): %w", issueKey, err)
+			}
+			rowLog.Warn(utils.T("import.status_failed", issueKey, err))
+			fieldErrors["status"] = err.Error()
+		}
+	}
+
+	// 3. コメントの追加（作成APIでは指定できないため別途追加）
+	if comment := record["Comment"]; !m.config.SkipComments && comment != "" {
+		if err := m.addComments(issueKey, comment); err != nil {
+			rowLog.Warn(utils.T("import.comment_failed", issueKey, err))
+			fieldErrors["comment"] = err.Error()
+		} else {
+			rowLog.Info(utils.T("import.comment_added", issueKey))
+		}
+	}
+
+	return nil
+}
+
+// addComments は結合されたコメントをイシューに追加します
+// COMMENT_VISIBILITY を指定した場合は閲覧できる範囲を制限し、COMMENT_INTERNAL_MARKER も指定した場合はマーカーで始まるコメントのみを別のコメントとして制限します
+func (m *MigrationService) addComments(issueKey, comment string) error {
+	kind, name, _ := m.config.CommentRestriction()
+	if kind == "" {
+		return m.jiraClient.AddComment(issueKey, comment)
+	}
+
+	public, internal := splitInternalComments(comment, m.config.CommentInternalMarker)
+	if err := m.jiraClient.AddComment(issueKey, public); err != nil {
+		return err
+	}
+	return m.jiraClient.AddCommentWithVisibility(issueKey, internal, &api.CommentVisibility{Type: kind, Value: name})
+}
+
+// splitInternalComments は結合されたコメントを、公開するコメントと制限するコメント（marker で始まるもの。marker は取り除く）に分けます
+// marker が空の場合はすべてのコメントを制限します
+func splitInternalComments(comment, marker string) (public, internal string) {
+	if marker == "" {
+		return "", comment
+	}
+
+	var publicBodies, internalBodies []string
+	for _, body := range strings.Split(comment, commentSeparator) {
+		trimmed := strings.TrimSpace(body)
+		if rest, ok := strings.CutPrefix(trimmed, marker); ok {
+			internalBodies = append(internalBodies, strings.TrimSpace(rest))
+		} else if trimmed != "" {
+			publicBodies = append(publicBodies, body)
+		}
+	}
+	return strings.Join(publicBodies, commentSeparator), strings.Join(internalBodies, commentSeparator)
+}
+
+// issueRequest はレコードから組み立てたイシュー作成時の値です
+type issueRequest struct {
+	summary     string
+	description string
+	labels      []string
+	issueType   string
+	reporter    string
+	assignee    string
+	extraFields map[string]interface{}
+}
+
+// newIssueRequest はレコードからイシュー作成時の値を組み立てます
+func (m *MigrationService) newIssueRequest(record models.CSVRecord) issueRequest {
 	// 基本情報の取得
-	summary := record["Title"]
-	if summary == "" {
-		summary = "No Title"
+	title, _ := issueTitle(record)
+	pivotalID := record["JIRA Issue ID"]
+
+	return issueRequest{
+		summary:     buildSummary(m.config.SummaryPrefixFormat, pivotalID, title),
+		description: m.withMigrationFooter(m.buildDescription(record), pivotalID),
+		// ラベルの処理（JQLで検索できるようPivotal IDのラベルを付与）
+		labels:      m.withPivotalIDLabel(m.withGlobalLabels(parseLabels(record["Labels"])), pivotalID),
+		issueType:   resolveIssueType(m.config, record["Type"]),
+		reporter:    record["Reporter"],
+		assignee:    firstOwner(record["Assignee"]),
+		extraFields: m.createFields(record),
 	}
+}
 
-	pivotalId := record["JIRA Issue ID"]
-	summary = fmt.Sprintf("[%s] %s", pivotalId, summary)
+// PreviewCreatePayload はレコードからイシューを作成する際にJIRAへ送信するペイロードを返します
+// JIRAのAPIは呼び出さないため、マッピングの確認に使えます
+func (m *MigrationService) PreviewCreatePayload(record models.CSVRecord) (map[string]interface{}, error) {
+	req := m.newIssueRequest(record)
+	return m.jiraClient.BuildCreatePayload(req.summary, req.description, req.labels, req.issueType, req.reporter, req.assignee, req.extraFields)
+}
+
+// createFields は作成リクエストに含める追加フィールド（ストーリーポイント・修正バージョン・Pivotal ID）を返します
+func (m *MigrationService) createFields(record models.CSVRecord) map[string]interface{} {
+	fields := make(map[string]interface{})
+
+	// 検索やレポート用にPivotal IDを専用のフィールドにも保存する
+	if m.config.PivotalIDField != "" && record["JIRA Issue ID"] != "" {
+		fields[m.config.PivotalIDField] = record["JIRA Issue ID"]
+	}
+
+	// 小数のポイント (0.5 など) も数値のまま送信する
+	if sp, ok := parseStoryPoints(record["Story Points"]); ok && sp > 0 && !m.config.SkipStoryPoints {
+		fields[m.config.StoryPointField] = sp
+	}
+
+	// リリースの行から作成したバージョン
+	if version := record["Fix Version"]; version != "" {
+		fields["fixVersions"] = []map[string]string{{"name": version}}
+	}
+
+	for id, value := range m.originalDateFields(record) {
+		fields[id] = value
+	}
+
+	// マッピングにないレビュアーは説明文に追記する（buildDescription）
+	if reviewers, _ := m.mapReviewers(record); m.config.ReviewerField != "" && len(reviewers) > 0 {
+		fields[m.config.ReviewerField] = m.jiraClient.UserListField(reviewers)
+	}
+
+	return fields
+}
+
+// mapReviewers はレコードのレビュアーをJIRAのユーザーに変換し、マッピングにないレビュアーを unmapped に返します
+// JIRA_REVIEWER_FIELD が設定されていない場合はすべてのレビュアーを unmapped として返します
+func (m *MigrationService) mapReviewers(record models.CSVRecord) (users []string, unmapped []string) {
+	for _, reviewer := range parseUsers(record["Reviewers"]) {
+		if user, ok := api.MapUser(reviewer); ok && m.config.ReviewerField != "" {
+			users = append(users, user)
+		} else {
+			unmapped = append(unmapped, reviewer)
+		}
+	}
+	return users, unmapped
+}
+
+// firstOwner はカンマ区切りの担当者（MERGE_COLUMNS で結合した Owned By）から、JIRAの担当者にする最初の1人を返します
+// 2人目以降は buildDescription で説明文に追記します
+func firstOwner(owners string) string {
+	if users := parseUsers(owners); len(users) > 0 {
+		return users[0]
+	}
+	return ""
+}
+
+// parseUsers はカンマ区切りのユーザー名を分割します（空の要素は除きます）
+func parseUsers(usersStr string) []string {
+	var users []string
+	for _, user := range strings.Split(usersStr, ",") {
+		if user = strings.TrimSpace(user); user != "" {
+			users = append(users, user)
+		}
+	}
+	return users
+}
+
+// originalDateFields はPivotalでの作成日時・受け入れ日時と CUSTOM_DATE_FIELD_MAP の日時を、設定された日付フィールドの値として返します
+// 値が空（元の値が空または変換できなかった）のフィールドは含めません
+func (m *MigrationService) originalDateFields(record models.CSVRecord) map[string]interface{} {
+	dates := map[string]string{
+		m.config.OriginalCreatedField:  record["Created Date"],
+		m.config.OriginalResolvedField: record["Resolved Date"],
+	}
+	for column, id := range m.config.CustomDateFieldMap {
+		if value := record[column]; value != "" {
+			dates[id] = value
+		}
+	}
+
+	fields := make(map[string]interface{})
+	for id, value := range dates {
+		if id == "" || value == "" {
+			continue
+		}
+		fields[id] = m.formatDateField(id, value)
+	}
+	return fields
+}
+
+// formatDateField はCSVの日時をフィールドの型に合わせた形式にします（date の場合は日付のみ）
+func (m *MigrationService) formatDateField(fieldID, value string) string {
+	if m.dateFieldTypes[fieldID] != "date" {
+		return value
+	}
+	t, err := time.Parse(jiraDateTimeFormat, value)
+	if err != nil {
+		return value
+	}
+	return t.Format("2006-01-02")
+}
+
+// buildDescription はイシューの説明文を作成します
+// 元の作成日時・受け入れ日時は、保存先のフィールドが設定されていない場合に説明文の末尾へ追記します
+// レビュアーは、JIRA_REVIEWER_FIELD が設定されていないかマッピングにない場合に追記します
+// 担当者が複数の場合、JIRAの担当者にしない2人目以降を追記します
+func (m *MigrationService) buildDescription(record models.CSVRecord) string {
 	description := record["Description"]
 
-	// ラベルの処理
-	var labels []string
-	if labelsStr := record["Labels"]; labelsStr != "" {
-		labels = strings.Split(labelsStr, ",")
-		for i := range labels {
-			labels[i] = strings.TrimSpace(labels[i])
+	var notes []string
+	if created := record["Created Date"]; created != "" && m.config.OriginalCreatedField == "" {
+		notes = append(notes, utils.T("import.note_created", created))
+	}
+	if resolved := record["Resolved Date"]; resolved != "" && m.config.OriginalResolvedField == "" {
+		notes = append(notes, utils.T("import.note_resolved", resolved))
+	}
+	if _, unmapped := m.mapReviewers(record); len(unmapped) > 0 {
+		notes = append(notes, m.config.ReviewerNotePrefix+" "+strings.Join(unmapped, ", "))
+	}
+	if owners := parseUsers(record["Assignee"]); len(owners) > 1 {
+		notes = append(notes, utils.T("import.note_other_owners", strings.Join(owners[1:], ", ")))
+	}
+	if len(notes) == 0 {
+		return description
+	}
+
+	return strings.TrimSpace(description + "\n\n" + strings.Join(notes, "\n"))
+}
+
+// migrationFooterMark は移行フッターの先頭に付ける区切り線です
+const migrationFooterMark = "----\n"
+
+// withMigrationFooter は説明文の末尾に区切り線と MIGRATION_FOOTER を1回だけ追記します
+// 説明文が空の場合は区切り線とフッターのみを説明文とし、MIGRATION_FOOTER が空の場合はそのまま返します
+func (m *MigrationService) withMigrationFooter(description, pivotalID string) string {
+	if m.config.MigrationFooter == "" {
+		return description
+	}
+
+	footer := strings.NewReplacer(
+		"{date}", time.Now().Format("2006-01-02"),
+		"{version}", utils.Version,
+		"{id}", pivotalID,
+	).Replace(m.config.MigrationFooter)
+
+	description = strings.TrimSpace(description)
+	if strings.HasSuffix(description, migrationFooterMark+footer) {
+		return description
+	}
+	if description == "" {
+		return migrationFooterMark + footer
+	}
+	return description + "\n\n" + migrationFooterMark + footer
+}
+
+// stripMigrationFooter は既存イシューの説明文から移行フッターを取り除きます（差分確認用）
+// フッターには日付が含まれるため、最後の区切り線以降をフッターとみなします
+func (m *MigrationService) stripMigrationFooter(description string) string {
+	if m.config.MigrationFooter == "" {
+		return description
+	}
+	if strings.HasPrefix(description, migrationFooterMark) {
+		return ""
+	}
+	if i := strings.LastIndex(description, "\n\n"+migrationFooterMark); i >= 0 {
+		return description[:i]
+	}
+	return description
+}
+
+// createIssue は追加フィールドを含めてイシューを作成します
+// 作成画面にないなどの理由で追加フィールドだけが拒否された場合は、それらを除いて作り直し、
+// 作成後に更新すべきフィールドとして返します
+// dedupLabel を指定した場合は、そのラベルの既存イシューがあれば作成せずにキーを返します（戻り値の created が false）
+func (m *MigrationService) createIssue(req issueRequest, dedupLabel string, rowLog *utils.RowLogger) (string, map[string]interface{}, bool, error) {
+	extraFields := req.extraFields
+
+	var issueKey string
+	var err error
+	if dedupLabel != "" {
+		var created bool
+		issueKey, created, err = m.jiraClient.CreateIssueIfAbsent(dedupLabel, req.summary, req.description, req.labels, req.issueType, req.reporter, req.assignee, extraFields)
+		if err == nil {
+			return issueKey, nil, created, nil
+		}
+	} else {
+		issueKey, err = m.jiraClient.CreateIssueWithFields(req.summary, req.description, req.labels, req.issueType, req.reporter, req.assignee, extraFields)
+		if err == nil {
+			return issueKey, nil, true, nil
 		}
 	}
 
-	// 3. 担当者と報告者の処理
-    reporter := record["Reporter"]
-    assignee := record["Assignee"]
+	var apiErr *api.APIError
+	if !errors.As(err, &apiErr) || len(apiErr.Errors) == 0 {
+		return "", nil, false, err
+	}
+	for id := range apiErr.Errors {
+		if _, ok := extraFields[id]; !ok {
+			return "", nil, false, err
+		}
+	}
 
-	// イシュータイプの決定
-	issueType := "Task" // デフォルト
-	if recType, ok := record["Type"]; ok && recType != "" {
-		switch strings.ToLower(recType) {
-		case "bug":
-			issueType = "Bug"
-		case "feature", "story":
-			issueType = "feature"
-		case "chore":
-			issueType = "chore"
-		case "epic":
-			issueType = "Epic"
-		case "release":
-			issueType = "release"
+	deferred := make(map[string]interface{}, len(apiErr.Errors))
+	retryFields := make(map[string]interface{}, len(extraFields))
+	for id, value := range extraFields {
+		if _, rejected := apiErr.Errors[id]; rejected {
+			deferred[id] = value
+		} else {
+			retryFields[id] = value
 		}
 	}
 
-	// イシュー作成
-	issueKey, err := m.jiraClient.CreateIssue(summary, description, labels, issueType, reporter, assignee)
+	// 既存イシューの検索は済んでいるため、再試行では作成のみ行う
+	rowLog.Debug("作成時に設定できないフィールドを作成後に更新します: %v", err)
+	issueKey, err = m.jiraClient.CreateIssueWithFields(req.summary, req.description, req.labels, req.issueType, req.reporter, req.assignee, retryFields)
 	if err != nil {
-		return "", fmt.Errorf("イシュー作成エラー: %w", err)
+		return "", nil, false, err
 	}
+	return issueKey, deferred, true, nil
+}
+
+// updateRecord は作成済みのイシューをレコードの内容で更新します
+func (m *MigrationService) updateRecord(record models.CSVRecord, issueKey string, rowLog *utils.RowLogger) (string, map[string]string, error) {
+	title, _ := issueTitle(record)
 
-	// 1. ストーリーポイントの更新
-	if spStr := record["Story Points"]; spStr != "" {
-		sp := 0
-		fmt.Sscanf(spStr, "%d", &sp)
-		if sp > 0 {
-			if err := m.jiraClient.UpdateStoryPoints(issueKey, sp); err != nil {
-				utils.LogWarn("ストーリーポイント設定失敗 %s: %v", issueKey, err)
+	pivotalId := record["JIRA Issue ID"]
+	summary, description, _ := api.NormalizeSummary(buildSummary(m.config.SummaryPrefixFormat, pivotalId, title), m.buildDescription(record))
+
+	labels := m.withPivotalIDLabel(m.withGlobalLabels(parseLabels(record["Labels"])), pivotalId)
+	if labels == nil {
+		labels = []string{}
+	}
+
+	fields := map[string]interface{}{
+		"summary":     summary,
+		"description": m.withMigrationFooter(description, pivotalId),
+		"labels":      labels,
+	}
+	for id, value := range m.originalDateFields(record) {
+		fields[id] = value
+	}
+
+	if err := m.jiraClient.UpdateIssue(issueKey, fields); err != nil {
+		return "", nil, fmt.Errorf("イシュー更新エラー: %w", err)
+	}
+
+	// ステータスは現在の値から遷移させる
+	fieldErrors := make(map[string]string)
+	if status := targetStatus(m.config, record); !m.config.SkipStatusUpdate && m.config.NeedsTransition(status) {
+		current, err := m.jiraClient.GetIssue(issueKey)
+		if err != nil {
+			rowLog.Warn(utils.T("import.get_issue_failed", issueKey, err))
+		} else if err := m.jiraClient.UpdateStatusFrom(issueKey, resolveIssueType(m.config, record["Type"]), current.Status, status); err != nil {
+			m.recordStatusUnchanged(pivotalId, issueKey, status, err)
+			if m.config.StrictStatus {
+				return issueKey, fieldErrors, fmt.Errorf("ステータス更新エラー (%s は更新済み): %w", issueKey, err)
 			}
+			rowLog.Warn(utils.T("import.status_failed", issueKey, err))
+			fieldErrors["status"] = err.Error()
 		}
 	}
 
-	// 2. ステータスの更新
-	if status := record["JIRA Status"]; status != "" && status != "Backlog" {
-		if err := m.jiraClient.UpdateStatus(issueKey, status); err != nil {
-			utils.LogWarn("ステータス更新失敗 %s: %v", issueKey, err)
+	rowLog.Info(utils.T("import.issue_updated", issueKey))
+	return issueKey, fieldErrors, nil
+}
+
+// recordStatusUnchanged は目的のステータスに遷移できなかったイシューを集計に記録します
+func (m *MigrationService) recordStatusUnchanged(pivotalID, issueKey, targetStatus string, err error) {
+	m.summaryMutex.Lock()
+	defer m.summaryMutex.Unlock()
+
+	m.summary.StatusUnchanged = append(m.summary.StatusUnchanged, models.StatusUnchanged{
+		PivotalID:    pivotalID,
+		IssueKey:     issueKey,
+		TargetStatus: targetStatus,
+		Reason:       err.Error(),
+	})
+}
+
+// parseLabels はカンマ区切りのラベル文字列を分割します
+func parseLabels(labelsStr string) []string {
+	if labelsStr == "" {
+		return nil
+	}
+
+	var labels []string
+	for _, label := range strings.Split(labelsStr, ",") {
+		if label = sanitizeLabel(label); label != "" {
+			labels = append(labels, label)
 		}
 	}
+	return labels
+}
 
-	// 3. コメントの追加
-	if comment := record["Comment"]; comment != "" {
-		if err := m.jiraClient.AddComment(issueKey, comment); err != nil {
-			utils.LogWarn("コメント追加失敗 %s: %v", issueKey, err)
-		} else {
-			utils.LogInfo("コメントをイシュー %s に追加しました", issueKey)
+// sanitizeLabel はJIRAのラベルに使えない空白を取り除きます（途中の空白は "_" に置換）
+func sanitizeLabel(label string) string {
+	return strings.Join(strings.Fields(label), "_")
+}
+
+// withGlobalLabels はラベルの一覧に GLOBAL_LABELS のラベルを追加します
+func (m *MigrationService) withGlobalLabels(labels []string) []string {
+	return withGlobalLabels(m.config.GlobalLabels, labels)
+}
+
+// withGlobalLabels はラベルの一覧にすべてのイシューに共通のラベルを追加します
+// 共通のラベルは他のラベルと同じく空白を取り除き、すでに含まれるラベルは追加しません
+func withGlobalLabels(globalLabels, labels []string) []string {
+	for _, label := range globalLabels {
+		if label = sanitizeLabel(label); label != "" && !slices.Contains(labels, label) {
+			labels = append(labels, label)
+		}
+	}
+	return labels
+}
+
+// pivotalIDLabel はPivotal IDを検索するためのラベルを返します（無効な場合は空文字）
+func (m *MigrationService) pivotalIDLabel(pivotalID string) string {
+	return pivotalIDLabel(m.config.PivotalIDLabelPrefix, pivotalID)
+}
+
+// withPivotalIDLabel はラベルの一覧にPivotal IDのラベルを追加します
+func (m *MigrationService) withPivotalIDLabel(labels []string, pivotalID string) []string {
+	return withPivotalIDLabel(m.config.PivotalIDLabelPrefix, labels, pivotalID)
+}
+
+// pivotalIDLabel は接頭辞とPivotal IDから検索用のラベルを作成します
+func pivotalIDLabel(prefix, pivotalID string) string {
+	if prefix == "" || pivotalID == "" {
+		return ""
+	}
+	return sanitizeLabel(prefix + pivotalID)
+}
+
+// withPivotalIDLabel はラベルの一覧に接頭辞付きのPivotal IDのラベルを追加します
+func withPivotalIDLabel(prefix string, labels []string, pivotalID string) []string {
+	if idLabel := pivotalIDLabel(prefix, pivotalID); idLabel != "" && !slices.Contains(labels, idLabel) {
+		labels = append(labels, idLabel)
+	}
+	return labels
+}
+
+// resolveIssueType はPivotalのストーリー種別からJIRAのイシュータイプを決定します
+// 種別は大文字小文字を区別せず、TYPE_MAPPING_FILE、既定のマッピングの順に参照し、どちらにもない場合は DEFAULT_ISSUE_TYPE を使います
+func resolveIssueType(cfg *config.Config, pivotalType string) string {
+	if rule, ok := cfg.LookupIssueType(pivotalType); ok {
+		return rule.Type
+	}
+	if issueType, ok := issueTypeMapping[strings.ToLower(strings.TrimSpace(pivotalType))]; ok {
+		return issueType
+	}
+	return cfg.DefaultIssueType
+}
+
+// isMappedType はPivotalのストーリー種別がいずれかのマッピングにあるかを返します
+func isMappedType(cfg *config.Config, pivotalType string) bool {
+	if _, ok := cfg.LookupIssueType(pivotalType); ok {
+		return true
+	}
+	_, ok := issueTypeMapping[strings.ToLower(strings.TrimSpace(pivotalType))]
+	return ok
+}
+
+// targetStatus はレコードのイシューを遷移させるJIRAのステータスを返します
+// TYPE_MAPPING_FILE でストーリー種別にステータスを指定している場合は、変換済みCSVの JIRA Status よりそちらを優先します
+func targetStatus(cfg *config.Config, record models.CSVRecord) string {
+	if rule, ok := cfg.LookupIssueType(record["Type"]); ok && rule.Status != "" {
+		return rule.Status
+	}
+	return record["JIRA Status"]
+}
+
+// issueTypeMapping はPivotalのストーリー種別（小文字）からJIRAのイシュータイプへのマッピングです
+// JIRAのデフォルトのイシュータイプスキームにある名前（config.StandardIssueTypes）を使います
+var issueTypeMapping = map[string]string{
+	"bug":     "Bug",
+	"feature": "Story",
+	"story":   "Story",
+	"chore":   "Task",
+	"epic":    "Epic",
+	"release": "Task",
+}
+
+// MappedIssueTypes はインポートで使用する可能性のあるJIRAのイシュータイプを名前順で返します
+// RELEASES_AS_VERSIONS が有効な場合、リリースはイシューとして作成しないため含めません
+func MappedIssueTypes(cfg *config.Config) []string {
+	seen := map[string]bool{cfg.DefaultIssueType: true}
+	types := []string{cfg.DefaultIssueType}
+	mapping := maps.Clone(issueTypeMapping)
+	for pivotalType, rule := range cfg.IssueTypeMapping {
+		mapping[pivotalType] = rule.Type
+	}
+	for pivotalType, issueType := range mapping {
+		if seen[issueType] || (pivotalType == "release" && cfg.ReleasesAsVersions) {
+			continue
 		}
+		seen[issueType] = true
+		types = append(types, issueType)
 	}
+	slices.Sort(types)
+	return types
+}
 
-	return issueKey, nil
+// emptyTitle はタイトルが空の行に使うタイトルです
+const emptyTitle = "No Title"
+
+// issueTitle はレコードのタイトルを返します
+// 空（空白のみを含む）の場合は "No Title" と true を返し、インポートは続行できるようにします
+func issueTitle(record models.CSVRecord) (string, bool) {
+	if title := record["Title"]; strings.TrimSpace(title) != "" {
+		return title, false
+	}
+	return emptyTitle, true
+}
+
+// buildSummary はテンプレートからサマリーを作成します
+func buildSummary(format, pivotalID, title string) string {
+	return strings.NewReplacer("{id}", pivotalID, "{title}", title).Replace(format)
 }
 
 // UploadAttachments は添付ファイルをアップロードします
 func (m *MigrationService) UploadAttachments() error {
+	_, err := m.UploadAttachmentsWithResult()
+	return err
+}
+
+// UploadAttachmentsWithResult は添付ファイルをアップロードし、ファイルごとの結果を返します
+func (m *MigrationService) UploadAttachmentsWithResult() (*models.AttachmentResult, error) {
 	startTime := time.Now()
 	defer utils.TrackTime(startTime, "添付ファイルアップロード")
 
-	// イシューマッピングを読み込む
-	issueMapping, err := m.csvProc.LoadIssueMapping()
-	if err != nil {
-		return fmt.Errorf("イシューマッピング読み込みエラー: %w", err)
+	// イシューマッピングを読み込む（同じ実行でインポート済みの場合はその結果を使う）
+	issueMapping := m.issueMapping
+	if issueMapping == nil {
+		var err error
+		issueMapping, err = m.csvProc.LoadIssueMapping()
+		if err != nil {
+			return nil, fmt.Errorf("イシューマッピング読み込みエラー: %w", err)
+		}
 	}
 
-	// 添付ファイルフォルダの確認
-	attachmentsFolder := m.config.AttachmentsFolder
-	if _, err := os.Stat(attachmentsFolder); os.IsNotExist(err) {
-		return fmt.Errorf("添付ファイルフォルダが見つかりません: %s", attachmentsFolder)
+	// 添付ファイルフォルダの確認（カンマ区切りやグロブで複数指定可）
+	folders, err := m.config.AttachmentFolders()
+	if err != nil {
+		return nil, err
+	}
+	if len(folders) == 0 {
+		return nil, fmt.Errorf("添付ファイルフォルダが見つかりません: %s", m.config.AttachmentsFolder)
 	}
 
-	utils.LogInfo("添付ファイルのアップロードを開始します: フォルダ=%s", attachmentsFolder)
+	utils.LogInfo(utils.T("attachments.start_folder", strings.Join(folders, ", ")))
 
 	// セマフォとしてのチャネル（並列数を制限）
 	semaphore := make(chan struct{}, m.config.MaxConcurrent)
@@ -242,70 +1262,125 @@ func (m *MigrationService) UploadAttachments() error {
 	// 待機グループ
 	var wg sync.WaitGroup
 
-	// カウンター用の変数
-	totalFiles := 0
-	uploadedFiles := 0
-	failedFiles := 0
-	var countMutex sync.Mutex
+	// カウンター用の変数（ホットパスでロックを取らないようアトミックに更新）
+	var totalFiles, uploadedFiles, failedFiles atomic.Int64
+	result := &models.AttachmentResult{}
+	var resultMutex sync.Mutex // result.Files への追加のみを保護
 
-	// サブフォルダ（Pivotal ID）をスキャン
-	entries, err := os.ReadDir(attachmentsFolder)
+	// サブフォルダ（Pivotal ID）をスキャンし、複数のフォルダにある同じPivotal IDのファイルをまとめる
+	filesByID, pivotalIDs, err := collectAttachmentFiles(folders)
 	if err != nil {
-		return fmt.Errorf("フォルダ読み取りエラー: %w", err)
+		return nil, err
+	}
+
+	// 再実行時に同じファイルを重複して添付しないよう、添付済みのファイルを先に取得する
+	var existing map[string][]models.JiraAttachment
+	if m.config.SkipExistingAttachments {
+		existing = m.fetchExistingAttachments(issueMapping, pivotalIDs)
 	}
 
-	for _, entry := range entries {
-		if !entry.IsDir() {
-			continue // ファイルはスキップ
+	for _, pivotalID := range pivotalIDs {
+		if m.jiraClient.Stopped() != nil {
+			break
 		}
 
-		pivotalID := entry.Name()
 		issueKey, ok := issueMapping[pivotalID]
 		if !ok || issueKey == "ERROR" {
-			utils.LogWarn("Pivotal ID %s に対応するJIRAイシューが見つかりません", pivotalID)
+			utils.LogWarn(utils.T("attachments.issue_not_found", pivotalID))
+			result.SkippedPivotalIDs = append(result.SkippedPivotalIDs, pivotalID)
 			continue
 		}
 
-		// サブフォルダ内のファイルをスキャン
-		issueFolder := filepath.Join(attachmentsFolder, pivotalID)
-		files, err := os.ReadDir(issueFolder)
-		if err != nil {
-			utils.LogError("フォルダ %s の読み取りエラー: %v", issueFolder, err)
-			continue
+		filePaths := filesByID[pivotalID]
+		fileNames := make([]string, len(filePaths))
+		for i, filePath := range filePaths {
+			fileNames[i] = filepath.Base(filePath)
 		}
 
-		for _, file := range files {
-			if file.IsDir() {
-				continue // サブフォルダはスキップ
+		// JIRAで扱えない文字を置き換え、同じイシュー内で名前が重複しないようにする
+		uploadNames := uploadFileNames(fileNames)
+
+		for i, filePath := range filePaths {
+			totalFiles.Add(1)
+			if uploadNames[i] != fileNames[i] {
+				utils.LogInfo(utils.T("attachments.renamed", filePath, uploadNames[i]))
 			}
+		}
 
-			countMutex.Lock()
-			totalFiles++
-			countMutex.Unlock()
+		if attached := attachedFiles(existing[issueKey], filePaths, uploadNames, m.config.AttachmentMatchSize); len(attached) > 0 {
+			var pendingPaths, pendingNames []string
+			var skipped []models.AttachmentFileResult
+			for i, filePath := range filePaths {
+				if !attached[i] {
+					pendingPaths = append(pendingPaths, filePath)
+					pendingNames = append(pendingNames, uploadNames[i])
+					continue
+				}
+				utils.LogInfo(utils.T("attachments.skipped_existing", filePath, issueKey))
+				fileResult := models.AttachmentFileResult{PivotalID: pivotalID, IssueKey: issueKey, Path: filePath, Skipped: true}
+				if uploadNames[i] != filepath.Base(filePath) {
+					fileResult.UploadedName = uploadNames[i]
+				}
+				skipped = append(skipped, fileResult)
+			}
+			filePaths, uploadNames = pendingPaths, pendingNames
 
-			filePath := filepath.Join(issueFolder, file.Name())
+			resultMutex.Lock()
+			result.Files = append(result.Files, skipped...)
+			result.Skipped += len(skipped)
+			resultMutex.Unlock()
+		}
+
+		// 合計サイズが ATTACHMENT_BATCH_MB 以下のファイルは1回のリクエストでまとめてアップロードする
+		for _, batch := range attachmentBatches(filePaths, int64(m.config.AttachmentBatchMB)<<20) {
+			if m.jiraClient.Stopped() != nil {
+				break
+			}
+			batchPaths := make([]string, len(batch))
+			batchNames := make([]string, len(batch))
+			for j, index := range batch {
+				batchPaths[j] = filePaths[index]
+				batchNames[j] = uploadNames[index]
+			}
 
 			wg.Add(1)
 			semaphore <- struct{}{} // セマフォ取得
 
-			go func(fPath, iKey string) {
+			go func(pID, iKey string, fPaths, fNames []string) {
 				defer wg.Done()
 				defer func() { <-semaphore }() // セマフォ解放
 
 				// 添付ファイルのアップロード
-				err := m.jiraClient.UploadAttachment(iKey, fPath)
-
-				countMutex.Lock()
-				defer countMutex.Unlock()
-
-				if err != nil {
-					utils.LogError("ファイル %s のアップロード失敗: %v", fPath, err)
-					failedFiles++
-				} else {
-					utils.LogInfo("ファイル %s をイシュー %s にアップロードしました", filepath.Base(fPath), iKey)
-					uploadedFiles++
+				uploaded, err := m.jiraClient.UploadAttachmentsAs(iKey, fPaths, fNames)
+
+				fileResults := make([]models.AttachmentFileResult, len(fPaths))
+				for j, fPath := range fPaths {
+					fName := fNames[j]
+					fileResult := models.AttachmentFileResult{PivotalID: pID, IssueKey: iKey, Path: fPath}
+					if fName != filepath.Base(fPath) {
+						fileResult.UploadedName = fName
+					}
+					if !slices.Contains(uploaded, fName) {
+						fileErr := err
+						if fileErr == nil {
+							fileErr = fmt.Errorf("添付ファイル %s がアップロードされませんでした", fName)
+						}
+						fileResult.Error = fileErr.Error()
+						utils.LogError(utils.T("attachments.upload_failed", fPath, fileErr))
+						failedFiles.Add(1)
+						m.progress.OnAttachmentFailed(fileResult, fileErr)
+					} else {
+						utils.LogInfo(utils.T("attachments.uploaded", fName, iKey))
+						uploadedFiles.Add(1)
+						m.progress.OnAttachmentUploaded(fileResult)
+					}
+					fileResults[j] = fileResult
 				}
-			}(filePath, issueKey)
+
+				resultMutex.Lock()
+				result.Files = append(result.Files, fileResults...)
+				resultMutex.Unlock()
+			}(pivotalID, issueKey, batchPaths, batchNames)
 		}
 	}
 
@@ -313,28 +1388,99 @@ func (m *MigrationService) UploadAttachments() error {
 	wg.Wait()
 	close(semaphore)
 
-	utils.LogInfo("添付ファイルのアップロードが完了しました: 合計=%d, 成功=%d, 失敗=%d",
-		totalFiles, uploadedFiles, failedFiles)
+	result.Uploaded = int(uploadedFiles.Load())
+	result.Failed = int(failedFiles.Load())
+	m.summary.AttachmentsUploaded += result.Uploaded
+	m.summary.AttachmentsFailed += result.Failed
+	m.summary.AttachmentsSkipped += result.Skipped
 
-	return nil
+	utils.LogInfo(utils.T("attachments.done",
+		totalFiles.Load(), result.Uploaded, result.Failed, result.Skipped))
+
+	// 期限などで止まった場合はアップロードしたファイルの結果を返して中断する
+	if stopErr := m.jiraClient.Stopped(); stopErr != nil {
+		utils.LogError(utils.T("attachments.stopped", stopErr))
+		return result, stopErr
+	}
+
+	return result, nil
+}
+
+// fetchExistingAttachments は添付ファイルのあるイシューについて、添付済みのファイルを並列に取得します
+// 取得に失敗したイシューは警告を出して結果に含めません（すべてのファイルをアップロードします）
+func (m *MigrationService) fetchExistingAttachments(issueMapping models.IssueMapping, pivotalIDs []string) map[string][]models.JiraAttachment {
+	existing := make(map[string][]models.JiraAttachment)
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	semaphore := make(chan struct{}, m.config.MaxConcurrent)
+
+	for _, pivotalID := range pivotalIDs {
+		issueKey, ok := issueMapping[pivotalID]
+		if !ok || issueKey == "ERROR" {
+			continue
+		}
+
+		wg.Add(1)
+		semaphore <- struct{}{}
+		go func() {
+			defer wg.Done()
+			defer func() { <-semaphore }()
+
+			attachments, err := m.jiraClient.GetAttachments(issueKey)
+			if err != nil {
+				utils.LogWarn(utils.T("attachments.existing_failed", issueKey, err))
+				return
+			}
+			mu.Lock()
+			existing[issueKey] = attachments
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+
+	return existing
 }
 
 // RunMigration は移行処理全体を実行します
-func (m *MigrationService) RunMigration(convertOnly, importOnly, attachmentsOnly bool) error {
+// 処理結果の集計は成否にかかわらず SUMMARY_JSON に書き出します
+func (m *MigrationService) RunMigration(convertOnly, importOnly, attachmentsOnly bool) (err error) {
 	startTime := time.Now()
 	defer utils.TrackTime(startTime, "移行処理全体")
 
+	m.summary.StartedAt = startTime
+	defer func() {
+		m.summary.FinishedAt = time.Now()
+		m.summary.TotalSeconds = m.summary.FinishedAt.Sub(startTime).Seconds()
+		m.summary.HTTPSeconds = m.jiraClient.HTTPTime().Seconds()
+		m.summary.Success = err == nil
+		if err != nil {
+			m.summary.Error = err.Error()
+		}
+		m.summary.Retries = m.jiraClient.RetryCount()
+		switch {
+		case errors.Is(err, api.ErrDeadlineExceeded):
+			m.summary.TerminatedBy = "deadline"
+		case errors.Is(err, api.ErrRetryBudgetExhausted):
+			m.summary.TerminatedBy = "retry_budget"
+		}
+		m.logTimings()
+		if writeErr := m.writeSummary(); writeErr != nil {
+			utils.LogWarn(utils.T("migration.summary_write_failed", writeErr))
+		}
+	}()
+
 	// JIRA認証チェック
 	if err := m.jiraClient.CheckAuth(); err != nil {
+		utils.LogError(api.AuthErrorHint(err))
 		return fmt.Errorf("JIRA認証エラー: %w", err)
 	}
 
-	utils.LogInfo("JIRA認証成功")
+	utils.LogInfo(utils.T("auth.success"))
 
 	// 全処理またはCSV変換のみ
 	if !importOnly && !attachmentsOnly {
-		utils.LogInfo("CSVデータの変換を開始します")
-		if err := m.ConvertCSV(); err != nil {
+		utils.LogInfo(utils.T("convert.start"))
+		if err := m.runPhase("convert", m.ConvertCSV); err != nil {
 			return err
 		}
 	}
@@ -346,20 +1492,80 @@ func (m *MigrationService) RunMigration(convertOnly, importOnly, attachmentsOnly
 
 	// 全処理またはイシューインポートのみ
 	if !attachmentsOnly {
-		utils.LogInfo("JIRAイシューのインポートを開始します")
-		if err := m.ImportIssues(); err != nil {
+		utils.LogInfo(utils.T("import.start"))
+		if err := m.runPhase("import", m.ImportIssues); err != nil {
 			return err
 		}
 	}
 
 	// 全処理または添付ファイルアップロードのみ
 	if !importOnly || attachmentsOnly {
-		utils.LogInfo("添付ファイルのアップロードを開始します")
-		if err := m.UploadAttachments(); err != nil {
+		utils.LogInfo(utils.T("attachments.start"))
+		if err := m.runPhase("attachments", m.UploadAttachments); err != nil {
 			return err
 		}
 	}
 
-	utils.LogInfo("移行処理が完了しました")
+	utils.LogInfo(utils.T("migration.done"))
+	return nil
+}
+
+// runPhase は処理を実行し、所要時間とそのうちJIRAへのリクエストにかかった時間を集計に記録します
+func (m *MigrationService) runPhase(name string, phase func() error) error {
+	m.progress.OnPhaseStart(name)
+	start := time.Now()
+	httpStart := m.jiraClient.HTTPTime()
+	err := phase()
+	elapsed := time.Since(start)
+	m.summary.PhaseSeconds[name] = elapsed.Seconds()
+	m.summary.PhaseHTTPSeconds[name] = (m.jiraClient.HTTPTime() - httpStart).Seconds()
+	m.progress.OnPhaseEnd(name, elapsed, err)
+	return err
+}
+
+// migrationPhases は所要時間の表に出力するフェーズの順序です
+var migrationPhases = []string{"convert", "import", "attachments"}
+
+// logTimings はフェーズごとの所要時間とJIRAへのリクエスト時間を1つの表として出力します
+func (m *MigrationService) logTimings() {
+	utils.LogInfo(utils.T("migration.timing_header"))
+	utils.LogInfo("  %-12s %12s %12s", utils.T("migration.timing_phase"), utils.T("migration.timing_elapsed"), utils.T("migration.timing_http"))
+	for _, phase := range migrationPhases {
+		elapsed, ok := m.summary.PhaseSeconds[phase]
+		if !ok {
+			continue
+		}
+		utils.LogInfo("  %-12s %11.1fs %11.1fs", phase, elapsed, m.summary.PhaseHTTPSeconds[phase])
+	}
+	utils.LogInfo("  %-12s %11.1fs %11.1fs", "total", m.summary.TotalSeconds, m.summary.HTTPSeconds)
+	utils.LogInfo(utils.T("migration.timing_note"))
+}
+
+// writeSummary は処理結果の集計をJSONファイルに書き出します
+func (m *MigrationService) writeSummary() error {
+	if m.config.SummaryJSON == "" {
+		return nil
+	}
+
+	m.summary.Config = models.SummaryConfig{
+		JiraURL:           m.config.JiraURL,
+		JiraEmail:         m.config.JiraEmail,
+		JiraProjectKey:    m.config.JiraProjectKey,
+		PivotalCSV:        m.config.PivotalCSV,
+		JiraCSV:           m.config.JiraCSV,
+		AttachmentsFolder: m.config.AttachmentsFolder,
+		MaxConcurrent:     m.config.MaxConcurrent,
+	}
+
+	data, err := json.MarshalIndent(m.summary, "", "  ")
+	if err != nil {
+		return fmt.Errorf("JSONエンコードエラー: %w", err)
+	}
+
+	if err := os.WriteFile(m.config.SummaryJSON, data, 0644); err != nil {
+		return fmt.Errorf("ファイル書き込みエラー: %w", err)
+	}
+
+	utils.LogInfo(utils.T("migration.summary_written", m.config.SummaryJSON))
 	return nil
 }
@@ -0,0 +1,1176 @@
+package services
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"pivotaltojira/api"
+	"pivotaltojira/config"
+	"pivotaltojira/models"
+	"pivotaltojira/utils"
+)
+
+// fakeJira はインポートで使うJIRA APIの最小限の動作を再現するテスト用のサーバーです
+// 作成したイシューは "To Do" から始まり、workflow の遷移に従ってステータスが変わります
+type fakeJira struct {
+	mu       sync.Mutex
+	issues   map[string]*fakeIssue
+	order    []string                     // 作成した順のイシューキー
+	workflow map[string]map[string]string // ステータス → 遷移先ステータス → トランジションID
+	labelled map[string]string            // 検索で見つかる既存イシュー（ラベル → キー）
+	requests []string                     // "METHOD パス" の一覧
+	created  chan string                  // 作成したイシューのサマリー（nil の場合は通知しない）
+	createFn func(fields map[string]json.RawMessage) (int, string)
+}
+
+// fakeIssue は fakeJira のイシューです
+type fakeIssue struct {
+	Key      string
+	Fields   map[string]json.RawMessage
+	Status   string
+	Comments []string
+}
+
+func newFakeJira() *fakeJira {
+	return &fakeJira{
+		issues: make(map[string]*fakeIssue),
+		workflow: map[string]map[string]string{
+			"To Do":       {"In Progress": "21"},
+			"In Progress": {"To Do": "11", "Done": "31"},
+			"Done":        {"In Progress": "41"},
+		},
+		labelled: make(map[string]string),
+	}
+}
+
+func (f *fakeJira) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	body, _ := io.ReadAll(req.Body)
+	path := strings.TrimPrefix(req.URL.Path, "/rest/api/2")
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.requests = append(f.requests, req.Method+" "+path)
+
+	parts := strings.Split(strings.Trim(path, "/"), "/")
+	switch {
+	case req.Method == "GET" && path == "/myself":
+		writeJSON(w, http.StatusOK, map[string]string{"accountId": "tester", "displayName": "Tester"})
+
+	case req.Method == "GET" && path == "/project/TEST":
+		writeJSON(w, http.StatusOK, map[string]string{"key": "TEST"})
+
+	case req.Method == "GET" && path == "/field":
+		writeJSON(w, http.StatusOK, []interface{}{})
+
+	case req.Method == "GET" && path == "/search":
+		var issues []interface{}
+		for label, key := range f.labelled {
+			if strings.Contains(req.URL.Query().Get("jql"), `labels = "`+label+`"`) {
+				issues = append(issues, map[string]interface{}{"key": key, "fields": map[string]interface{}{}})
+			}
+		}
+		writeJSON(w, http.StatusOK, map[string]interface{}{"issues": issues})
+
+	case req.Method == "POST" && path == "/issue":
+		var payload struct {
+			Fields map[string]json.RawMessage `json:"fields"`
+		}
+		json.Unmarshal(body, &payload)
+		if f.createFn != nil {
+			if status, resp := f.createFn(payload.Fields); status != http.StatusCreated {
+				writeRaw(w, status, resp)
+				return
+			}
+		}
+		key := f.addIssue(payload.Fields)
+		if f.created != nil {
+			var summary string
+			json.Unmarshal(payload.Fields["summary"], &summary)
+			f.created <- summary
+		}
+		writeJSON(w, http.StatusCreated, map[string]string{"key": key})
+
+	case len(parts) >= 2 && parts[0] == "issue" && f.issues[parts[1]] != nil:
+		f.serveIssue(w, req, f.issues[parts[1]], parts[2:], body)
+
+	default:
+		http.NotFound(w, req)
+	}
+}
+
+// serveIssue は /issue/{key} 以下のリクエストを処理します
+func (f *fakeJira) serveIssue(w http.ResponseWriter, req *http.Request, issue *fakeIssue, sub []string, body []byte) {
+	switch {
+	case req.Method == "GET" && len(sub) == 0:
+		fields := map[string]interface{}{"status": map[string]string{"name": issue.Status}}
+		for id, value := range issue.Fields {
+			fields[id] = value
+		}
+		writeJSON(w, http.StatusOK, map[string]interface{}{"key": issue.Key, "fields": fields})
+
+	case req.Method == "PUT" && len(sub) == 0:
+		var payload struct {
+			Fields map[string]json.RawMessage `json:"fields"`
+		}
+		json.Unmarshal(body, &payload)
+		if issue.Fields == nil {
+			issue.Fields = make(map[string]json.RawMessage)
+		}
+		for id, value := range payload.Fields {
+			issue.Fields[id] = value
+		}
+		w.WriteHeader(http.StatusNoContent)
+
+	case req.Method == "GET" && len(sub) == 1 && sub[0] == "transitions":
+		var transitions []interface{}
+		for to, id := range f.workflow[issue.Status] {
+			transitions = append(transitions, map[string]interface{}{"id": id, "to": map[string]string{"name": to}})
+		}
+		writeJSON(w, http.StatusOK, map[string]interface{}{"transitions": transitions})
+
+	case req.Method == "POST" && len(sub) == 1 && sub[0] == "transitions":
+		var payload struct {
+			Transition struct {
+				ID string `json:"id"`
+			} `json:"transition"`
+		}
+		json.Unmarshal(body, &payload)
+		for to, id := range f.workflow[issue.Status] {
+			if id == payload.Transition.ID {
+				issue.Status = to
+				w.WriteHeader(http.StatusNoContent)
+				return
+			}
+		}
+		writeRaw(w, http.StatusBadRequest, `{"errorMessages":["Invalid transition"]}`)
+
+	case req.Method == "POST" && len(sub) == 1 && sub[0] == "comment":
+		issue.Comments = append(issue.Comments, string(body))
+		writeJSON(w, http.StatusCreated, map[string]string{"id": "1"})
+
+	default:
+		http.NotFound(w, req)
+	}
+}
+
+// addIssue は "To Do" のイシューを追加してキーを返します（呼び出し側でロックを取得します）
+func (f *fakeJira) addIssue(fields map[string]json.RawMessage) string {
+	key := fmt.Sprintf("TEST-%d", len(f.issues)+1)
+	f.issues[key] = &fakeIssue{Key: key, Fields: fields, Status: "To Do"}
+	f.order = append(f.order, key)
+	return key
+}
+
+func writeJSON(w http.ResponseWriter, status int, value interface{}) {
+	body, _ := json.Marshal(value)
+	writeRaw(w, status, string(body))
+}
+
+func writeRaw(w http.ResponseWriter, status int, body string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	io.WriteString(w, body)
+}
+
+// countRequests は "METHOD パス" が request と一致するリクエストの数を返します
+func (f *fakeJira) countRequests(request string) int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	n := 0
+	for _, r := range f.requests {
+		if r == request {
+			n++
+		}
+	}
+	return n
+}
+
+func (f *fakeJira) issue(key string) *fakeIssue {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.issues[key]
+}
+
+// newTestConfig はテスト用の設定を環境変数から読み込みます
+// 出力ファイルは一時ディレクトリに作成し、env の値で環境変数を上書きします
+func newTestConfig(t testing.TB, jiraURL string, env map[string]string) *config.Config {
+	t.Helper()
+
+	dir := t.TempDir()
+	defaults := map[string]string{
+		"JIRA_URL":         jiraURL,
+		"JIRA_EMAIL":       "tester@example.com",
+		"JIRA_API_TOKEN":   "test-api-token",
+		"JIRA_PROJECT_KEY": "TEST",
+		"PIVOTAL_CSV":      filepath.Join(dir, "pivotal.csv"),
+		"JIRA_CSV":         filepath.Join(dir, "jira.csv"),
+		"MAPPING_JSON":     filepath.Join(dir, "id_mapping.json"),
+		"SUMMARY_JSON":     filepath.Join(dir, "migration_summary.json"),
+		"IMPORT_JOURNAL":   "",
+		"MAX_CONCURRENT":   "4",
+	}
+	for key, value := range defaults {
+		t.Setenv(key, value)
+	}
+	for key, value := range env {
+		t.Setenv(key, value)
+	}
+
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		t.Fatalf("設定の読み込みに失敗しました: %v", err)
+	}
+	return cfg
+}
+
+// newTestService は fake に接続する MigrationService を作成します
+func newTestService(t testing.TB, fake http.Handler, env map[string]string) *MigrationService {
+	t.Helper()
+
+	srv := httptest.NewServer(fake)
+	t.Cleanup(srv.Close)
+
+	cfg := newTestConfig(t, srv.URL, env)
+	client := api.NewJiraClientWithClient(cfg, srv.Client())
+	return NewMigrationService(cfg, client, NewCSVProcessor(cfg))
+}
+
+// restartService は同じ設定・接続先で MigrationService を作成し直します（再実行の代わり）
+func restartService(m *MigrationService) *MigrationService {
+	return NewMigrationService(m.config, api.NewJiraClientWithClient(m.config, &http.Client{}), m.csvProc)
+}
+
+// writeJiraCSV は records を JIRA_CSV に書き出します
+func writeJiraCSV(t testing.TB, m *MigrationService, records []models.CSVRecord) {
+	t.Helper()
+	if err := m.csvProc.WriteJiraCSV(records); err != nil {
+		t.Fatalf("JIRA CSVを書き出せません: %v", err)
+	}
+}
+
+// readCSVFile は CSV ファイルをヘッダー名→値のマップの一覧として読み込みます
+func readCSVFile(t *testing.T, path string) []map[string]string {
+	t.Helper()
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	reader := csv.NewReader(file)
+	reader.FieldsPerRecord = -1
+	rows, err := reader.ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var records []map[string]string
+	for _, row := range rows[1:] {
+		record := make(map[string]string)
+		for i, header := range rows[0] {
+			if i < len(row) {
+				record[header] = row[i]
+			}
+		}
+		records = append(records, record)
+	}
+	return records
+}
+
+// jiraRecord はJIRA CSVの1行を作成します
+func jiraRecord(pivotalID, title, issueType, status string) models.CSVRecord {
+	return models.CSVRecord{
+		"JIRA Issue ID": pivotalID,
+		"Title":         title,
+		"Type":          issueType,
+		"JIRA Status":   status,
+		"Pivotal State": "",
+	}
+}
+
+func TestStrictStatusKeepsIssueKeyAndResumes(t *testing.T) {
+	fake := newFakeJira()
+	// Done へ遷移できないワークフロー
+	fake.workflow["In Progress"] = map[string]string{"To Do": "11"}
+	m := newTestService(t, fake, map[string]string{"STRICT_STATUS": "true"})
+	record := jiraRecord("100", "ステータスを更新できないストーリー", "feature", "Done")
+	record["Comment"] = "最初のコメント"
+	writeJiraCSV(t, m, []models.CSVRecord{record})
+
+	result, err := m.ImportIssuesWithResult()
+	if err != nil {
+		t.Fatalf("ImportIssuesWithResult がエラーを返しました: %v", err)
+	}
+	if result.Failed != 1 || result.Rows[0].IssueKey != "TEST-1" {
+		t.Fatalf("結果 = %+v, want 失敗1件・キー TEST-1", result.Rows)
+	}
+
+	// 作成済みのイシューのキーをエラーフラグと一緒にCSVに残す
+	rows := readCSVFile(t, m.config.JiraCSV)
+	if rows[0]["JIRA Issue Key"] != "TEST-1" || rows[0]["Error"] != "1" {
+		t.Fatalf("CSV の行 = %v, want JIRA Issue Key=TEST-1, Error=1", rows[0])
+	}
+
+	// ワークフローを直して再実行すると、作成し直さずに既存のイシューを遷移させる
+	fake.mu.Lock()
+	fake.workflow["In Progress"]["Done"] = "31"
+	fake.mu.Unlock()
+	m = restartService(m)
+
+	result, err = m.ImportIssuesWithResult()
+	if err != nil {
+		t.Fatalf("再実行で ImportIssuesWithResult がエラーを返しました: %v", err)
+	}
+	if result.Failed != 0 {
+		t.Fatalf("再実行の結果 = %+v, want 失敗なし", result.Rows)
+	}
+	if got := fake.countRequests("POST /issue"); got != 1 {
+		t.Errorf("イシューの作成回数 = %d, want 1（再実行で作成し直しています）", got)
+	}
+	if issue := fake.issue("TEST-1"); issue.Status != "Done" || len(issue.Comments) != 1 {
+		t.Errorf("TEST-1 のステータス = %q, コメント数 = %d, want Done, 1", issue.Status, len(issue.Comments))
+	}
+	rows = readCSVFile(t, m.config.JiraCSV)
+	if rows[0]["JIRA Issue Key"] != "TEST-1" || rows[0]["Error"] != "0" {
+		t.Errorf("再実行後の CSV の行 = %v, want JIRA Issue Key=TEST-1, Error=0", rows[0])
+	}
+}
+
+func TestImportCountsRowsByResult(t *testing.T) {
+	fake := newFakeJira()
+	fake.addIssue(nil) // TEST-1: 更新する既存イシュー
+	m := newTestService(t, fake, map[string]string{"UPDATE_EXISTING": "true", "MAX_CONCURRENT": "1"})
+
+	existing := jiraRecord("100", "既存のストーリー", "feature", "Backlog")
+	existing["JIRA Issue Key"] = "TEST-1"
+	// 同じPivotal IDの行が2行ある場合もそれぞれ作成した行として数える
+	duplicate1 := jiraRecord("200", "重複したID (1)", "feature", "Backlog")
+	duplicate2 := jiraRecord("200", "重複したID (2)", "feature", "Backlog")
+	invalid := jiraRecord("300", "作成に失敗する", "feature", "Backlog")
+	fake.createFn = func(fields map[string]json.RawMessage) (int, string) {
+		if strings.Contains(string(fields["summary"]), "作成に失敗する") {
+			return http.StatusBadRequest, `{"errors":{"summary":"invalid"}}`
+		}
+		return http.StatusCreated, ""
+	}
+	writeJiraCSV(t, m, []models.CSVRecord{existing, duplicate1, duplicate2, invalid})
+
+	if _, err := m.ImportIssuesWithResult(); err != nil {
+		t.Fatalf("ImportIssuesWithResult がエラーを返しました: %v", err)
+	}
+
+	s := m.Summary()
+	if s.IssuesCreated != 2 || s.IssuesUpdated != 1 || s.IssuesReused != 0 || s.IssuesFailed != 1 {
+		t.Errorf("集計 = 作成 %d, 更新 %d, 既存 %d, 失敗 %d, want 作成 2, 更新 1, 既存 0, 失敗 1",
+			s.IssuesCreated, s.IssuesUpdated, s.IssuesReused, s.IssuesFailed)
+	}
+}
+
+func TestCountRows(t *testing.T) {
+	rows := []models.RowResult{
+		{PivotalID: "1", IssueKey: "TEST-1"},
+		{PivotalID: "1", IssueKey: "TEST-2"},
+		{PivotalID: "2", IssueKey: "TEST-3", Updated: true},
+		{PivotalID: "3", IssueKey: "TEST-4", Reused: true},
+		{PivotalID: "4", IssueKey: "TEST-5", Updated: true, Error: "ステータス更新エラー"},
+		{PivotalID: "5", Error: "イシュー作成エラー"},
+	}
+	created, updated, reused, failed := countRows(rows)
+	if created != 2 || updated != 1 || reused != 1 || failed != 2 {
+		t.Errorf("countRows = %d, %d, %d, %d, want 2, 1, 1, 2", created, updated, reused, failed)
+	}
+}
+
+func TestSelectRowsCountsReleasesSeparately(t *testing.T) {
+	records := []models.CSVRecord{
+		{"Type": "feature", "Pivotal State": "accepted"},
+		{"Type": "release", "Pivotal State": "unstarted"},
+		{"Type": "bug", "Pivotal State": "unstarted"},
+		{"Type": "Release", "Pivotal State": "accepted"},
+		{"Type": "chore", "Pivotal State": "accepted"},
+	}
+
+	tests := []struct {
+		name         string
+		env          map[string]string
+		wantRows     []int
+		wantReleases int
+		wantFiltered int
+	}{
+		{
+			name:         "リリースをバージョンとして作成",
+			env:          map[string]string{"RELEASES_AS_VERSIONS": "true"},
+			wantRows:     []int{0, 2, 4},
+			wantReleases: 2,
+		},
+		{
+			name:         "リリースとフィルターの併用",
+			env:          map[string]string{"RELEASES_AS_VERSIONS": "true", "FILTER_STATES": "accepted"},
+			wantRows:     []int{0, 4},
+			wantReleases: 2,
+			wantFiltered: 1,
+		},
+		{
+			name:         "リリースもイシューとして作成",
+			env:          map[string]string{"FILTER_STATES": "accepted"},
+			wantRows:     []int{0, 3, 4},
+			wantFiltered: 2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := NewMigrationService(newTestConfig(t, "https://jira.example.test", tt.env), nil, nil)
+			rows, releases, filtered := m.selectRows(records, 0, len(records))
+			if fmt.Sprint(rows) != fmt.Sprint(tt.wantRows) || releases != tt.wantReleases || filtered != tt.wantFiltered {
+				t.Errorf("selectRows = %v, リリース %d, フィルター %d, want %v, %d, %d",
+					rows, releases, filtered, tt.wantRows, tt.wantReleases, tt.wantFiltered)
+			}
+		})
+	}
+}
+
+// payloadFields は作成ペイロードの fields を JSON の値のマップとして返します
+func payloadFields(t *testing.T, payload map[string]interface{}) map[string]json.RawMessage {
+	t.Helper()
+	data, err := json.Marshal(payload)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded struct {
+		Fields map[string]json.RawMessage `json:"fields"`
+	}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	return decoded.Fields
+}
+
+func TestPreviewCreatePayload(t *testing.T) {
+	tests := []struct {
+		name   string
+		env    map[string]string
+		record models.CSVRecord
+		want   map[string]string // フィールドID → 期待するJSON
+		absent []string          // 含まれないフィールドID
+	}{
+		{
+			name:   "基本のフィールド",
+			record: models.CSVRecord{"JIRA Issue ID": "100", "Title": "  ログイン\n画面の   修正 ", "Type": "feature"},
+			want: map[string]string{
+				"project":   `{"key":"TEST"}`,
+				"summary":   `"[100] ログイン 画面の 修正"`,
+				"issuetype": `{"name":"Story"}`,
+				"labels":    `["pivotal-100"]`,
+			},
+			absent: []string{"description", "assignee", "reporter", "customfield_10016"},
+		},
+		{
+			name: "ラベルの正規化と共通ラベル",
+			env:  map[string]string{"GLOBAL_LABELS": "migrated, from pivotal"},
+			record: models.CSVRecord{
+				"JIRA Issue ID": "101", "Title": "ラベル", "Type": "bug",
+				"Labels": "frontend, needs review ,, migrated",
+			},
+			want: map[string]string{
+				"issuetype": `{"name":"Bug"}`,
+				"labels":    `["frontend","needs_review","migrated","from_pivotal","pivotal-101"]`,
+			},
+		},
+		{
+			name:   "ストーリーポイント",
+			record: models.CSVRecord{"JIRA Issue ID": "102", "Title": "ポイント", "Type": "feature", "Story Points": "3"},
+			want:   map[string]string{"customfield_10016": `3`},
+		},
+		{
+			name:   "小数のストーリーポイント",
+			record: models.CSVRecord{"JIRA Issue ID": "106", "Title": "ポイント", "Type": "feature", "Story Points": "0.5"},
+			want:   map[string]string{"customfield_10016": `0.5`},
+		},
+		{
+			name:   "見積もりなし",
+			record: models.CSVRecord{"JIRA Issue ID": "107", "Title": "ポイント", "Type": "feature", "Story Points": ""},
+			absent: []string{"customfield_10016"},
+		},
+		{
+			name:   "ストーリーポイントを省略",
+			env:    map[string]string{"SKIP_STORY_POINTS": "true"},
+			record: models.CSVRecord{"JIRA Issue ID": "103", "Title": "ポイント", "Type": "feature", "Story Points": "3"},
+			absent: []string{"customfield_10016"},
+		},
+		{
+			name: "カスタムフィールド",
+			env:  map[string]string{"PIVOTAL_ID_FIELD": "customfield_10100"},
+			record: models.CSVRecord{
+				"JIRA Issue ID": "104", "Title": "カスタムフィールド", "Type": "chore", "Fix Version": "v1.0",
+			},
+			want: map[string]string{
+				"customfield_10100": `"104"`,
+				"fixVersions":       `[{"name":"v1.0"}]`,
+			},
+		},
+		{
+			name: "担当者と報告者のマッピング",
+			record: models.CSVRecord{
+				"JIRA Issue ID": "105", "Title": "担当者", "Type": "feature",
+				"Assignee": "pivotal_user1", "Reporter": "unknown_user",
+			},
+			want: map[string]string{
+				"assignee":    `{"id":"jira_user1"}`,
+				"description": `"報告者: unknown_user"`,
+			},
+			absent: []string{"reporter"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := newTestService(t, newFakeJira(), tt.env)
+			payload, err := m.PreviewCreatePayload(tt.record)
+			if err != nil {
+				t.Fatalf("PreviewCreatePayload がエラーを返しました: %v", err)
+			}
+			fields := payloadFields(t, payload)
+			for id, want := range tt.want {
+				if got := string(fields[id]); got != want {
+					t.Errorf("%s = %s, want %s", id, got, want)
+				}
+			}
+			for _, id := range tt.absent {
+				if got, ok := fields[id]; ok {
+					t.Errorf("%s = %s, want なし", id, got)
+				}
+			}
+		})
+	}
+}
+
+func TestPreviewCreatePayloadReviewers(t *testing.T) {
+	api.UserMapping["pivotal_user2"] = "jira_user2"
+	t.Cleanup(func() { delete(api.UserMapping, "pivotal_user2") })
+
+	tests := []struct {
+		name            string
+		env             map[string]string
+		reviewers       string
+		wantField       string // 空の場合はフィールドを含まない
+		wantDescription string // 空の場合は説明文を含まない
+	}{
+		{
+			name:      "Cloud はアカウントIDの配列",
+			env:       map[string]string{"JIRA_REVIEWER_FIELD": "customfield_10200"},
+			reviewers: "pivotal_user1, pivotal_user2",
+			wantField: `[{"id":"jira_user1"},{"id":"jira_user2"}]`,
+		},
+		{
+			name:      "Server はユーザー名の配列",
+			env:       map[string]string{"JIRA_REVIEWER_FIELD": "customfield_10200", "JIRA_DEPLOYMENT": "server"},
+			reviewers: "pivotal_user1, pivotal_user2",
+			wantField: `[{"name":"jira_user1"},{"name":"jira_user2"}]`,
+		},
+		{
+			name:            "マッピングにないレビュアーは説明文に追記",
+			env:             map[string]string{"JIRA_REVIEWER_FIELD": "customfield_10200"},
+			reviewers:       "pivotal_user1, unknown_user",
+			wantField:       `[{"id":"jira_user1"}]`,
+			wantDescription: `"レビュアー: unknown_user"`,
+		},
+		{
+			name:            "すべてマッピングにない場合はフィールドを含まない",
+			env:             map[string]string{"JIRA_REVIEWER_FIELD": "customfield_10200"},
+			reviewers:       "unknown_user",
+			wantDescription: `"レビュアー: unknown_user"`,
+		},
+		{
+			name:            "フィールド未設定の場合はすべて説明文に追記",
+			reviewers:       "pivotal_user1, pivotal_user2",
+			wantDescription: `"レビュアー: pivotal_user1, pivotal_user2"`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := newTestService(t, newFakeJira(), tt.env)
+			payload, err := m.PreviewCreatePayload(models.CSVRecord{
+				"JIRA Issue ID": "100", "Title": "レビュー", "Type": "feature", "Reviewers": tt.reviewers,
+			})
+			if err != nil {
+				t.Fatalf("PreviewCreatePayload がエラーを返しました: %v", err)
+			}
+			fields := payloadFields(t, payload)
+			if got := string(fields["customfield_10200"]); got != tt.wantField {
+				t.Errorf("customfield_10200 = %s, want %s", got, tt.wantField)
+			}
+			if got := string(fields["description"]); got != tt.wantDescription {
+				t.Errorf("description = %s, want %s", got, tt.wantDescription)
+			}
+		})
+	}
+}
+
+func TestBuildSummary(t *testing.T) {
+	tests := []struct {
+		format string
+		want   string
+	}{
+		{"[{id}] {title}", "[100] ログイン画面"},
+		{"{title}", "ログイン画面"},
+		{"{title} (#{id})", "ログイン画面 (#100)"},
+	}
+	for _, tt := range tests {
+		if got := buildSummary(tt.format, "100", "ログイン画面"); got != tt.want {
+			t.Errorf("buildSummary(%q) = %q, want %q", tt.format, got, tt.want)
+		}
+	}
+
+	// 接頭辞なしの場合は作成するイシューのサマリーがタイトルのみになる
+	m := newTestService(t, newFakeJira(), map[string]string{"SUMMARY_PREFIX_FORMAT": "{title}"})
+	payload, err := m.PreviewCreatePayload(models.CSVRecord{"JIRA Issue ID": "100", "Title": "ログイン画面", "Type": "feature"})
+	if err != nil {
+		t.Fatalf("PreviewCreatePayload がエラーを返しました: %v", err)
+	}
+	if got := string(payloadFields(t, payload)["summary"]); got != `"ログイン画面"` {
+		t.Errorf("summary = %s, want \"ログイン画面\"", got)
+	}
+}
+
+func TestBuildCreatePayloadParentLink(t *testing.T) {
+	m := newTestService(t, newFakeJira(), nil)
+
+	// サブタスクは作成時に親のイシューを指定する
+	parent := map[string]interface{}{
+		"parent":  map[string]string{"key": "TEST-1"},
+		"summary": "上書きされないサマリー",
+	}
+	payload, err := m.jiraClient.BuildCreatePayload("サブタスク", "", nil, "Sub-task", "", "", parent)
+	if err != nil {
+		t.Fatalf("BuildCreatePayload がエラーを返しました: %v", err)
+	}
+	fields := payloadFields(t, payload)
+	if got := string(fields["parent"]); got != `{"key":"TEST-1"}` {
+		t.Errorf("parent = %s, want {\"key\":\"TEST-1\"}", got)
+	}
+	if got := string(fields["summary"]); got != `"サブタスク"` {
+		t.Errorf("summary = %s, want \"サブタスク\"（追加フィールドで上書きされています）", got)
+	}
+
+	if _, err := m.jiraClient.BuildCreatePayload("タイプなし", "", nil, " ", "", "", nil); err == nil {
+		t.Error("イシュータイプが空の場合にエラーになりません")
+	}
+}
+
+func TestAddCommentsVisibility(t *testing.T) {
+	comment := "公開するコメント" + commentSeparator + "[internal] 社内向けのコメント"
+
+	type sentComment struct {
+		Body       string                 `json:"body"`
+		Visibility *api.CommentVisibility `json:"visibility"`
+	}
+	tests := []struct {
+		name string
+		env  map[string]string
+		want []sentComment
+	}{
+		{
+			name: "制限なし",
+			want: []sentComment{{Body: comment}},
+		},
+		{
+			name: "すべてのコメントを制限",
+			env:  map[string]string{"COMMENT_VISIBILITY": "role:Administrators"},
+			want: []sentComment{{Body: comment, Visibility: &api.CommentVisibility{Type: "role", Value: "Administrators"}}},
+		},
+		{
+			name: "マーカーで始まるコメントのみ制限",
+			env:  map[string]string{"COMMENT_VISIBILITY": "group:jira-developers", "COMMENT_INTERNAL_MARKER": "[internal]"},
+			want: []sentComment{
+				{Body: "公開するコメント"},
+				{Body: "社内向けのコメント", Visibility: &api.CommentVisibility{Type: "group", Value: "jira-developers"}},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := newFakeJira()
+			key := fake.addIssue(nil)
+			m := newTestService(t, fake, tt.env)
+
+			if err := m.addComments(key, comment); err != nil {
+				t.Fatalf("addComments がエラーを返しました: %v", err)
+			}
+
+			issue := fake.issue(key)
+			if len(issue.Comments) != len(tt.want) {
+				t.Fatalf("コメント = %q, want %d 件", issue.Comments, len(tt.want))
+			}
+			for i, raw := range issue.Comments {
+				var got sentComment
+				if err := json.Unmarshal([]byte(raw), &got); err != nil {
+					t.Fatal(err)
+				}
+				// 制限しない場合は visibility を送信しない
+				if has := strings.Contains(raw, `"visibility"`); has != (tt.want[i].Visibility != nil) {
+					t.Errorf("コメント %d の visibility の有無 = %v（%s）", i+1, has, raw)
+				}
+				if got.Body != tt.want[i].Body || fmt.Sprint(got.Visibility) != fmt.Sprint(tt.want[i].Visibility) {
+					t.Errorf("コメント %d = %s, want %+v", i+1, raw, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestImportPhasesEpicsFirst(t *testing.T) {
+	targets := []models.CSVRecord{
+		{"Type": "feature"}, {"Type": "epic"}, {"Type": "bug"}, {"Type": "Epic"}, {"Type": "chore"},
+	}
+
+	m := NewMigrationService(newTestConfig(t, "https://jira.example.test", map[string]string{"EPICS_FIRST": "true"}), nil, nil)
+	if got, want := fmt.Sprint(m.importPhases(targets)), "[[1 3] [0 2 4]]"; got != want {
+		t.Errorf("EPICS_FIRST のフェーズ = %s, want %s", got, want)
+	}
+
+	m = NewMigrationService(newTestConfig(t, "https://jira.example.test", map[string]string{"EPICS_FIRST": "false"}), nil, nil)
+	if got, want := fmt.Sprint(m.importPhases(targets)), "[[0 1 2 3 4]]"; got != want {
+		t.Errorf("EPICS_FIRST 無効のフェーズ = %s, want %s", got, want)
+	}
+}
+
+func TestImportEpicsFinishBeforeChildrenStart(t *testing.T) {
+	fake := newFakeJira()
+
+	// イシュー作成の開始と終了を記録する（エピックの作成は遅くして、並列に処理すると後の行が先に始まるようにする）
+	var mu sync.Mutex
+	var events []string
+	handler := http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.Method != "POST" || req.URL.Path != "/rest/api/2/issue" {
+			fake.ServeHTTP(w, req)
+			return
+		}
+		body, _ := io.ReadAll(req.Body)
+		req.Body = io.NopCloser(strings.NewReader(string(body)))
+		kind := "child"
+		if strings.Contains(string(body), `"issuetype":{"name":"Epic"}`) {
+			kind = "epic"
+			time.Sleep(20 * time.Millisecond)
+		}
+
+		mu.Lock()
+		events = append(events, "start "+kind)
+		mu.Unlock()
+		fake.ServeHTTP(w, req)
+		mu.Lock()
+		events = append(events, "end "+kind)
+		mu.Unlock()
+	})
+
+	m := newTestService(t, handler, map[string]string{"EPICS_FIRST": "true", "MAX_CONCURRENT": "4"})
+	writeJiraCSV(t, m, []models.CSVRecord{
+		jiraRecord("100", "子のストーリー1", "feature", ""),
+		jiraRecord("200", "エピック1", "epic", ""),
+		jiraRecord("300", "子のストーリー2", "feature", ""),
+		jiraRecord("400", "エピック2", "epic", ""),
+	})
+
+	result, err := m.ImportIssuesWithResult()
+	if err != nil {
+		t.Fatalf("ImportIssuesWithResult がエラーを返しました: %v", err)
+	}
+	if result.Failed != 0 {
+		t.Fatalf("結果 = %+v, want 失敗なし", result.Rows)
+	}
+
+	lastEpicEnd, firstChildStart := -1, len(events)
+	for i, event := range events {
+		switch event {
+		case "end epic":
+			lastEpicEnd = i
+		case "start child":
+			firstChildStart = min(firstChildStart, i)
+		}
+	}
+	if lastEpicEnd < 0 || firstChildStart == len(events) || lastEpicEnd > firstChildStart {
+		t.Errorf("イシュー作成の順序 = %q, want すべてのエピックの作成が終わってから子のイシューを作成", events)
+	}
+}
+
+func TestImportIssueTypesInAllowedSet(t *testing.T) {
+	tests := []struct {
+		name    string
+		env     map[string]string
+		allowed []string // 標準のイシュータイプに加えて作成を許可するイシュータイプ
+	}{
+		{"デフォルト", nil, nil},
+		{"小文字のDEFAULT_ISSUE_TYPE", map[string]string{"DEFAULT_ISSUE_TYPE": "story"}, nil},
+		{"独自のDEFAULT_ISSUE_TYPE", map[string]string{"DEFAULT_ISSUE_TYPE": "Improvement"}, []string{"Improvement"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			allowed := append(slices.Clone(config.StandardIssueTypes), tt.allowed...)
+
+			fake := newFakeJira()
+			// プロジェクトにないイシュータイプはJIRAと同じく 400 で拒否する
+			fake.createFn = func(fields map[string]json.RawMessage) (int, string) {
+				var issueType struct{ Name string }
+				json.Unmarshal(fields["issuetype"], &issueType)
+				if !slices.Contains(allowed, issueType.Name) {
+					return http.StatusBadRequest, `{"errors":{"issuetype":"valid issue type is required"}}`
+				}
+				return http.StatusCreated, ""
+			}
+			m := newTestService(t, fake, tt.env)
+
+			pivotalTypes := []string{"feature", "STORY", "Chore", "bug", "epic", "release", "spike"}
+			var records []models.CSVRecord
+			for i, pivotalType := range pivotalTypes {
+				records = append(records, jiraRecord(fmt.Sprint(100+i), pivotalType+" のストーリー", pivotalType, "To Do"))
+			}
+			writeJiraCSV(t, m, records)
+
+			if _, err := m.ImportIssuesWithResult(); err != nil {
+				t.Fatalf("ImportIssuesWithResult がエラーを返しました: %v", err)
+			}
+			if s := m.Summary(); s.IssuesCreated != len(pivotalTypes) || s.IssuesFailed != 0 {
+				t.Errorf("集計 = 作成 %d, 失敗 %d, want 作成 %d, 失敗 0", s.IssuesCreated, s.IssuesFailed, len(pivotalTypes))
+			}
+
+			for _, pivotalType := range pivotalTypes {
+				if got := resolveIssueType(m.config, pivotalType); !slices.Contains(allowed, got) {
+					t.Errorf("resolveIssueType(%q) = %q, want %v のいずれか", pivotalType, got, allowed)
+				}
+			}
+			for _, issueType := range MappedIssueTypes(m.config) {
+				if !slices.Contains(allowed, issueType) {
+					t.Errorf("MappedIssueTypes に %q が含まれます, want %v のいずれか", issueType, allowed)
+				}
+			}
+		})
+	}
+}
+
+func TestSkipStatusConsistentBetweenConversionAndImport(t *testing.T) {
+	tests := []struct {
+		name    string
+		initial string // unstarted を変換するステータス
+		skip    string // SKIP_STATUSES（空の場合はデフォルトの backlog）
+	}{
+		{"デフォルトの表記", "Backlog", ""},
+		{"小文字", "backlog", ""},
+		{"独自のステータス", "未着手", "未着手"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mappingFile := filepath.Join(t.TempDir(), "status_mapping.json")
+			mapping := `{"*": {"unstarted": "` + tt.initial + `", "started": "In Progress"}}`
+			if err := os.WriteFile(mappingFile, []byte(mapping), 0644); err != nil {
+				t.Fatal(err)
+			}
+			env := map[string]string{"STATUS_MAPPING_FILE": mappingFile}
+			if tt.skip != "" {
+				env["SKIP_STATUSES"] = tt.skip
+			}
+			fake := newFakeJira()
+			m := newTestService(t, fake, env)
+
+			writeRawCSV(t, m.config.PivotalCSV, "Id,Title,Type,Current State\n"+
+				"100,未着手のストーリー,feature,unstarted\n"+
+				"200,着手済みのストーリー,feature,started\n")
+			records, err := m.csvProc.ReadPivotalCSV()
+			if err != nil {
+				t.Fatalf("ReadPivotalCSV がエラーを返しました: %v", err)
+			}
+			converted, err := m.csvProc.ProcessPivotalToJiraCSV(records)
+			if err != nil {
+				t.Fatalf("ProcessPivotalToJiraCSV がエラーを返しました: %v", err)
+			}
+			if got := converted[0]["JIRA Status"]; got != tt.initial {
+				t.Errorf("変換後の JIRA Status = %q, want %q", got, tt.initial)
+			}
+			if m.config.NeedsTransition(converted[0]["JIRA Status"]) {
+				t.Errorf("変換後の JIRA Status %q が遷移の対象になっています", converted[0]["JIRA Status"])
+			}
+			writeJiraCSV(t, m, converted)
+
+			if _, err := m.ImportIssuesWithResult(); err != nil {
+				t.Fatalf("ImportIssuesWithResult がエラーを返しました: %v", err)
+			}
+			if s := m.Summary(); s.IssuesCreated != 2 || s.IssuesFailed != 0 {
+				t.Errorf("集計 = 作成 %d, 失敗 %d, want 作成 2, 失敗 0", s.IssuesCreated, s.IssuesFailed)
+			}
+
+			keys := make(map[string]string)
+			for _, row := range readCSVFile(t, m.config.JiraCSV) {
+				keys[row["JIRA Issue ID"]] = row["JIRA Issue Key"]
+			}
+			// 初期ステータスの行は遷移を取得せず、作成時の To Do のまま
+			if n := fake.countRequests("GET /issue/" + keys["100"] + "/transitions"); n != 0 {
+				t.Errorf("%s の行で遷移を %d 回取得しました, want 0", tt.initial, n)
+			}
+			if got := fake.issue(keys["100"]).Status; got != "To Do" {
+				t.Errorf("%s の行のステータス = %q, want To Do", tt.initial, got)
+			}
+			if got := fake.issue(keys["200"]).Status; got != "In Progress" {
+				t.Errorf("started の行のステータス = %q, want In Progress", got)
+			}
+		})
+	}
+}
+
+func TestImportDedupOnCreate(t *testing.T) {
+	fake := newFakeJira()
+	fake.labelled["pivotal-100"] = fake.addIssue(nil) // TEST-1: 前回の実行で作成済みのイシュー
+	m := newTestService(t, fake, map[string]string{"DEDUP_ON_CREATE": "true", "MAX_CONCURRENT": "1"})
+	writeJiraCSV(t, m, []models.CSVRecord{
+		jiraRecord("100", "作成済みのストーリー", "feature", "Done"),
+		jiraRecord("200", "新しいストーリー", "feature", "Done"),
+	})
+
+	result, err := m.ImportIssuesWithResult()
+	if err != nil {
+		t.Fatalf("ImportIssuesWithResult がエラーを返しました: %v", err)
+	}
+
+	want := []models.RowResult{
+		{Row: 1, PivotalID: "100", IssueKey: "TEST-1", Reused: true},
+		{Row: 2, PivotalID: "200", IssueKey: "TEST-2"},
+	}
+	if len(result.Rows) != len(want) {
+		t.Fatalf("結果の行数 = %d, want %d", len(result.Rows), len(want))
+	}
+	for i, row := range result.Rows {
+		if row.PivotalID != want[i].PivotalID || row.IssueKey != want[i].IssueKey || row.Reused != want[i].Reused || row.Error != "" {
+			t.Errorf("行 %d = %+v, want %+v", i+1, row, want[i])
+		}
+	}
+	if s := m.Summary(); s.IssuesCreated != 1 || s.IssuesReused != 1 {
+		t.Errorf("集計 = 作成 %d, 既存 %d, want 作成 1, 既存 1", s.IssuesCreated, s.IssuesReused)
+	}
+
+	// 既存のイシューには作成もステータスの遷移もしない
+	if n := fake.countRequests("POST /issue"); n != 1 {
+		t.Errorf("作成のリクエスト数 = %d, want 1", n)
+	}
+	if n := fake.countRequests("GET /issue/TEST-1/transitions"); n != 0 {
+		t.Errorf("既存のイシューの遷移を %d 回取得しました, want 0", n)
+	}
+	if got := fake.issue("TEST-2").Status; got != "Done" {
+		t.Errorf("作成したイシューのステータス = %q, want Done", got)
+	}
+}
+
+func TestImportSetsStoryPointsAtCreate(t *testing.T) {
+	fake := newFakeJira()
+	m := newTestService(t, fake, nil)
+	record := jiraRecord("100", "ポイントのあるストーリー", "feature", "")
+	record["Story Points"] = "3"
+	writeJiraCSV(t, m, []models.CSVRecord{record})
+
+	if _, err := m.ImportIssuesWithResult(); err != nil {
+		t.Fatalf("ImportIssuesWithResult がエラーを返しました: %v", err)
+	}
+
+	if got := string(fake.issue("TEST-1").Fields["customfield_10016"]); got != "3" {
+		t.Errorf("作成時の customfield_10016 = %q, want 3", got)
+	}
+	// ストーリーポイントのために作成後の更新は送信しない
+	if n := fake.countRequests("PUT /issue/TEST-1"); n != 0 {
+		t.Errorf("作成後の更新を %d 回送信しました, want 0", n)
+	}
+}
+
+func TestImportConsolidatesCreateFields(t *testing.T) {
+	fake := newFakeJira()
+	m := newTestService(t, fake, map[string]string{
+		"PIVOTAL_ID_FIELD":       "customfield_10100",
+		"ORIGINAL_CREATED_FIELD": "customfield_10300",
+	})
+	record := jiraRecord("100", "まとめて作成するストーリー", "feature", "")
+	record["Story Points"] = "5"
+	record["Fix Version"] = "v1.0"
+	record["Created Date"] = "2024-01-02T10:00:00.000+0900"
+	writeJiraCSV(t, m, []models.CSVRecord{record})
+
+	if _, err := m.ImportIssuesWithResult(); err != nil {
+		t.Fatalf("ImportIssuesWithResult がエラーを返しました: %v", err)
+	}
+
+	want := map[string]string{
+		"customfield_10016": `5`,
+		"customfield_10100": `"100"`,
+		"customfield_10300": `"2024-01-02T10:00:00.000+0900"`,
+		"fixVersions":       `[{"name":"v1.0"}]`,
+	}
+	fields := fake.issue("TEST-1").Fields
+	for id, value := range want {
+		if got := string(fields[id]); got != value {
+			t.Errorf("作成時の %s = %s, want %s", id, got, value)
+		}
+	}
+	if n := fake.countRequests("POST /issue"); n != 1 {
+		t.Errorf("作成のリクエスト数 = %d, want 1", n)
+	}
+	if n := fake.countRequests("PUT /issue/TEST-1"); n != 0 {
+		t.Errorf("作成後の更新を %d 回送信しました, want 0", n)
+	}
+}
+
+func TestImportDefersFieldsRejectedAtCreate(t *testing.T) {
+	tests := []struct {
+		name           string
+		updateStatus   int // 作成後の更新への応答
+		wantFieldError bool
+	}{
+		{"作成後の更新で反映する", http.StatusNoContent, false},
+		{"更新に失敗した項目を結果に残す", http.StatusBadRequest, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := newFakeJira()
+			// 作成画面にない PIVOTAL_ID_FIELD は作成時に拒否する
+			fake.createFn = func(fields map[string]json.RawMessage) (int, string) {
+				if _, ok := fields["customfield_10100"]; ok {
+					return http.StatusBadRequest, `{"errors":{"customfield_10100":"Field 'customfield_10100' cannot be set. It is not on the appropriate screen, or unknown."}}`
+				}
+				return http.StatusCreated, ""
+			}
+			handler := http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+				if req.Method == "PUT" && req.URL.Path == "/rest/api/2/issue/TEST-1" {
+					fake.mu.Lock()
+					fake.requests = append(fake.requests, "PUT /issue/TEST-1")
+					fake.mu.Unlock()
+					writeRaw(w, tt.updateStatus, `{"errors":{"customfield_10100":"invalid"}}`)
+					return
+				}
+				fake.ServeHTTP(w, req)
+			})
+			m := newTestService(t, handler, map[string]string{"PIVOTAL_ID_FIELD": "customfield_10100"})
+			record := jiraRecord("100", "作成画面にないフィールド", "feature", "")
+			record["Story Points"] = "3"
+			writeJiraCSV(t, m, []models.CSVRecord{record})
+
+			result, err := m.ImportIssuesWithResult()
+			if err != nil {
+				t.Fatalf("ImportIssuesWithResult がエラーを返しました: %v", err)
+			}
+
+			// 拒否されなかったフィールドは再試行した作成にまとめて含める
+			fields := fake.issue("TEST-1").Fields
+			if got := string(fields["customfield_10016"]); got != "3" {
+				t.Errorf("作成時の customfield_10016 = %q, want 3", got)
+			}
+			if _, ok := fields["customfield_10100"]; ok {
+				t.Error("拒否された customfield_10100 を作成時に含めています")
+			}
+			if n := fake.countRequests("PUT /issue/TEST-1"); n != 1 {
+				t.Errorf("作成後の更新のリクエスト数 = %d, want 1", n)
+			}
+
+			row := result.Rows[0]
+			if row.IssueKey != "TEST-1" || row.Error != "" {
+				t.Errorf("行の結果 = %+v, want TEST-1 で成功", row)
+			}
+			if _, ok := row.FieldErrors["customfield_10100"]; ok != tt.wantFieldError {
+				t.Errorf("FieldErrors = %v, customfield_10100 の有無 want %v", row.FieldErrors, tt.wantFieldError)
+			}
+		})
+	}
+}
+
+// BenchmarkImportIssues はモックのJIRAに対する並列インポートの処理時間を計測します
+// 並列数を上げたときに行ごとの集計がボトルネックにならないことを確認します
+func BenchmarkImportIssues(b *testing.B) {
+	utils.SetLogLevel("error")
+	b.Cleanup(func() { utils.SetLogLevel("info") })
+
+	var records []models.CSVRecord
+	for i := range 200 {
+		records = append(records, jiraRecord(fmt.Sprint(1000+i), fmt.Sprintf("ストーリー%d", i), "feature", ""))
+	}
+
+	for _, concurrency := range []int{1, 8, 32} {
+		b.Run(fmt.Sprintf("MAX_CONCURRENT=%d", concurrency), func(b *testing.B) {
+			m := newTestService(b, newFakeJira(), map[string]string{"MAX_CONCURRENT": fmt.Sprint(concurrency)})
+			for range b.N {
+				// 前回の実行で書き戻したキーを消して、毎回すべての行を作成する
+				b.StopTimer()
+				writeJiraCSV(b, m, records)
+				m = restartService(m)
+				b.StartTimer()
+
+				if _, err := m.ImportIssuesWithResult(); err != nil {
+					b.Fatalf("ImportIssuesWithResult がエラーを返しました: %v", err)
+				}
+			}
+		})
+	}
+}
+
+// BenchmarkRowCounter は並列に処理する行の集計について、ロックとアトミック変数の加算を比較します
+func BenchmarkRowCounter(b *testing.B) {
+	b.Run("mutex", func(b *testing.B) {
+		var mu sync.Mutex
+		var count int64
+		b.RunParallel(func(pb *testing.PB) {
+			for pb.Next() {
+				mu.Lock()
+				count++
+				mu.Unlock()
+			}
+		})
+	})
+	b.Run("atomic", func(b *testing.B) {
+		var count atomic.Int64
+		b.RunParallel(func(pb *testing.PB) {
+			for pb.Next() {
+				count.Add(1)
+			}
+		})
+	})
+}
+
+func TestMultipleOwnersMapFirstOwner(t *testing.T) {
+	m := newTestService(t, newFakeJira(), nil)
+	writeRawCSV(t, m.config.PivotalCSV, "Id,Title,Type,Current State,Owned By,Owned By\n"+
+		"100,ペアで担当するストーリー,feature,started,pivotal_user1,bob\n")
+	records, err := m.csvProc.ReadPivotalCSV()
+	if err != nil {
+		t.Fatalf("ReadPivotalCSV がエラーを返しました: %v", err)
+	}
+	converted, err := m.csvProc.ProcessPivotalToJiraCSV(records)
+	if err != nil {
+		t.Fatalf("ProcessPivotalToJiraCSV がエラーを返しました: %v", err)
+	}
+
+	// マッピングの確認では担当者を1人ずつ数える
+	coverage := newConversionCoverage()
+	tallyConversion(m.config, &coverage, converted[0])
+	if want := map[string]int{"bob": 1}; !maps.Equal(coverage.UnmappedUsers, want) {
+		t.Errorf("UnmappedUsers = %v, want %v", coverage.UnmappedUsers, want)
+	}
+
+	// 最初の担当者をJIRAの担当者にし、2人目以降は説明文に残す
+	payload, err := m.PreviewCreatePayload(converted[0])
+	if err != nil {
+		t.Fatalf("PreviewCreatePayload がエラーを返しました: %v", err)
+	}
+	fields := payloadFields(t, payload)
+	if got := string(fields["assignee"]); got != `{"id":"jira_user1"}` {
+		t.Errorf("assignee = %s, want {\"id\":\"jira_user1\"}", got)
+	}
+	if got := string(fields["description"]); got != `"他の担当者: bob"` {
+		t.Errorf("description = %s, want \"他の担当者: bob\"", got)
+	}
+}
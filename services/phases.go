@@ -0,0 +1,28 @@
+package services
+
+import (
+	"pivotaltojira/models"
+)
+
+// importPhases は targets のインデックスを、作成する順のフェーズに分けて返します
+// 各フェーズ内の行は並列に処理し、前のフェーズがすべて完了してから次のフェーズを始めます
+// EPICS_FIRST が有効な場合は、エピックを子のイシューより先に作成するためエピックの行を最初のフェーズにします
+// 無効な場合やエピックの行がない場合は、すべての行を1つのフェーズで処理します
+func (m *MigrationService) importPhases(targets []models.CSVRecord) [][]int {
+	var epics, others []int
+	for i, record := range targets {
+		if m.config.EpicsFirst && resolveIssueType(m.config, record["Type"]) == "Epic" {
+			epics = append(epics, i)
+		} else {
+			others = append(others, i)
+		}
+	}
+
+	var phases [][]int
+	for _, phase := range [][]int{epics, others} {
+		if len(phase) > 0 {
+			phases = append(phases, phase)
+		}
+	}
+	return phases
+}
@@ -0,0 +1,66 @@
+package services
+
+import (
+	"time"
+
+	"pivotaltojira/models"
+	"pivotaltojira/utils"
+)
+
+// ProgressReporter は移行処理の進捗を受け取るインターフェースです
+// GUIやWeb画面に組み込む場合など、ログを解析せずに進捗を取得するために使います
+// イシューと添付ファイルのイベントは並列に処理しているgoroutineから呼ばれるため、実装は並行呼び出しに対して安全である必要があります
+// フェーズのイベントは RunMigration から呼ばれます
+type ProgressReporter interface {
+	// OnPhaseStart はフェーズ (convert/import/attachments) の開始時に呼ばれます
+	OnPhaseStart(phase string)
+	// OnPhaseEnd はフェーズの終了時に呼ばれます（失敗した場合は err にエラーが入ります）
+	OnPhaseEnd(phase string, elapsed time.Duration, err error)
+	// OnIssueCreated は行のイシューの作成（更新・既存イシューの再利用を含む）が完了したときに呼ばれます
+	OnIssueCreated(row models.RowResult)
+	// OnIssueFailed は行の処理に失敗したときに呼ばれます
+	OnIssueFailed(row models.RowResult, err error)
+	// OnAttachmentUploaded は添付ファイルのアップロードが完了したときに呼ばれます
+	OnAttachmentUploaded(file models.AttachmentFileResult)
+	// OnAttachmentFailed は添付ファイルのアップロードに失敗したときに呼ばれます
+	OnAttachmentFailed(file models.AttachmentFileResult, err error)
+}
+
+// LogProgressReporter は進捗をデバッグログに出力する ProgressReporter です（MigrationService の既定）
+// 通常のログは MigrationService が出力するため、このレポーターは -verbose の場合のみ出力します
+// 独自のレポーターに埋め込むと、必要なメソッドだけを実装できます
+type LogProgressReporter struct{}
+
+// OnPhaseStart はフェーズの開始をログに出力します
+func (LogProgressReporter) OnPhaseStart(phase string) {
+	utils.LogDebug("進捗: フェーズ %s 開始", phase)
+}
+
+// OnPhaseEnd はフェーズの終了をログに出力します
+func (LogProgressReporter) OnPhaseEnd(phase string, elapsed time.Duration, err error) {
+	if err != nil {
+		utils.LogDebug("進捗: フェーズ %s 失敗 (%s): %v", phase, elapsed, err)
+		return
+	}
+	utils.LogDebug("進捗: フェーズ %s 終了 (%s)", phase, elapsed)
+}
+
+// OnIssueCreated はイシューの作成をログに出力します
+func (LogProgressReporter) OnIssueCreated(row models.RowResult) {
+	utils.LogDebug("進捗: 行 %d (Pivotal ID: %s) → %s", row.Row, row.PivotalID, row.IssueKey)
+}
+
+// OnIssueFailed は行の処理の失敗をログに出力します
+func (LogProgressReporter) OnIssueFailed(row models.RowResult, err error) {
+	utils.LogDebug("進捗: 行 %d (Pivotal ID: %s) 失敗: %v", row.Row, row.PivotalID, err)
+}
+
+// OnAttachmentUploaded は添付ファイルのアップロードをログに出力します
+func (LogProgressReporter) OnAttachmentUploaded(file models.AttachmentFileResult) {
+	utils.LogDebug("進捗: 添付ファイル %s → %s", file.Path, file.IssueKey)
+}
+
+// OnAttachmentFailed は添付ファイルのアップロードの失敗をログに出力します
+func (LogProgressReporter) OnAttachmentFailed(file models.AttachmentFileResult, err error) {
+	utils.LogDebug("進捗: 添付ファイル %s 失敗: %v", file.Path, err)
+}
@@ -0,0 +1,109 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"pivotaltojira/models"
+)
+
+// recordingReporter は受け取った進捗イベントを順に記録する ProgressReporter です
+// 行のイベントは並列に処理しているgoroutineから呼ばれるため、記録はロックで保護します
+type recordingReporter struct {
+	LogProgressReporter
+	mu     sync.Mutex
+	events []string
+	rows   map[string]int // Pivotal ID → イベントを受け取った回数
+}
+
+func (r *recordingReporter) record(event string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events = append(r.events, event)
+}
+
+func (r *recordingReporter) recordRow(event string, row models.RowResult) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events = append(r.events, event)
+	if r.rows == nil {
+		r.rows = make(map[string]int)
+	}
+	r.rows[row.PivotalID]++
+}
+
+func (r *recordingReporter) OnPhaseStart(phase string) {
+	r.record("start " + phase)
+}
+
+func (r *recordingReporter) OnPhaseEnd(phase string, elapsed time.Duration, err error) {
+	r.record(fmt.Sprintf("end %s (error: %v)", phase, err != nil))
+}
+
+func (r *recordingReporter) OnIssueCreated(row models.RowResult) {
+	r.recordRow("created", row)
+}
+
+func (r *recordingReporter) OnIssueFailed(row models.RowResult, err error) {
+	r.recordRow("failed", row)
+}
+
+func TestProgressReporterEvents(t *testing.T) {
+	fake := newFakeJira()
+	fake.createFn = func(fields map[string]json.RawMessage) (int, string) {
+		if strings.Contains(string(fields["summary"]), "作成に失敗する") {
+			return http.StatusBadRequest, `{"errors":{"summary":"invalid"}}`
+		}
+		return http.StatusCreated, ""
+	}
+	m := newTestService(t, fake, map[string]string{"MAX_CONCURRENT": "8"})
+	reporter := &recordingReporter{}
+	m.SetProgressReporter(reporter)
+
+	var records []models.CSVRecord
+	for i := 1; i <= 20; i++ {
+		title := fmt.Sprintf("ストーリー%d", i)
+		if i == 7 {
+			title = "作成に失敗する"
+		}
+		records = append(records, jiraRecord(fmt.Sprint(100+i), title, "feature", ""))
+	}
+	writeJiraCSV(t, m, records)
+
+	if err := m.RunMigration(false, true, false); err != nil {
+		t.Fatalf("RunMigration がエラーを返しました: %v", err)
+	}
+
+	events := reporter.events
+	if len(events) != len(records)+2 {
+		t.Fatalf("イベント = %q, want %d 件", events, len(records)+2)
+	}
+	if events[0] != "start import" || events[len(events)-1] != "end import (error: false)" {
+		t.Errorf("最初と最後のイベント = %q, %q, want フェーズの開始と終了", events[0], events[len(events)-1])
+	}
+	rowEvents := events[1 : len(events)-1]
+	if created, failed := countEvents(rowEvents, "created"), countEvents(rowEvents, "failed"); created != 19 || failed != 1 {
+		t.Errorf("行のイベント = 作成 %d, 失敗 %d, want 作成 19, 失敗 1", created, failed)
+	}
+	for _, record := range records {
+		if n := reporter.rows[record["JIRA Issue ID"]]; n != 1 {
+			t.Errorf("Pivotal ID %s のイベント = %d 回, want 1 回", record["JIRA Issue ID"], n)
+		}
+	}
+}
+
+// countEvents は events のうち event と一致するものの数を返します
+func countEvents(events []string, event string) int {
+	n := 0
+	for _, e := range events {
+		if e == event {
+			n++
+		}
+	}
+	return n
+}
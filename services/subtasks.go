@@ -0,0 +1,79 @@
+package services
+
+import (
+	"sync"
+	"sync/atomic"
+
+	"pivotaltojira/models"
+	"pivotaltojira/utils"
+)
+
+// createSubtasks は親イシューの作成後に、Pivotalのタスクをサブタスクとして作成します（2パス目）
+// 親はマッピングから引き、作成に失敗したタスクはログに記録して親の結果には影響させません
+func (m *MigrationService) createSubtasks(records []models.CSVRecord, parents models.IssueMapping) {
+	semaphore := make(chan struct{}, m.config.MaxConcurrent)
+	var wg sync.WaitGroup
+	var created, failed atomic.Int64
+
+	for _, record := range records {
+		tasks := parseTasks(record["Tasks"])
+		if len(tasks) == 0 {
+			continue
+		}
+		parentKey := parents[record["JIRA Issue ID"]]
+		if parentKey == "" || parentKey == "ERROR" {
+			continue
+		}
+
+		for _, task := range tasks {
+			wg.Add(1)
+			semaphore <- struct{}{}
+
+			go func(parentKey string, task models.Task) {
+				defer wg.Done()
+				defer func() { <-semaphore }()
+
+				err := m.createSubtask(parentKey, task)
+
+				if err != nil {
+					utils.LogWarn(utils.T("import.subtask_failed", parentKey, task.Title, err))
+					failed.Add(1)
+					return
+				}
+				created.Add(1)
+			}(parentKey, task)
+		}
+	}
+
+	wg.Wait()
+	close(semaphore)
+
+	createdCount, failedCount := int(created.Load()), int(failed.Load())
+	if createdCount+failedCount > 0 {
+		utils.LogInfo(utils.T("import.subtasks_done", createdCount, failedCount))
+	}
+	m.summary.SubtasksCreated += createdCount
+	m.summary.SubtasksFailed += failedCount
+}
+
+// createSubtask は1つのタスクをサブタスクとして作成し、完了済みのタスクは完了のステータスへ遷移させます
+func (m *MigrationService) createSubtask(parentKey string, task models.Task) error {
+	// 長いタスク名はサマリーの上限で切り詰められ、元の名前は説明文に残る
+	issueType := m.config.SubtaskIssueType
+	parent := map[string]interface{}{"parent": map[string]string{"key": parentKey}}
+	issueKey, err := m.jiraClient.CreateIssueWithFields(task.Title, "", nil, issueType, "", "", parent)
+	if err != nil {
+		return err
+	}
+	utils.LogDebug("サブタスク %s を %s に作成しました", issueKey, parentKey)
+
+	if !task.Completed || m.config.SkipStatusUpdate {
+		return nil
+	}
+	if status := m.config.MapStatus(issueType, "accepted"); status != "" {
+		if err := m.jiraClient.UpdateStatus(issueKey, issueType, status); err != nil {
+			utils.LogWarn(utils.T("import.status_failed", issueKey, err))
+		}
+	}
+	return nil
+}
@@ -0,0 +1,22 @@
+package utils
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// Confirm は prompt を表示して in から1行読み、"yes" または "y" の場合に true を返します
+// 入力がない場合（パイプやCIなどで標準入力が閉じている場合）は false を返します
+func Confirm(in io.Reader, prompt string) bool {
+	fmt.Fprintf(os.Stderr, "%s [yes/no]: ", prompt)
+
+	line, _ := bufio.NewReader(in).ReadString('\n')
+	switch strings.ToLower(strings.TrimSpace(line)) {
+	case "y", "yes":
+		return true
+	}
+	return false
+}
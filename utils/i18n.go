@@ -0,0 +1,44 @@
+package utils
+
+import (
+	"fmt"
+	"strings"
+)
+
+// 対応している言語
+const (
+	LangJapanese = "ja"
+	LangEnglish  = "en"
+)
+
+// language はメッセージの出力に使う言語です（デフォルトは日本語）
+var language = LangJapanese
+
+// SetLanguage はメッセージの言語を設定します
+// "en_US.UTF-8" のようなロケール形式も受け付け、未対応の言語の場合は何もせず false を返します
+func SetLanguage(lang string) bool {
+	lang = strings.ToLower(strings.TrimSpace(lang))
+	if i := strings.IndexAny(lang, "_-."); i >= 0 {
+		lang = lang[:i]
+	}
+	if _, ok := messages[lang]; !ok {
+		return false
+	}
+	language = lang
+	return true
+}
+
+// T はメッセージIDに対応する現在の言語のメッセージを返します
+// 翻訳がない場合は日本語、それもない場合はメッセージIDをそのまま使います
+func T(id string, v ...interface{}) string {
+	format, ok := messages[language][id]
+	if !ok {
+		if format, ok = messages[LangJapanese][id]; !ok {
+			format = id
+		}
+	}
+	if len(v) == 0 {
+		return format
+	}
+	return fmt.Sprintf(format, v...)
+}
@@ -1,40 +1,117 @@
 package utils
 
 import (
+	"fmt"
 	"log"
 	"os"
+	"strings"
 	"time"
 )
 
+// ログレベル
+const (
+	LevelDebug = iota
+	LevelInfo
+	LevelWarn
+	LevelError
+)
+
 var (
+	// DebugLogger はデバッグレベルのログを出力します
+	DebugLogger *log.Logger
 	// InfoLogger は情報レベルのログを出力します
 	InfoLogger *log.Logger
 	// WarnLogger は警告レベルのログを出力します
 	WarnLogger *log.Logger
 	// ErrorLogger はエラーレベルのログを出力します
 	ErrorLogger *log.Logger
+
+	// logLevel はこのレベル以上のログのみ出力します
+	logLevel = LevelInfo
 )
 
 // init関数はパッケージがインポートされたときに自動的に実行されます
 func init() {
+	DebugLogger = log.New(os.Stdout, "DEBUG: ", log.Ldate|log.Ltime)
 	InfoLogger = log.New(os.Stdout, "INFO: ", log.Ldate|log.Ltime)
 	WarnLogger = log.New(os.Stdout, "WARN: ", log.Ldate|log.Ltime)
 	ErrorLogger = log.New(os.Stderr, "ERROR: ", log.Ldate|log.Ltime)
 }
 
+// SetLogLevel はログレベルを文字列 (debug/info/warn/error) で設定します
+// 不明な値の場合は何もせず false を返します
+func SetLogLevel(level string) bool {
+	switch strings.ToLower(strings.TrimSpace(level)) {
+	case "debug":
+		logLevel = LevelDebug
+	case "info":
+		logLevel = LevelInfo
+	case "warn", "warning":
+		logLevel = LevelWarn
+	case "error":
+		logLevel = LevelError
+	default:
+		return false
+	}
+	return true
+}
+
+// ConfigureLogLevel はコマンドラインフラグと環境変数からログレベルを設定します
+// フラグ (-verbose / -quiet) が指定された場合は LOG_LEVEL より優先します
+func ConfigureLogLevel(verbose, quiet bool, envLevel string) {
+	switch {
+	case verbose:
+		SetLogLevel("debug")
+	case quiet:
+		SetLogLevel("warn")
+	case envLevel != "":
+		if !SetLogLevel(envLevel) {
+			LogWarn("不明なログレベルです: '%s'（info を使用します）", envLevel)
+		}
+	}
+}
+
+// DebugEnabled はデバッグレベルのログを出力する設定かどうかを返します
+// ログの内容を組み立てるコストが大きい場合に、事前に確認するために使います
+func DebugEnabled() bool {
+	return logLevel <= LevelDebug
+}
+
+// LogDebug はデバッグレベルのメッセージをログに記録します
+func LogDebug(format string, v ...interface{}) {
+	if logLevel <= LevelDebug {
+		DebugLogger.Print(Redact(sprintf(format, v...)))
+	}
+}
+
 // LogInfo は情報レベルのメッセージをログに記録します
 func LogInfo(format string, v ...interface{}) {
-	InfoLogger.Printf(format, v...)
+	if logLevel <= LevelInfo {
+		InfoLogger.Print(Redact(sprintf(format, v...)))
+	}
 }
 
 // LogWarn は警告レベルのメッセージをログに記録します
 func LogWarn(format string, v ...interface{}) {
-	WarnLogger.Printf(format, v...)
+	if logLevel <= LevelWarn {
+		if msg := Redact(sprintf(format, v...)); allowWarn(msg) {
+			WarnLogger.Print(msg)
+		}
+	}
 }
 
 // LogError はエラーレベルのメッセージをログに記録します
 func LogError(format string, v ...interface{}) {
-	ErrorLogger.Printf(format, v...)
+	ErrorLogger.Print(Redact(sprintf(format, v...)))
+}
+
+// sprintf は引数がない場合は書式を解釈せずにそのまま返します
+// utils.T で組み立て済みのメッセージに含まれる "%" が誤って解釈されないようにするためです
+func sprintf(format string, v ...interface{}) string {
+	if len(v) == 0 {
+		return format
+	}
+	return fmt.Sprintf(format, v...)
 }
 
 // TrackTime は関数の実行時間を計測して出力するユーティリティです
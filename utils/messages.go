@@ -0,0 +1,414 @@
+package utils
+
+// messages はメッセージIDごとの翻訳です
+// 新しいメッセージを追加する場合は ja と en の両方を登録してください
+var messages = map[string]map[string]string{
+	"ja": {
+		"config.load_failed": "設定の読み込みに失敗しました: %v",
+		"config.env_loaded":  "環境設定ファイルを読み込みました: %s",
+
+		// 繰り返される警告
+		"log.warn_suppressed": "次の警告が %d 回出力されたため、以降は省略して最後に回数をまとめて出力します: %s",
+		"log.warn_repeated":   "次の警告が合計 %d 回出力されました: %s",
+
+		// 本番環境の確認
+		"confirm.production_prompt":  "本番環境と判定されたJIRA (%s, プロジェクト: %s) に書き込みます。続行しますか？",
+		"confirm.production_skipped": "-yes が指定されたため、本番環境と判定されたJIRA (%s) に確認なしで書き込みます",
+		"confirm.production_aborted": "本番環境への書き込みを中止しました（確認を省略する場合は -yes を指定してください）",
+		"config.loaded":              "設定読み込み完了 (Max Concurrent: %d)",
+
+		// 並列数とレート制限
+		"config.concurrency_too_high": "並列数 (%d) がレート制限 (%.1f 件/秒) に対して大きすぎます。" +
+			"超過分のリクエストはレート制限で待機するだけで、goroutineを無駄に消費します（目安: %d 以下）",
+		"config.concurrency_clamped": "AUTO_CLAMP_CONCURRENCY が有効なため、並列数を %d に抑えます",
+
+		"auth.checking":                  "JIRA認証情報を確認しています...",
+		"auth.checking_api":              "JIRA APIの認証を確認しています...",
+		"auth.success":                   "JIRA認証成功",
+		"auth.success_url":               "JIRA認証成功！ 接続先: %s",
+		"auth.error":                     "JIRA認証エラー: %v",
+		"auth.hint_credentials":          "JIRA_EMAIL と JIRA_API_TOKEN（server の場合はユーザー名とパスワード）、JIRA_DEPLOYMENT を確認してください。403 の場合はアカウントがロックされていないか（ブラウザでのCAPTCHA要求など）も確認してください。",
+		"auth.hint_transient":            "JIRAに一時的に接続できません（サーバーエラー・タイムアウト・接続エラー）。認証情報の問題ではない可能性が高いため、しばらくしてから再実行してください。続く場合は JIRA_URL とプロキシ設定を確認してください。",
+		"auth.hint_status":               "JIRAが想定外の応答 (HTTP %d) を返しました。JIRA_URL と JIRA_CONTEXT_PATH が正しいか確認してください。",
+		"auth.hint_url":                  "JIRA_URL・JIRA_CONTEXT_PATH と証明書の設定 (JIRA_CA_CERT) を確認してください。",
+		"auth.display_name":              "  表示名: %s",
+		"auth.email":                     "  メールアドレス: %s",
+		"auth.account_id":                "  アカウントID: %s",
+		"auth.username":                  "  ユーザー名: %s",
+		"auth.checking_project":          "JIRAプロジェクト '%s' を確認しています...",
+		"auth.project_error":             "JIRAプロジェクトエラー: %v",
+		"auth.check_project_key":         "JIRA_PROJECT_KEY を確認してください。",
+		"auth.create_permission_unknown": "イシュー作成権限を確認できませんでした: %v",
+		"auth.create_permission_denied":  "このアカウントはプロジェクト '%s' でイシューを作成する権限がありません。",
+		"auth.create_permission_ok":      "プロジェクト '%s' でのイシュー作成権限: あり",
+		"auth.all_ok":                    "JIRA APIの認証情報とプロジェクト設定は正常です。",
+
+		"tool.all_in_one":        "Pivotal → JIRA 移行ツール (%s)",
+		"tool.auth_check":        "JIRA認証確認ツール",
+		"tool.csv_convert":       "Pivotal CSV → JIRA CSV 変換ツール",
+		"tool.issue_import":      "JIRA イシューインポートツール",
+		"tool.attachment_upload": "JIRA 添付ファイルアップロードツール",
+		"tool.doctor":            "移行前の診断ツール (接続先: %s)",
+		"tool.json_export":       "JIRA CSV → JIRA JSONインポートファイル 変換ツール",
+		"tool.preview":           "イシュー作成ペイロードのプレビューツール",
+		"init.invalid_format":    "出力形式が不正です: %s（env または yaml を指定してください）",
+		"init.exists":            "%s は既に存在します。上書きする場合は -force を指定してください",
+		"init.write_failed":      "サンプル設定の書き込みに失敗しました: %v",
+		"init.written":           "%s に %d 件の設定項目を書き出しました",
+
+		"doctor.auth_ok":                  "認証: %s としてログインしました",
+		"doctor.auth_failed":              "認証: %v",
+		"doctor.latency_ok":               "応答時間: %s",
+		"doctor.latency_slow":             "応答時間: %s（遅延が大きいため移行に時間がかかる可能性があります）",
+		"doctor.latency_hint":             "プロキシ設定 (JIRA_PROXY) やネットワーク経路を確認してください",
+		"doctor.project_ok":               "プロジェクト: %s",
+		"doctor.project_failed":           "プロジェクト: %v",
+		"doctor.project_hint":             "JIRA_PROJECT_KEY または -project の値を確認してください",
+		"doctor.permission_hint":          "プロジェクトの権限スキームで「課題の作成」権限を付与してください",
+		"doctor.createmeta_failed":        "イシュータイプの取得: %v",
+		"doctor.issue_type_ok":            "イシュータイプ: %s",
+		"doctor.issue_type_missing":       "イシュータイプ: %s がプロジェクトにありません",
+		"doctor.issue_type_hint":          "プロジェクトのイシュータイプスキームに追加してください",
+		"doctor.story_point_ok":           "ストーリーポイント: %s は作成画面にある数値フィールドです",
+		"doctor.story_point_invalid":      "ストーリーポイント: %v",
+		"doctor.story_point_hint":         "JIRA_STORY_POINT_FIELD のフィールドIDと、作成画面の設定を確認してください",
+		"doctor.pivotal_id_field_ok":      "Pivotal IDフィールド: %s は作成画面にある文字列フィールドです",
+		"doctor.pivotal_id_field_invalid": "Pivotal IDフィールド: %v",
+		"doctor.pivotal_id_field_hint":    "PIVOTAL_ID_FIELD のフィールドIDと、作成画面の設定を確認してください",
+		"doctor.failed":                   "診断が完了しました: %d 件の重要な問題があります",
+		"doctor.all_ok":                   "診断が完了しました: 問題はありません",
+
+		"option.csv_file":            "CSVファイルを指定: %s",
+		"option.input_file":          "入力ファイルを指定: %s",
+		"option.output_file":         "出力ファイルを指定: %s",
+		"option.attachments_folder":  "添付ファイルフォルダを指定: %s",
+		"option.concurrent":          "並列処理数を指定: %d",
+		"option.update_existing":     "JIRA Issue Key が設定済みの行は既存イシューを更新します",
+		"option.no_transition_cache": "ステータス遷移のキャッシュを無効にします",
+		"option.project":             "対象のJIRAプロジェクト: %s",
+
+		"convert.start":                 "CSVデータの変換を開始します",
+		"convert.done":                  "CSVの変換が完了しました",
+		"convert.reading":               "Pivotal CSVを読み込んでいます: %s",
+		"convert.read_error":            "Pivotal CSV読み込みエラー: %v",
+		"convert.read_done":             "Pivotal CSVを読み込みました: %d 件のレコード",
+		"convert.converting":            "JIRAフォーマットに変換しています...",
+		"convert.streaming":             "Pivotal CSVを読み込みながら変換しています: %s → %s",
+		"convert.error":                 "CSV変換エラー: %v",
+		"convert.writing":               "JIRA CSVとして保存しています: %s",
+		"convert.write_error":           "JIRA CSV書き込みエラー: %v",
+		"convert.appending":             "JIRA CSVに追記しています: %s",
+		"convert.appended":              "%d 行を追記しました（重複のためスキップ: %d 行）",
+		"convert.append_format":         "-append は -output-format=csv の場合のみ指定できます",
+		"convert.append_existing":       "JIRA CSVファイル '%s' に追記します",
+		"convert.append_incompatible":   "既存のJIRA CSV (%s) のヘッダーと互換性がありません（不足しているカラム: %s）。-append を外して作成し直すか、同じ設定で変換してください",
+		"convert.append_no_header":      "既存のJIRA CSV (%s) にヘッダーがありません",
+		"convert.append_no_id":          "既存のJIRA CSV (%s) に JIRA Issue ID のカラムがありません",
+		"convert.append_duplicates":     "JIRA Issue ID が既存の行（または先に追記する行）と重複する %d 行は追記しません",
+		"convert.append_nothing":        "追記する行はありません",
+		"convert.append_done":           "CSV追記完了: %d 行",
+		"convert.invalid_output_format": "-output-format が不正です: %s（%s のいずれかを指定してください）",
+		"convert.finished":              "CSV変換が完了しました: %d 件のレコードを処理しました。処理時間: %s",
+		"coverage.states":               "%d 件のステータスがマッピングされていません（JIRAのステータスは変更されません）: %s",
+		"coverage.types":                "%d 件の種別がマッピングされていません（DEFAULT_ISSUE_TYPE で作成されます）: %s",
+		"coverage.users":                "%d 人のユーザーがマッピングされていません（説明文に追記されます）: %s",
+		"coverage.rows":                 "%s(%d 行)",
+		"coverage.unestimated":          "見積もりのない行: %d 行（ストーリーポイントは設定しません）",
+		"coverage.complete":             "すべてのステータス・種別・ユーザーをマッピングできました: %d 行",
+
+		"preview.row":       "行 %d (Pivotal ID: %s, 作成後のステータス: %s)",
+		"preview.row_error": "行 %d のペイロードを作成できません: %v",
+		"preview.done":      "%d 件のペイロードを表示しました（全 %d 件）",
+
+		"json_export.reading":  "JIRA CSVを読み込んでいます: %s",
+		"json_export.error":    "JSONエクスポートエラー: %v",
+		"json_export.finished": "JSONインポートファイルを出力しました: %s (イシュー %d 件, バージョン %d 件)。処理時間: %s",
+
+		"import.start":                  "JIRAイシューのインポートを開始します",
+		"import.start_cli":              "JIRAイシューのインポートを開始します...",
+		"import.start_count":            "イシューのインポートを開始します: %d 件",
+		"import.range":                  "行 %d 〜 %d のみインポートします（全 %d 行）",
+		"import.filtered":               "フィルター条件に一致しない %d 行をスキップしました",
+		"import.release_rows":           "リリースの %d 行はイシューではなくバージョンとして作成します",
+		"import.version_created":        "リリース '%s' をバージョンとして作成しました",
+		"import.create_version_failed":  "バージョン '%s' の作成に失敗しました: %v",
+		"import.list_versions_failed":   "既存バージョンの取得に失敗しました: %v",
+		"import.deferred_fields_failed": "作成後のフィールド更新失敗 %s: %v",
+		"import.journal_failed":         "ジャーナル記録失敗 %s: %v",
+		"import.empty_title":            "行 %d (Pivotal ID: %s): タイトルが空のため \"No Title\" で作成します",
+		"import.empty_titles":           "タイトルが空の行が %d 件あります（Pivotal ID: %s）。必要に応じて元データを修正してください",
+		"import.subtask_failed":         "サブタスク作成失敗 %s「%s」: %v",
+		"import.subtasks_done":          "サブタスクの作成が完了しました: 成功=%d, 失敗=%d",
+		"import.retry_row":              "行 %d: 前回失敗したレコードを再処理します",
+		"import.resume_row":             "作成済みのイシュー %s に、前回失敗したステータスの更新とコメントの追加を行います",
+		"import.row_failed":             "行 %d の処理に失敗: %v",
+		"import.row_done":               "行 %d の処理が完了: %s (%s)",
+		"import.skipping_steps":         "次の項目は設定しません（後から反映してください）: %s",
+		"import.phase_start":            "フェーズ %d/%d: %d 件を処理します",
+		"import.issue_reused":           "既存のイシュー %s を使用します（Pivotal ID: %s のラベルが付いています）",
+		"import.mapping_write_failed":   "マッピングJSONの書き出しに失敗しました: %v",
+		"import.done":                   "イシューのインポートが完了しました: 成功=%d（作成=%d, 更新=%d, 既存=%d）, 失敗=%d",
+		"import.aborted":                "失敗した行が MAX_ERRORS (%d) を超えたため中断しました。未処理の %d 行はスキップします。設定を見直して再実行してください",
+		"import.stopped":                "%v。未処理の %d 行はスキップします（処理済みの行の結果は書き出します）",
+		"import.aborted_auth":           "JIRAの認証に失敗したため中断しました。未処理の %d 行はスキップします。APIトークンを更新して再実行してください（作成済みの行のキーはCSVに書き込まれています）",
+		"import.auth_recheck":           "行 %d で認証エラー (401) が返されたため、認証を確認し直します",
+		"import.auth_expired":           "認証を確認し直しましたが失敗しました。APIトークンの有効期限が切れた可能性があります: %v",
+		"import.auth_warning":           "行 %d で認証エラー (401) が返されました。APIトークンの有効期限が切れた可能性があります（CONTINUE_ON_AUTH_WARNING のため続行します）",
+		"import.finished":               "JIRAイシューのインポートが完了しました。処理時間: %s",
+		"import.error":                  "イシューインポートエラー: %v",
+		"import.csv_not_found":          "JIRAインポート用CSVファイルが見つかりません: %s",
+		"import.run_csv_convert":        "先に csv_convert ツールを実行して、CSVを準備してください。",
+		"import.transition_calls_saved": "トランジションのキャッシュにより省略したAPI呼び出し: %d 回",
+		"import.tuned_concurrency":      "自動調整後の並列数: %d（上限 MAX_CONCURRENT: %d）",
+		"import.status_unchanged":       "目的のステータスに遷移できなかったイシュー (STATUS_UNCHANGED): %d 件",
+		"import.rank_start":             "イシューのランクを元の並び順に更新しています: %d 件",
+		"import.rank_failed":            "ランク更新失敗: %v",
+		"import.rank_done":              "イシューのランク更新が完了しました",
+		"import.status_failed":          "ステータス更新失敗 %s: %v",
+		"import.comment_failed":         "コメント追加失敗 %s: %v",
+		"import.comment_added":          "コメントをイシュー %s に追加しました",
+		"import.get_issue_failed":       "イシュー取得失敗 %s: %v",
+		"import.note_resolved":          "Pivotalでの受け入れ日時: %s",
+		"import.note_created":           "Pivotalでの作成日時: %s",
+		"import.note_other_owners":      "他の担当者: %s",
+		"import.verify_dates_failed":    "日付のフィールドを確認できませんでした: %v",
+		"import.verify_id_failed":       "Pivotal IDのフィールドを確認できませんでした: %v",
+		"import.verify_sp_failed":       "ストーリーポイントのフィールドを確認できませんでした: %v",
+		"import.issue_updated":          "既存イシュー %s を更新しました",
+		"import.diff_start":             "既存イシューとの差分を確認します（書き込みは行いません）...",
+		"import.diff_error":             "差分確認エラー: %v",
+
+		"attachments.start":             "添付ファイルのアップロードを開始します",
+		"attachments.start_cli":         "添付ファイルのアップロードを開始します...",
+		"attachments.start_folder":      "添付ファイルのアップロードを開始します: フォルダ=%s",
+		"attachments.issue_not_found":   "Pivotal ID %s に対応するJIRAイシューが見つかりません",
+		"attachments.read_folder_error": "フォルダ %s の読み取りエラー: %v",
+		"attachments.upload_failed":     "ファイル %s のアップロード失敗: %v",
+		"attachments.uploaded":          "ファイル %s をイシュー %s にアップロードしました",
+		"attachments.renamed":           "ファイル %s は %s という名前でアップロードします",
+		"attachments.skipped_existing":  "ファイル %s はイシュー %s に添付済みのためスキップします",
+		"attachments.stopped":           "%v。残りの添付ファイルはアップロードしません",
+		"attachments.existing_failed":   "イシュー %s の添付済みファイルを取得できませんでした（すべてアップロードします）: %v",
+		"attachments.done":              "添付ファイルのアップロードが完了しました: 合計=%d, 成功=%d, 失敗=%d, スキップ=%d",
+		"attachments.finished":          "添付ファイルのアップロードが完了しました。処理時間: %s",
+		"attachments.error":             "添付ファイルアップロードエラー: %v",
+		"attachments.mapping_not_found": "JIRAイシューマッピングCSVファイルが見つかりません: %s",
+		"attachments.run_issue_import":  "先に issue_import ツールを実行して、JIRAイシューを作成してください。",
+		"attachments.folder_not_found":  "添付ファイルフォルダが見つかりません: %s",
+
+		"migration.done":                 "移行処理が完了しました",
+		"migration.finished":             "移行処理が完了しました。合計実行時間: %s",
+		"migration.failed":               "移行処理に失敗しました: %v",
+		"migration.summary_written":      "移行結果の集計を書き出しました: %s",
+		"migration.timing_header":        "所要時間の内訳:",
+		"migration.timing_phase":         "フェーズ",
+		"migration.timing_elapsed":       "経過時間",
+		"migration.timing_http":          "HTTP時間",
+		"migration.timing_note":          "HTTP時間は並列に送信したリクエストの合計のため、経過時間を超えることがあります",
+		"migration.summary_write_failed": "移行結果の集計ファイル書き込みに失敗しました: %v",
+	},
+	"en": {
+		"config.load_failed": "Failed to load configuration: %v",
+		"config.env_loaded":  "Loaded env files: %s",
+
+		// 繰り返される警告
+		"log.warn_suppressed": "The following warning was logged %d times; further occurrences are suppressed and counted at the end: %s",
+		"log.warn_repeated":   "The following warning was logged %d times in total: %s",
+
+		// Production confirmation
+		"confirm.production_prompt":  "About to write to a JIRA that looks like production (%s, project: %s). Continue?",
+		"confirm.production_skipped": "-yes given; writing to production-like JIRA (%s) without confirmation",
+		"confirm.production_aborted": "Aborted writing to production (use -yes to skip the confirmation)",
+		"config.loaded":              "Configuration loaded (Max Concurrent: %d)",
+
+		// Concurrency and rate limit
+		"config.concurrency_too_high": "Concurrency (%d) is too high for the rate limit (%.1f req/s). " +
+			"Extra requests only wait on the rate limit and waste goroutines (recommended: %d or less)",
+		"config.concurrency_clamped": "AUTO_CLAMP_CONCURRENCY is enabled; limiting concurrency to %d",
+
+		"auth.checking":                  "Checking JIRA credentials...",
+		"auth.checking_api":              "Checking JIRA API authentication...",
+		"auth.success":                   "JIRA authentication succeeded",
+		"auth.success_url":               "JIRA authentication succeeded! Connected to: %s",
+		"auth.error":                     "JIRA authentication error: %v",
+		"auth.hint_credentials":          "Check JIRA_EMAIL, JIRA_API_TOKEN (username and password for server) and JIRA_DEPLOYMENT. For 403, also check whether the account is locked (e.g. a CAPTCHA is required in the browser).",
+		"auth.hint_transient":            "JIRA is temporarily unreachable (server error, timeout or connection error). This is probably not a credentials problem; try again later. If it persists, check JIRA_URL and the proxy settings.",
+		"auth.hint_status":               "JIRA returned an unexpected response (HTTP %d). Check JIRA_URL and JIRA_CONTEXT_PATH.",
+		"auth.hint_url":                  "Check JIRA_URL, JIRA_CONTEXT_PATH and the certificate settings (JIRA_CA_CERT).",
+		"auth.display_name":              "  Display name: %s",
+		"auth.email":                     "  Email address: %s",
+		"auth.account_id":                "  Account ID: %s",
+		"auth.username":                  "  Username: %s",
+		"auth.checking_project":          "Checking JIRA project '%s'...",
+		"auth.project_error":             "JIRA project error: %v",
+		"auth.check_project_key":         "Please check JIRA_PROJECT_KEY.",
+		"auth.create_permission_unknown": "Could not verify the permission to create issues: %v",
+		"auth.create_permission_denied":  "This account does not have permission to create issues in project '%s'.",
+		"auth.create_permission_ok":      "Permission to create issues in project '%s': granted",
+		"auth.all_ok":                    "JIRA API credentials and project settings are valid.",
+
+		"tool.all_in_one":        "Pivotal → JIRA migration tool (%s)",
+		"tool.auth_check":        "JIRA authentication check tool",
+		"tool.csv_convert":       "Pivotal CSV → JIRA CSV conversion tool",
+		"tool.issue_import":      "JIRA issue import tool",
+		"tool.attachment_upload": "JIRA attachment upload tool",
+		"tool.doctor":            "Pre-migration diagnostics (target: %s)",
+		"tool.json_export":       "JIRA CSV → JIRA JSON import file export tool",
+		"tool.preview":           "Issue create payload preview tool",
+		"init.invalid_format":    "Invalid format: %s (use env or yaml)",
+		"init.exists":            "%s already exists. Use -force to overwrite it",
+		"init.write_failed":      "Failed to write the sample config: %v",
+		"init.written":           "Wrote the sample config to %s (%d keys)",
+
+		"doctor.auth_ok":                  "Authentication: logged in as %s",
+		"doctor.auth_failed":              "Authentication: %v",
+		"doctor.latency_ok":               "Latency: %s",
+		"doctor.latency_slow":             "Latency: %s (high latency may slow down the migration)",
+		"doctor.latency_hint":             "Check the proxy settings (JIRA_PROXY) and the network route",
+		"doctor.project_ok":               "Project: %s",
+		"doctor.project_failed":           "Project: %v",
+		"doctor.project_hint":             "Check JIRA_PROJECT_KEY or the -project flag",
+		"doctor.permission_hint":          "Grant the Create Issues permission in the project's permission scheme",
+		"doctor.createmeta_failed":        "Fetching issue types: %v",
+		"doctor.issue_type_ok":            "Issue type: %s",
+		"doctor.issue_type_missing":       "Issue type: %s does not exist in the project",
+		"doctor.issue_type_hint":          "Add it to the project's issue type scheme",
+		"doctor.story_point_ok":           "Story points: %s is a numeric field on the create screen",
+		"doctor.story_point_invalid":      "Story points: %v",
+		"doctor.story_point_hint":         "Check the field ID in JIRA_STORY_POINT_FIELD and the create screen configuration",
+		"doctor.pivotal_id_field_ok":      "Pivotal ID field: %s is a string field on the create screen",
+		"doctor.pivotal_id_field_invalid": "Pivotal ID field: %v",
+		"doctor.pivotal_id_field_hint":    "Check the field ID in PIVOTAL_ID_FIELD and the create screen configuration",
+		"doctor.failed":                   "Diagnosis finished: %d critical problems found",
+		"doctor.all_ok":                   "Diagnosis finished: no problems found",
+
+		"option.csv_file":            "Using CSV file: %s",
+		"option.input_file":          "Using input file: %s",
+		"option.output_file":         "Using output file: %s",
+		"option.attachments_folder":  "Using attachments folder: %s",
+		"option.concurrent":          "Using concurrency: %d",
+		"option.update_existing":     "Rows with a JIRA Issue Key will update the existing issue",
+		"option.no_transition_cache": "Transition cache disabled",
+		"option.project":             "Target JIRA project: %s",
+
+		"convert.start":                 "Starting CSV conversion",
+		"convert.done":                  "CSV conversion completed",
+		"convert.reading":               "Reading Pivotal CSV: %s",
+		"convert.read_error":            "Failed to read Pivotal CSV: %v",
+		"convert.read_done":             "Read Pivotal CSV: %d records",
+		"convert.converting":            "Converting to JIRA format...",
+		"convert.streaming":             "Converting Pivotal CSV while reading: %s -> %s",
+		"convert.error":                 "CSV conversion error: %v",
+		"convert.writing":               "Saving JIRA CSV: %s",
+		"convert.write_error":           "Failed to write JIRA CSV: %v",
+		"convert.appending":             "Appending to JIRA CSV: %s",
+		"convert.appended":              "Appended %d rows (skipped %d duplicates)",
+		"convert.append_format":         "-append can only be used with -output-format=csv",
+		"convert.append_existing":       "Appending to existing JIRA CSV file '%s'",
+		"convert.append_incompatible":   "Headers of the existing JIRA CSV (%s) are incompatible (missing columns: %s). Remove -append to recreate it, or convert with the same settings",
+		"convert.append_no_header":      "Existing JIRA CSV (%s) has no header",
+		"convert.append_no_id":          "Existing JIRA CSV (%s) has no JIRA Issue ID column",
+		"convert.append_duplicates":     "Not appending %d rows whose JIRA Issue ID duplicates an existing (or earlier appended) row",
+		"convert.append_nothing":        "No rows to append",
+		"convert.append_done":           "CSV append completed: %d rows",
+		"convert.invalid_output_format": "Invalid -output-format: %s (use one of %s)",
+		"convert.finished":              "CSV conversion completed: processed %d records in %s",
+		"coverage.states":               "%d states unmapped (JIRA status will not be changed): %s",
+		"coverage.types":                "%d types unmapped (created as DEFAULT_ISSUE_TYPE): %s",
+		"coverage.users":                "%d users unmapped (added to the description): %s",
+		"coverage.rows":                 "%s(%d rows)",
+		"coverage.unestimated":          "Unestimated rows: %d (story points are left unset)",
+		"coverage.complete":             "All states, types and users are mapped: %d rows",
+
+		"preview.row":       "Row %d (Pivotal ID: %s, status after creation: %s)",
+		"preview.row_error": "Cannot build the payload for row %d: %v",
+		"preview.done":      "Showed %d payloads (of %d records)",
+
+		"json_export.reading":  "Reading JIRA CSV: %s",
+		"json_export.error":    "JSON export error: %v",
+		"json_export.finished": "Wrote JSON import file: %s (%d issues, %d versions) in %s",
+
+		"import.start":                  "Starting JIRA issue import",
+		"import.start_cli":              "Starting JIRA issue import...",
+		"import.start_count":            "Starting issue import: %d rows",
+		"import.range":                  "Importing rows %d to %d only (of %d rows)",
+		"import.filtered":               "Skipped %d rows not matching the filter",
+		"import.release_rows":           "Creating %d release rows as versions instead of issues",
+		"import.version_created":        "Created release '%s' as a version",
+		"import.create_version_failed":  "Failed to create version '%s': %v",
+		"import.list_versions_failed":   "Failed to get existing versions: %v",
+		"import.deferred_fields_failed": "Failed to update fields after creating %s: %v",
+		"import.journal_failed":         "Failed to record %s in the journal: %v",
+		"import.empty_title":            "Row %d (Pivotal ID: %s): title is empty; creating it as \"No Title\"",
+		"import.empty_titles":           "%d rows have an empty title (Pivotal IDs: %s). Fix the source data if needed",
+		"import.subtask_failed":         "Failed to create subtask of %s \"%s\": %v",
+		"import.subtasks_done":          "Subtask creation completed: succeeded=%d, failed=%d",
+		"import.retry_row":              "Row %d: retrying previously failed record",
+		"import.resume_row":             "Resuming the status update and comments that failed last time on the already created issue %s",
+		"import.row_failed":             "Row %d failed: %v",
+		"import.row_done":               "Row %d completed: %s (%s)",
+		"import.skipping_steps":         "Skipping these steps (apply them later): %s",
+		"import.phase_start":            "Phase %d/%d: processing %d rows",
+		"import.issue_reused":           "Using existing issue %s (labelled with Pivotal ID %s)",
+		"import.mapping_write_failed":   "Failed to write mapping JSON: %v",
+		"import.done":                   "Issue import completed: succeeded=%d (created=%d, updated=%d, existing=%d), failed=%d",
+		"import.aborted":                "Aborted because failed rows exceeded MAX_ERRORS (%d); skipping %d unprocessed rows. Check the configuration and run again",
+		"import.stopped":                "%v; skipping %d unprocessed rows (results of processed rows are written)",
+		"import.aborted_auth":           "Aborted because JIRA authentication failed; skipping %d unprocessed rows. Refresh the API token and run again (keys of created rows are written to the CSV)",
+		"import.auth_recheck":           "Row %d returned an authentication error (401); re-checking authentication",
+		"import.auth_expired":           "Authentication re-check failed. The API token may have expired: %v",
+		"import.auth_warning":           "Row %d returned an authentication error (401). The API token may have expired (continuing because CONTINUE_ON_AUTH_WARNING is set)",
+		"import.finished":               "JIRA issue import completed in %s",
+		"import.error":                  "Issue import error: %v",
+		"import.csv_not_found":          "JIRA import CSV not found: %s",
+		"import.run_csv_convert":        "Run the csv_convert tool first to prepare the CSV.",
+		"import.transition_calls_saved": "API calls saved by the transition cache: %d",
+		"import.tuned_concurrency":      "Auto-tuned concurrency: %d (MAX_CONCURRENT limit: %d)",
+		"import.status_unchanged":       "Issues that could not reach the target status (STATUS_UNCHANGED): %d",
+		"import.rank_start":             "Updating issue rank to the original order: %d issues",
+		"import.rank_failed":            "Failed to update rank: %v",
+		"import.rank_done":              "Issue rank update completed",
+		"import.status_failed":          "Failed to update status of %s: %v",
+		"import.comment_failed":         "Failed to add comment to %s: %v",
+		"import.comment_added":          "Added comment to issue %s",
+		"import.get_issue_failed":       "Failed to get issue %s: %v",
+		"import.note_resolved":          "Accepted in Pivotal: %s",
+		"import.note_created":           "Created in Pivotal: %s",
+		"import.note_other_owners":      "Other owners: %s",
+		"import.verify_dates_failed":    "Could not verify the date fields: %v",
+		"import.verify_id_failed":       "Could not verify the Pivotal ID field: %v",
+		"import.verify_sp_failed":       "Could not verify the story point field: %v",
+		"import.issue_updated":          "Updated existing issue %s",
+		"import.diff_start":             "Comparing with existing issues (no changes will be written)...",
+		"import.diff_error":             "Diff error: %v",
+
+		"attachments.start":             "Starting attachment upload",
+		"attachments.start_cli":         "Starting attachment upload...",
+		"attachments.start_folder":      "Starting attachment upload: folder=%s",
+		"attachments.issue_not_found":   "No JIRA issue found for Pivotal ID %s",
+		"attachments.read_folder_error": "Failed to read folder %s: %v",
+		"attachments.upload_failed":     "Failed to upload file %s: %v",
+		"attachments.uploaded":          "Uploaded file %s to issue %s",
+		"attachments.renamed":           "File %s will be uploaded as %s",
+		"attachments.skipped_existing":  "Skipping file %s: already attached to issue %s",
+		"attachments.stopped":           "%v; remaining attachments are not uploaded",
+		"attachments.existing_failed":   "Failed to fetch existing attachments of issue %s (uploading all files): %v",
+		"attachments.done":              "Attachment upload completed: total=%d, succeeded=%d, failed=%d, skipped=%d",
+		"attachments.finished":          "Attachment upload completed in %s",
+		"attachments.error":             "Attachment upload error: %v",
+		"attachments.mapping_not_found": "JIRA issue mapping CSV not found: %s",
+		"attachments.run_issue_import":  "Run the issue_import tool first to create JIRA issues.",
+		"attachments.folder_not_found":  "Attachments folder not found: %s",
+
+		"migration.done":                 "Migration completed",
+		"migration.finished":             "Migration completed. Total time: %s",
+		"migration.failed":               "Migration failed: %v",
+		"migration.summary_written":      "Wrote migration summary: %s",
+		"migration.timing_header":        "Time breakdown:",
+		"migration.timing_phase":         "Phase",
+		"migration.timing_elapsed":       "Elapsed",
+		"migration.timing_http":          "HTTP",
+		"migration.timing_note":          "HTTP time is summed over concurrent requests and may exceed the elapsed time",
+		"migration.summary_write_failed": "Failed to write migration summary: %v",
+	},
+}
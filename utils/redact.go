@@ -0,0 +1,34 @@
+package utils
+
+import (
+	"strings"
+	"sync"
+)
+
+var (
+	// secrets はログから除去する秘密情報の一覧です
+	secrets      []string
+	secretsMutex sync.RWMutex
+)
+
+// RegisterSecret はログに出力してはならない文字列を登録します
+func RegisterSecret(secret string) {
+	if secret == "" {
+		return
+	}
+
+	secretsMutex.Lock()
+	defer secretsMutex.Unlock()
+	secrets = append(secrets, secret)
+}
+
+// Redact は登録された秘密情報を *** に置き換えます
+func Redact(message string) string {
+	secretsMutex.RLock()
+	defer secretsMutex.RUnlock()
+
+	for _, secret := range secrets {
+		message = strings.ReplaceAll(message, secret, "***")
+	}
+	return message
+}
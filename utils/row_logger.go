@@ -0,0 +1,94 @@
+package utils
+
+import "sync"
+
+// RowLogger は1行（1レコード）分の処理のログを出力します
+// バッファーモードの場合はすぐに出力せずに溜めておき、OrderedFlusher から行順に出力します
+type RowLogger struct {
+	buffered bool
+	entries  []rowLogEntry
+}
+
+type rowLogEntry struct {
+	level int
+	msg   string
+}
+
+// NewRowLogger は行ごとのロガーを作成します
+// buffered が false の場合は通常の LogInfo などと同じくすぐに出力します
+func NewRowLogger(buffered bool) *RowLogger {
+	return &RowLogger{buffered: buffered}
+}
+
+// Debug はデバッグレベルのメッセージを記録します
+func (l *RowLogger) Debug(format string, v ...interface{}) { l.log(LevelDebug, format, v...) }
+
+// Info は情報レベルのメッセージを記録します
+func (l *RowLogger) Info(format string, v ...interface{}) { l.log(LevelInfo, format, v...) }
+
+// Warn は警告レベルのメッセージを記録します
+func (l *RowLogger) Warn(format string, v ...interface{}) { l.log(LevelWarn, format, v...) }
+
+// Error はエラーレベルのメッセージを記録します
+func (l *RowLogger) Error(format string, v ...interface{}) { l.log(LevelError, format, v...) }
+
+func (l *RowLogger) log(level int, format string, v ...interface{}) {
+	msg := sprintf(format, v...)
+	if l.buffered {
+		l.entries = append(l.entries, rowLogEntry{level: level, msg: msg})
+		return
+	}
+	logAt(level, msg)
+}
+
+// flush は溜めておいたメッセージを出力します
+func (l *RowLogger) flush() {
+	for _, e := range l.entries {
+		logAt(e.level, e.msg)
+	}
+	l.entries = nil
+}
+
+// logAt は指定したレベルでメッセージを出力します
+func logAt(level int, msg string) {
+	switch level {
+	case LevelDebug:
+		LogDebug(msg)
+	case LevelInfo:
+		LogInfo(msg)
+	case LevelWarn:
+		LogWarn(msg)
+	default:
+		LogError(msg)
+	}
+}
+
+// OrderedFlusher は並列に処理した行のログを、元の行順に並べ替えて出力します
+// 先行する行が終わるまで後続の行のログは保留されます
+type OrderedFlusher struct {
+	mu      sync.Mutex
+	next    int
+	pending map[int]*RowLogger
+}
+
+// NewOrderedFlusher は0番目の行から順に出力するフラッシャーを作成します
+func NewOrderedFlusher() *OrderedFlusher {
+	return &OrderedFlusher{pending: make(map[int]*RowLogger)}
+}
+
+// Done は pos 番目の行の処理が終わったことを通知し、出力可能になったログをまとめて出力します
+func (f *OrderedFlusher) Done(pos int, l *RowLogger) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.pending[pos] = l
+	for {
+		next, ok := f.pending[f.next]
+		if !ok {
+			return
+		}
+		next.flush()
+		delete(f.pending, f.next)
+		f.next++
+	}
+}
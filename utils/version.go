@@ -0,0 +1,20 @@
+package utils
+
+import "fmt"
+
+// ビルド情報はビルド時に -ldflags "-X" で埋め込みます
+//
+//	go build -ldflags "-X pivotaltojira/utils.Version=v1.1.0 -X pivotaltojira/utils.Commit=$(git rev-parse --short HEAD) -X pivotaltojira/utils.BuildDate=$(date -u +%Y-%m-%dT%H:%M:%SZ)" ./cmd/...
+var (
+	// Version はツールのバージョンです
+	Version = "v1.0.0"
+	// Commit はビルド元のコミットハッシュです
+	Commit = "unknown"
+	// BuildDate はビルド日時です
+	BuildDate = "unknown"
+)
+
+// VersionString はバージョン情報を1行の文字列で返します
+func VersionString() string {
+	return fmt.Sprintf("%s (commit: %s, build date: %s)", Version, Commit, BuildDate)
+}
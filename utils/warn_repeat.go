@@ -0,0 +1,56 @@
+package utils
+
+import "sync"
+
+// warnRepeats は同じ内容の警告の出力回数を数え、上限を超えた分を省略します
+// 大量の行で同じ警告（マッピングにないユーザーなど）が出る場合にログが埋もれないようにするためです
+var warnRepeats = struct {
+	mu     sync.Mutex
+	limit  int // 同じ警告を出力する最大回数（0の場合は省略しない）
+	counts map[string]int
+	order  []string // 上限を超えた警告（最初に上限を超えた順）
+}{counts: make(map[string]int)}
+
+// SetWarnRepeatLimit は同じ内容の警告を出力する最大回数を設定し、これまでの回数をリセットします
+// 上限を超えた警告は出力せず、ReportRepeatedWarnings でまとめて回数を出力します（0の場合は省略しない）
+func SetWarnRepeatLimit(limit int) {
+	warnRepeats.mu.Lock()
+	defer warnRepeats.mu.Unlock()
+
+	warnRepeats.limit = max(limit, 0)
+	warnRepeats.counts = make(map[string]int)
+	warnRepeats.order = nil
+}
+
+// allowWarn は警告を出力してよいかを返します
+// 上限に達した時点で、以降は省略することを1回だけ出力します
+func allowWarn(msg string) bool {
+	warnRepeats.mu.Lock()
+	defer warnRepeats.mu.Unlock()
+
+	if warnRepeats.limit == 0 {
+		return true
+	}
+
+	warnRepeats.counts[msg]++
+	switch n := warnRepeats.counts[msg]; {
+	case n <= warnRepeats.limit:
+		return true
+	case n == warnRepeats.limit+1:
+		warnRepeats.order = append(warnRepeats.order, msg)
+		WarnLogger.Print(T("log.warn_suppressed", warnRepeats.limit, msg))
+	}
+	return false
+}
+
+// ReportRepeatedWarnings は上限を超えて省略した警告ごとに、出力しなかった分を含む回数をまとめて出力し、回数をリセットします
+func ReportRepeatedWarnings() {
+	warnRepeats.mu.Lock()
+	defer warnRepeats.mu.Unlock()
+
+	for _, msg := range warnRepeats.order {
+		WarnLogger.Print(T("log.warn_repeated", warnRepeats.counts[msg], msg))
+	}
+	warnRepeats.counts = make(map[string]int)
+	warnRepeats.order = nil
+}